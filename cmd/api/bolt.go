@@ -0,0 +1,55 @@
+package main // Define the package name as "main" for the application entry point
+
+import (
+	"fmt" // Import fmt for printing results
+	"io"  // Import io for the output writer
+
+	"order-packs-calculator/internal/infrastructure/config"     // Import the config package for the repository settings
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for the bolt maintenance
+)
+
+// boltUsage describes the bolt subcommand
+const boltUsage = `usage: order-packs-calculator bolt check|compact
+  check    read the whole bolt file and report every inconsistency
+  compact  rewrite the bolt file without the space freed by earlier changes
+Both need the server to be stopped, since it keeps the file locked`
+
+// runBolt implements the bolt subcommand, which maintains the file of the bolt repository
+func runBolt(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) != 1 || (args[0] != "check" && args[0] != "compact") {
+		return fmt.Errorf("%s", boltUsage)
+	}
+	if cfg.RepositoryType != config.RepositoryBolt {
+		return fmt.Errorf("the bolt command needs repository.type %q, not %q", config.RepositoryBolt, cfg.RepositoryType)
+	}
+
+	if args[0] == "compact" {
+		before, after, err := repository.CompactBolt(cfg.RepositoryDSN)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Compacted %s from %d to %d bytes\n", cfg.RepositoryDSN, before, after)
+		return nil
+	}
+
+	db, err := repository.OpenBoltReadOnly(cfg.RepositoryDSN) // Checking must neither create nor change the file
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	check, err := repository.CheckBolt(db)
+	if err != nil {
+		return err
+	}
+	for _, tenant := range check.Tenants {
+		fmt.Fprintf(out, "%s\t%d pack-size versions\t%d changes\n", tenant.Tenant, tenant.Versions, tenant.Changes)
+	}
+	for _, problem := range check.Problems {
+		fmt.Fprintf(out, "Problem: %s\n", problem)
+	}
+	if len(check.Problems) > 0 { // Fail, so scripts notice
+		return fmt.Errorf("%d problem(s) found in %s", len(check.Problems), cfg.RepositoryDSN)
+	}
+	fmt.Fprintf(out, "%s is consistent\n", cfg.RepositoryDSN)
+	return nil
+}
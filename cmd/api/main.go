@@ -1,12 +1,19 @@
 package main // Define the package name as "main" for the application entry point
 
 import (
+	"context" // Import context for background workers
+	"fmt"     // Import fmt for formatting log messages
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"log" // Import the log package for logging errors
 	"order-packs-calculator/internal/presentation/http"
+	"os"   // Import os for the command-line arguments
+	"time" // Import time for the mirror interval
 
 	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for the web server
+	"order-packs-calculator/internal/domain"                    // Import the domain package for calculation policies
 	"order-packs-calculator/internal/infrastructure/config"     // Import the config package for loading configuration
+	"order-packs-calculator/internal/infrastructure/events"     // Import the events package for publishing domain events
 	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
 	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for data access
 	"order-packs-calculator/internal/service"                   // Import the service package for business logic
@@ -20,44 +27,284 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err) // Log the error and exit
 	}
 
+	// Run the migrate subcommand instead of the server when asked to
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("Migration failed: %v", err) // Log the error and exit
+		}
+		return
+	}
+
+	// Run the bolt subcommand, which maintains the bolt file while the server is stopped, when asked to
+	if len(os.Args) > 1 && os.Args[1] == "bolt" {
+		if err := runBolt(cfg, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("bolt failed: %v", err) // Log the error and exit
+		}
+		return
+	}
+
+	// Run the snapshot or restore subcommand against a running server instead of starting one when asked to
+	if len(os.Args) > 1 && (os.Args[1] == "snapshot" || os.Args[1] == "restore") {
+		if err := runSnapshotCommand(cfg, os.Args[1], os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("%s failed: %v", os.Args[1], err) // Log the error and exit
+		}
+		return
+	}
+
 	// Initialize the logger
 	logger := logging.NewLogger() // Create a new logger instance
 
-	// Initialize the in-memory repository with the default pack sizes from the config
-	repo := repository.NewInMemoryPackRepository(cfg.PackSizes)
+	// Connect to the configured storage and bring its schema up to date
+	store, err := openStorage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err) // Log the error and exit
+	}
+	defer store.Close()
+	applied, err := store.migrate()
+	if err != nil {
+		log.Fatalf("Failed to migrate storage: %v", err) // Log the error and exit
+	}
+	for _, migration := range applied {
+		logger.Info(fmt.Sprintf("Applied migration %04d_%s", migration.Version, migration.Name)) // Log each applied migration
+	}
+
+	// Resolve the configured tie-break policy
+	tieBreak, err := domain.ParseTieBreakPolicy(cfg.TieBreak)
+	if err != nil {
+		log.Fatalf("Invalid tie-break policy %q: %v", cfg.TieBreak, err) // Log the error and exit
+	}
+
+	// Initialize the in-process publisher; for local use every event is written to the log
+	publisher := events.NewInProcessPublisher()
+	publisher.Subscribe(events.NewLogPublisher(logger))
+
+	// Initialize the statistics of repository calls, reported at /metrics/repository
+	repoMetrics := repository.NewRepositoryMetrics()
+
+	// Initialize the services of every tenant; tenants share nothing but the publisher
+	tenants := service.NewTenantRegistry(config.DefaultTenantID)
+	snapshots := service.NewSnapshotUseCase() // Backs up and restores the state of every tenant
+	webhookSender := events.NewHTTPWebhookSender(cfg.WebhookTimeout)
+	var packSizesWriter *config.PackSizesWriter // Shared by the tenants, which all live in the one config file
+	if cfg.PersistPackSizes {
+		packSizesWriter = config.NewPackSizesWriter(cfg.ConfigFile)
+	}
+	for _, tenant := range cfg.Tenants {
+		// Initialize the tenant's pack repository; a new one starts with the tenant's pack sizes from the config
+		repo, err := store.packRepository(tenant)
+		if err != nil {
+			log.Fatalf("Failed to open pack repository of tenant %q: %v", tenant.ID, err) // Log the error and exit
+		}
+		repo = decoratePackRepository(cfg, repo, tenant.ID, repoMetrics) // Add the configured metrics, retries and cache
+
+		// Initialize the tenant's history of served calculations
+		history := repository.NewInMemoryCalculationRepository(cfg.CalculationHistoryLimit)
+
+		// Initialize the tenant's outbox of calculation events, kept in memory apart from the pack repository
+		// so calculations never wait for or add to its writes; the oldest are dropped while delivery fails
+		calculationEvents := repository.NewInMemoryOutboxRepository(cfg.CalculationEventsLimit)
+
+		// Initialize the service with the repository
+		options := []service.Option{
+			service.WithTieBreakPolicy(tieBreak),
+			service.WithApprovalRequired(cfg.RequireApproval),
+			service.WithEvents(cfg.EventsEnabled), // Written to the tenant's pack repository together with each change
+			service.WithTenant(tenant.ID),
+			service.WithPackSizeRules(service.PackSizeRules{MaxSizes: cfg.MaxPackSizes, MaxSize: cfg.MaxPackSize}),
+			service.WithHistory(history),
+		}
+		if cfg.EventsEnabled {
+			options = append(options, service.WithCalculationEvents(calculationEvents))
+		}
+		if packSizesWriter != nil { // Write changes back, so the in-memory catalogue starts from them after a restart
+			tenantID := tenant.ID
+			options = append(options, service.WithPackSizesMirror(func(packSizes []int) error {
+				return packSizesWriter.WritePackSizes(tenantID, packSizes)
+			}))
+		}
+		calculatePacksService := service.NewCalculatePacksUseCase(repo, options...)
+		// Keep the pack sizes in memory for as long as the repository reports every change to them
+		go calculatePacksService.WatchPackSizes(context.Background())
+		// Write scheduled changes back once they take effect; returns at once when nothing is written back
+		go calculatePacksService.RunPackSizesMirror(context.Background(), time.Second, func(err error) {
+			logger.Error("Failed to write scheduled pack sizes back", err) // Retried on the next run
+		})
+
+		// Initialize the webhook service, which pushes the tenant's events to partner URLs
+		webhookService := service.NewWebhookUseCase(
+			store.webhookRepository(tenant),
+			webhookSender,
+			service.WebhookRetryPolicy{MaxAttempts: cfg.WebhookMaxAttempts, Backoff: cfg.WebhookBackoff},
+		)
+		publisher.Subscribe(events.ForTenant(tenant.ID, webhookService)) // Webhooks pick the event types they want themselves
+		go webhookService.Run(context.Background())                      // Deliveries run until the process exits
 
-	// Initialize the service with the repository
-	calculatePacksService := service.NewCalculatePacksUseCase(repo)
+		// Initialize the reservation service, which holds the packs of an order in stock until it is confirmed
+		reservationService := service.NewReservationUseCase(repository.NewInMemoryInventoryRepository(), calculatePacksService, cfg.ReservationTTL)
+		go reservationService.Run(context.Background(), cfg.ReservationExpiryInterval, func(err error) {
+			logger.Error("Failed to release expired reservations", err) // Expired reservations are retried on the next run
+		})
 
-	// Initialize the controller with the service and logger
-	packController := http.NewPackController(calculatePacksService, logger)
+		// Initialize the quote service, which pins solutions to the pack-size version they used
+		quoteService := service.NewQuoteUseCase(repository.NewInMemoryQuoteRepository(), calculatePacksService, cfg.QuoteTTL)
+		if err := snapshots.Register(tenant.ID, calculatePacksService, quoteService); err != nil {
+			log.Fatalf("Invalid tenant %q: %v", tenant.ID, err) // Log the error and exit
+		}
+
+		err = tenants.Register(tenant.ID, tenant.APIKeys, service.TenantServices{
+			Packs: calculatePacksService,
+			// Initialize the approval workflow on top of the pack-size catalogue
+			Approvals: service.NewPackSizeApprovalUseCase(repository.NewInMemoryProposalRepository(), calculatePacksService),
+			Quotes:    quoteService,
+			Webhooks:  webhookService,
+			// Initialize the calculation history, which lists and replays past calculations
+			Calculations: service.NewCalculationHistoryUseCase(history, calculatePacksService),
+			Reservations: reservationService,
+			// Initialize the catalogue import and export on top of the pack-size catalogue
+			Catalogue: service.NewCatalogueUseCase(calculatePacksService),
+		})
+		if err != nil {
+			log.Fatalf("Invalid tenant %q: %v", tenant.ID, err) // Log the error and exit
+		}
+
+		// Deliver the tenant's events in the background: pack-size changes from the outbox in its pack
+		// repository, including those left undelivered by an earlier run, and calculations from theirs
+		if cfg.EventsEnabled {
+			for _, outbox := range []repository.OutboxRepository{repo, calculationEvents} {
+				relay := service.NewOutboxRelay(outbox, publisher, func(err error) {
+					logger.Error("Failed to publish domain events", err) // Failed events stay in the outbox and are retried
+				})
+				go relay.Run(context.Background(), cfg.OutboxRelayInterval)
+			}
+		}
+	}
+
+	// Initialize the controllers with the default tenant's services and logger;
+	// the tenancy middleware swaps in the services of the requested tenant
+	defaults, err := tenants.Services(config.DefaultTenantID)
+	if err != nil {
+		log.Fatalf("Failed to load default tenant: %v", err) // Log the error and exit
+	}
+	packController := http.NewPackController(defaults.Packs, logger)
+	proposalController := http.NewProposalController(defaults.Approvals, logger)
+	quoteController := http.NewQuoteController(defaults.Quotes, logger)
+	webhookController := http.NewWebhookController(defaults.Webhooks, logger)
+	calculationController := http.NewCalculationController(defaults.Calculations, logger)
+	reservationController := http.NewReservationController(defaults.Reservations, logger)
+	catalogueController := http.NewCatalogueController(defaults.Catalogue, logger)
+
+	// Initialize the tenancy middleware, which resolves the tenant from X-Tenant or X-API-Key
+	tenancy := http.NewTenancy(tenants, logger)
+
+	// Initialize the audit trail and the middleware recording every mutating call
+	auditService := service.NewAuditUseCase(repository.NewInMemoryAuditRepository())
+	audit := http.NewAudit(auditService, logger)
+	auditController := http.NewAuditController(auditService, logger)
+
+	// Initialize the idempotency middleware so retried mutating requests are not executed twice
+	idempotency := http.NewIdempotency(repository.NewInMemoryIdempotencyRepository(), cfg.IdempotencyTTL, logger)
 
 	// Create a new Fiber application instance
 	app := fiber.New()
 
 	// Add CORS middleware to allow cross-origin requests
 	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "http://localhost:3000,http://localhost:63342", // Allow requests from both origins
-		AllowMethods:     "GET,POST,OPTIONS",                             // Include OPTIONS for preflight requests
-		AllowHeaders:     "Content-Type",                                 // Allow Content-Type header
-		AllowCredentials: false,                                          // Set to true if credentials (e.g., cookies) are needed
-		MaxAge:           86400,                                          // Cache preflight response for 24 hours
+		AllowOrigins:     "http://localhost:3000,http://localhost:63342",                                                                                                                                      // Allow requests from both origins
+		AllowMethods:     "GET,POST,DELETE,OPTIONS",                                                                                                                                                           // Include OPTIONS for preflight requests
+		AllowHeaders:     "Content-Type," + http.ActorHeader + "," + http.IdempotencyKeyHeader + "," + http.TenantHeader + "," + http.APIKeyHeader + "," + fiber.HeaderXRequestID + "," + fiber.HeaderIfMatch, // Allow Content-Type, actor, idempotency, tenant, request ID and If-Match headers
+		ExposeHeaders:    fiber.HeaderETag,                                                                                                                                                                    // Let the web UI read the pack-size version it must send back in If-Match
+		AllowCredentials: false,                                                                                                                                                                               // Set to true if credentials (e.g., cookies) are needed
+		MaxAge:           86400,                                                                                                                                                                               // Cache preflight response for 24 hours
 	}))
 
+	// Add a request ID to every request (or keep the caller's) so audit entries can be traced
+	app.Use(requestid.New())
+
 	// Serve static files from the ./web directory (for the UI)
 	app.Static("/", "./web")
 
 	// Create a group for API routes under the /api prefix
-	api := app.Group("/api")
-	// Define the POST /api/calculate endpoint for calculating packs
-	api.Post("/calculate", packController.CalculatePacks)
+	api := app.Group("/api", tenancy.Handle) // Every API request is scoped to a tenant
+	// Define the POST /api/calculate endpoint for calculating packs, audited only when configured
+	if cfg.AuditCalculations {
+		api.Post("/calculate", audit.Track("calculate", nil), packController.CalculatePacks)
+	} else {
+		api.Post("/calculate", packController.CalculatePacks)
+	}
 	// Define the POST /api/pack-sizes endpoint for updating pack sizes
-	api.Post("/pack-sizes", packController.UpdatePackSizes)
+	api.Post("/pack-sizes", audit.Track("pack_sizes.update", packController.PackSizesSnapshot), idempotency.Handle, packController.UpdatePackSizes)
 	// Define the GET /api/pack-sizes endpoint for retrieving pack sizes
 	api.Get("/pack-sizes", packController.GetPackSizes)
+	// Define the pack-size version endpoints for history, lookup and rollback
+	api.Get("/pack-sizes/history", packController.GetPackSizeHistory)
+	api.Get("/pack-sizes/scheduled", packController.GetScheduledPackSizes)
+	api.Get("/pack-sizes/versions/:id", packController.GetPackSizeVersion)
+	api.Post("/pack-sizes/versions/:id/rollback", audit.Track("pack_sizes.rollback", packController.PackSizesSnapshot), idempotency.Handle, packController.RollbackPackSizes)
+	// Define the pack-size proposal endpoints for the draft -> review -> published workflow
+	api.Post("/pack-size-proposals", audit.Track("proposal.create", nil), idempotency.Handle, proposalController.CreateProposal)
+	api.Get("/pack-size-proposals", proposalController.ListProposals)
+	api.Get("/pack-size-proposals/:id", proposalController.GetProposal)
+	api.Post("/pack-size-proposals/:id/submit", audit.Track("proposal.submit", proposalController.ProposalSnapshot), idempotency.Handle, proposalController.SubmitProposal)
+	api.Post("/pack-size-proposals/:id/approve", audit.Track("proposal.approve", proposalController.ProposalSnapshot), idempotency.Handle, proposalController.ApproveProposal)
+	api.Post("/pack-size-proposals/:id/reject", audit.Track("proposal.reject", proposalController.ProposalSnapshot), idempotency.Handle, proposalController.RejectProposal)
+	api.Post("/pack-size-proposals/:id/preview", proposalController.PreviewProposal)
+	// Define the quote endpoints
+	api.Post("/quotes", audit.Track("quote.create", nil), idempotency.Handle, quoteController.CreateQuote)
+	api.Get("/quotes/:id", quoteController.GetQuote)
+	// Define the webhook subscription endpoints
+	api.Post("/webhooks", audit.Track("webhook.register", nil), idempotency.Handle, webhookController.RegisterWebhook)
+	api.Get("/webhooks", webhookController.ListWebhooks)
+	api.Delete("/webhooks/:id", audit.Track("webhook.unregister", nil), webhookController.UnregisterWebhook)
+	api.Get("/webhooks/:id/deliveries", webhookController.ListWebhookDeliveries)
+	// Define the calculation history endpoints
+	api.Get("/calculations", calculationController.ListCalculations)
+	api.Get("/calculations/:id", calculationController.GetCalculation)
+	api.Post("/calculations/:id/replay", calculationController.ReplayCalculation)
+	// Define the inventory and reservation endpoints
+	api.Get("/inventory", reservationController.GetStock)
+	api.Post("/inventory", audit.Track("inventory.update", reservationController.StockSnapshot), idempotency.Handle, reservationController.SetStock)
+	api.Post("/reservations", audit.Track("reservation.create", nil), idempotency.Handle, reservationController.Reserve)
+	api.Get("/reservations", reservationController.ListReservations)
+	api.Get("/reservations/:id", reservationController.GetReservation)
+	api.Post("/reservations/:id/confirm", audit.Track("reservation.confirm", reservationController.ReservationSnapshot), idempotency.Handle, reservationController.ConfirmReservation)
+	api.Post("/reservations/:id/cancel", audit.Track("reservation.cancel", reservationController.ReservationSnapshot), idempotency.Handle, reservationController.CancelReservation)
+	// Define the catalogue export and import endpoints; an import replaces the pack sizes like POST /api/pack-sizes
+	api.Get("/catalogue/export", catalogueController.ExportCatalogue)
+	api.Post("/catalogue/import", audit.Track("catalogue.import", packController.PackSizesSnapshot), idempotency.Handle, catalogueController.ImportCatalogue)
+	// Define the audit trail endpoint
+	api.Get("/audit", auditController.ListAudit)
+
+	// Define the GET /metrics/repository endpoint outside /api; the statistics cover every tenant
+	if cfg.RepositoryMetrics {
+		app.Get("/metrics/repository", http.NewMetricsController(repoMetrics, logger).GetRepositoryMetrics)
+	}
+
+	// Define the snapshot and restore endpoints outside /api, only when an admin key is configured
+	if cfg.AdminAPIKey != "" {
+		snapshotController := http.NewSnapshotController(snapshots, logger)
+		admin := app.Group("/admin", http.NewAdmin(cfg.AdminAPIKey, logger).Handle)
+		admin.Get("/snapshot", snapshotController.GetSnapshot)
+		admin.Post("/restore", snapshotController.RestoreSnapshot)
+	}
 
 	// Start the Fiber server on the configured port
 	if err := app.Listen(cfg.Port); err != nil { // Start the server and handle any errors
 		log.Fatalf("Failed to start server: %v", err) // Log the error and exit
 	}
 }
+
+// decoratePackRepository wraps a tenant's pack repository in the decorators enabled in the configuration.
+// From the inside out: metrics time every call that reaches the store, including each retry; retries
+// repeat reads that fail with a transient error; the cache answers reads without reaching the store
+func decoratePackRepository(cfg *config.Config, repo repository.PackRepository, tenantID string, metrics *repository.RepositoryMetrics) repository.PackRepository {
+	if cfg.RepositoryMetrics {
+		repo = repository.NewMeteredPackRepository(repo, metrics, tenantID+".pack_sizes")
+	}
+	if cfg.RepositoryRetryAttempts > 1 { // One attempt means no retries
+		repo = repository.NewRetryingPackRepository(repo, cfg.RepositoryRetryAttempts, cfg.RepositoryRetryBackoff)
+	}
+	if cfg.RepositoryCacheTTL > 0 { // Zero disables the cache
+		repo = repository.NewCachingPackRepository(repo, cfg.RepositoryCacheTTL)
+	}
+	return repo
+}
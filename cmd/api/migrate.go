@@ -0,0 +1,67 @@
+package main // Define the package name as "main" for the application entry point
+
+import (
+	"fmt"  // Import fmt for printing the migration status
+	"io"   // Import io for the output writer
+	"time" // Import time for formatting when migrations were applied
+
+	"order-packs-calculator/internal/infrastructure/config" // Import the config package for the repository settings
+)
+
+// migrateUsage describes the migrate subcommand
+const migrateUsage = `usage: order-packs-calculator migrate [up|status]
+  up      apply pending schema migrations (default)
+  status  list every migration and whether it has been applied`
+
+// runMigrate implements the migrate subcommand, which manages the schema of the configured database
+func runMigrate(cfg *config.Config, args []string, out io.Writer) error {
+	command := "up" // Apply migrations when no command is given
+	if len(args) > 0 {
+		command = args[0]
+	}
+	if len(args) > 1 || (command != "up" && command != "status") {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	store, err := openStorage(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	migrator, err := store.migrator()
+	if err != nil {
+		return err
+	}
+	if migrator == nil { // Only database-backed repositories have a schema
+		fmt.Fprintf(out, "The %s repository has no schema to migrate\n", cfg.RepositoryType)
+		return nil
+	}
+
+	if command == "status" {
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+		return nil
+	}
+
+	applied, err := migrator.Migrate()
+	for _, migration := range applied { // Report what was applied even when a later migration failed
+		fmt.Fprintf(out, "Applied %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(out, "Schema is up to date")
+	}
+	return nil
+}
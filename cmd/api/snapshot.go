@@ -0,0 +1,172 @@
+package main // Define the package name as "main" for the application entry point
+
+import (
+	"bytes"         // Import bytes for reading error responses
+	"encoding/json" // Import json for decoding the restore summary
+	"flag"          // Import flag for the subcommand options
+	"fmt"           // Import fmt for printing results
+	"io"            // Import io for copying archives
+	"mime"          // Import mime for the archive name the server suggests
+	"net/http"      // Import net/http for calling the admin endpoints
+	"os"            // Import os for reading and writing archive files
+	"path/filepath" // Import filepath for keeping suggested names in the current directory
+	"sort"          // Import sort for printing tenants in order
+	"strings"       // Import strings for building the server address and listing state
+	"time"          // Import time for the request timeout
+
+	"order-packs-calculator/internal/infrastructure/config"          // Import the config package for the port and admin key
+	presentation "order-packs-calculator/internal/presentation/http" // Import the HTTP package for the admin header, renamed to keep net/http
+	"order-packs-calculator/internal/service"                        // Import the service package for the restore summary
+)
+
+// snapshotUsage describes the snapshot and restore subcommands
+const snapshotUsage = `usage: order-packs-calculator snapshot [-server URL] [file]
+       order-packs-calculator restore [-server URL] file
+  snapshot  save an archive of every tenant's state from a running server; without a file,
+            it is saved under the name the server suggests, and "-" writes it to stdout
+  restore   replace the state of a running server with an archive; "-" reads it from stdin
+The server defaults to this configuration's port on localhost; ADMIN_API_KEY must match the server's`
+
+// snapshotTimeout bounds a whole snapshot or restore request, archive transfer included
+const snapshotTimeout = 5 * time.Minute
+
+// runSnapshotCommand implements the snapshot and restore subcommands, which call the admin endpoints
+// of a running server so the archive is consistent with what that server is serving
+func runSnapshotCommand(cfg *config.Config, command string, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(io.Discard) // The usage below is returned as the error instead
+	server := flags.String("server", defaultServerURL(cfg.Port), "base URL of the running server")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 || (command == "restore" && flags.NArg() != 1) {
+		return fmt.Errorf("%s", snapshotUsage)
+	}
+	if cfg.AdminAPIKey == "" {
+		return fmt.Errorf("ADMIN_API_KEY is not set; it must match the key of the server")
+	}
+
+	client := &http.Client{Timeout: snapshotTimeout}
+	baseURL := strings.TrimRight(*server, "/")
+	if command == "snapshot" {
+		return saveSnapshot(client, baseURL, cfg.AdminAPIKey, flags.Arg(0), out)
+	}
+	return restoreSnapshot(client, baseURL, cfg.AdminAPIKey, flags.Arg(0), out)
+}
+
+// defaultServerURL returns the address a server listening on port is reached at from the same host
+func defaultServerURL(port string) string {
+	if strings.HasPrefix(port, ":") { // Listening on every interface
+		return "http://localhost" + port
+	}
+	return "http://" + port
+}
+
+// saveSnapshot downloads an archive and writes it to path
+func saveSnapshot(client *http.Client, baseURL, key, path string, out io.Writer) error {
+	resp, err := adminRequest(client, http.MethodGet, baseURL+"/admin/snapshot", key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if path == "-" {
+		_, err := io.Copy(out, resp.Body)
+		return err
+	}
+	if path == "" { // Use the name the server suggests, but never outside the current directory
+		path = "snapshot.json.gz"
+		if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+			path = filepath.Base(params["filename"])
+		}
+	}
+
+	// Write next to the target and rename, so an interrupted download never leaves a truncated archive
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once the rename succeeded
+	size, err := io.Copy(tmp, resp.Body)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Saved snapshot to %s (%d bytes)\n", path, size)
+	return nil
+}
+
+// restoreSnapshot uploads the archive at path and prints what was restored
+func restoreSnapshot(client *http.Client, baseURL, key, path string, out io.Writer) error {
+	var archive io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		archive = file
+	}
+
+	resp, err := adminRequest(client, http.MethodPost, baseURL+"/admin/restore", key, archive)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var summary struct {
+		CreatedAt   time.Time                         `json:"createdAt"`
+		Tenants     map[string]service.RestoredTenant `json:"tenants"`
+		NotIncluded []string                          `json:"notIncluded"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return fmt.Errorf("reading restore summary: %w", err)
+	}
+	fmt.Fprintf(out, "Restored snapshot taken at %s\n", summary.CreatedAt.Format(time.RFC3339))
+	tenants := make([]string, 0, len(summary.Tenants))
+	for id := range summary.Tenants {
+		tenants = append(tenants, id)
+	}
+	sort.Strings(tenants)
+	for _, id := range tenants {
+		restored := summary.Tenants[id]
+		fmt.Fprintf(out, "%s\t%d pack-size versions\t%d quotes\n", id, restored.PackSizeVersions, restored.Quotes)
+	}
+	if len(summary.NotIncluded) > 0 { // Make clear what the target server kept of its own
+		fmt.Fprintf(out, "Not restored: %s\n", strings.Join(summary.NotIncluded, ", "))
+	}
+	return nil
+}
+
+// adminRequest calls an admin endpoint and returns the response when it succeeded; otherwise the
+// error the server reported
+func adminRequest(client *http.Client, method, url, key string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(presentation.AdminKeyHeader, key)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/gzip")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var failure struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &failure) == nil && failure.Error != "" {
+		return nil, fmt.Errorf("server answered %s: %s", resp.Status, failure.Error)
+	}
+	return nil, fmt.Errorf("server answered %s: %s", resp.Status, bytes.TrimSpace(data))
+}
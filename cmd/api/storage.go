@@ -0,0 +1,153 @@
+package main // Define the package name as "main" for the application entry point
+
+import (
+	"database/sql"  // Import database/sql for the shared database connection
+	"fmt"           // Import fmt for configuration errors
+	"path/filepath" // Import filepath for repository file paths
+
+	"github.com/redis/go-redis/v9"                              // Import go-redis for the Redis connection
+	bolt "go.etcd.io/bbolt"                                     // Import bbolt for the embedded key-value file
+	"order-packs-calculator/internal/infrastructure/config"     // Import the config package for the repository settings
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for data access
+)
+
+// storage holds the connection of the configured repository type and creates the repositories of each tenant
+type storage struct {
+	cfg  *config.Config // Configuration selecting the repository type
+	db   *sql.DB        // Database shared by all tenants; nil for the memory, file, bolt and Redis types
+	bolt *bolt.DB       // Bolt file shared by all tenants; nil for the other types
+
+	redis      *redis.Client              // Redis connection shared by all tenants; nil for the other types
+	redisStore *repository.RedisPackStore // Listens for pack-size changes made by other replicas
+}
+
+// openStorage connects to the repository selected in the configuration; it does not migrate the schema
+func openStorage(cfg *config.Config) (*storage, error) {
+	s := &storage{cfg: cfg}
+	switch cfg.RepositoryType {
+	case config.RepositoryMemory, config.RepositoryFile:
+		return s, nil // Nothing to connect to
+	case config.RepositorySQLite:
+		db, err := repository.OpenSQLite(cfg.RepositoryDSN)
+		if err != nil {
+			return nil, err
+		}
+		s.db = db
+		return s, nil
+	case config.RepositoryBolt:
+		db, err := repository.OpenBolt(cfg.RepositoryDSN)
+		if err != nil {
+			return nil, err
+		}
+		s.bolt = db
+		return s, nil
+	case config.RepositoryPostgres:
+		if cfg.RepositoryDSN == "" {
+			return nil, fmt.Errorf("repository.dsn is required for the %s repository", cfg.RepositoryType)
+		}
+		db, err := repository.OpenPostgres(cfg.RepositoryDSN, repository.PoolOptions{
+			MaxOpenConns:    cfg.RepositoryMaxOpenConns,
+			MaxIdleConns:    cfg.RepositoryMaxIdleConns,
+			ConnMaxLifetime: cfg.RepositoryConnMaxLifetime,
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.db = db
+		return s, nil
+	case config.RepositoryRedis:
+		if cfg.RepositoryDSN == "" {
+			return nil, fmt.Errorf("repository.dsn is required for the %s repository", cfg.RepositoryType)
+		}
+		client, err := repository.OpenRedis(cfg.RepositoryDSN, repository.PoolOptions{
+			MaxOpenConns:    cfg.RepositoryMaxOpenConns,
+			MaxIdleConns:    cfg.RepositoryMaxIdleConns,
+			ConnMaxLifetime: cfg.RepositoryConnMaxLifetime,
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Subscribe before any repository is created, so no change made by another replica is missed
+		store, err := repository.NewRedisPackStore(client)
+		if err != nil {
+			client.Close()
+			return nil, err
+		}
+		s.redis, s.redisStore = client, store
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown repository type %q", cfg.RepositoryType)
+	}
+}
+
+// migrator returns the schema migrator of the database, or nil when the repository type has no schema
+func (s *storage) migrator() (*repository.Migrator, error) {
+	switch s.cfg.RepositoryType {
+	case config.RepositorySQLite:
+		migrations, err := repository.SQLiteMigrations()
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewMigrator(s.db, repository.SQLiteDialect, migrations), nil
+	case config.RepositoryPostgres:
+		migrations, err := repository.PostgresMigrations()
+		if err != nil {
+			return nil, err
+		}
+		return repository.NewMigrator(s.db, repository.PostgresDialect, migrations), nil
+	default:
+		return nil, nil
+	}
+}
+
+// migrate applies the pending schema migrations and returns the ones it applied
+func (s *storage) migrate() ([]repository.Migration, error) {
+	migrator, err := s.migrator()
+	if err != nil || migrator == nil {
+		return nil, err
+	}
+	return migrator.Migrate()
+}
+
+// packRepository creates the pack repository of a tenant; a new one starts with the tenant's configured pack sizes
+func (s *storage) packRepository(tenant config.TenantConfig) (repository.PackRepository, error) {
+	switch s.cfg.RepositoryType {
+	case config.RepositoryFile:
+		// Each tenant gets its own directory so later file-backed stores can sit next to the pack sizes
+		return repository.NewFilePackRepository(filepath.Join(s.cfg.RepositoryPath, tenant.ID, "pack_sizes.json"), tenant.PackSizes)
+	case config.RepositorySQLite:
+		return repository.NewSQLitePackRepository(s.db, tenant.ID, tenant.PackSizes)
+	case config.RepositoryBolt:
+		return repository.NewBoltPackRepository(s.bolt, tenant.ID, tenant.PackSizes)
+	case config.RepositoryPostgres:
+		return repository.NewPostgresPackRepository(s.db, tenant.ID, tenant.PackSizes)
+	case config.RepositoryRedis:
+		return s.redisStore.Repository(tenant.ID, tenant.PackSizes)
+	default:
+		return repository.NewInMemoryPackRepository(tenant.PackSizes), nil
+	}
+}
+
+// webhookRepository creates the webhook store of a tenant; with Redis it is shared by every replica, otherwise
+// subscriptions live in the process that registered them
+func (s *storage) webhookRepository(tenant config.TenantConfig) repository.WebhookRepository {
+	if s.cfg.RepositoryType == config.RepositoryRedis {
+		return repository.NewRedisWebhookRepository(s.redis, tenant.ID)
+	}
+	return repository.NewInMemoryWebhookRepository()
+}
+
+// Close stops listening for changes and closes the database, bolt file or Redis connection, if there is one
+func (s *storage) Close() error {
+	if s.redis != nil {
+		s.redisStore.Close()
+		return s.redis.Close()
+	}
+	if s.bolt != nil {
+		return s.bolt.Close()
+	}
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
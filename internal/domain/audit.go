@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records one audited request: who did what, from where, and what changed
+type AuditEntry struct {
+	ID         int             `json:"id"`
+	Tenant     string          `json:"tenant,omitempty"`
+	Action     string          `json:"action"`
+	Actor      string          `json:"actor"`
+	SourceIP   string          `json:"sourceIp"`
+	RequestID  string          `json:"requestId,omitempty"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	StatusCode int             `json:"statusCode"`
+	OldValue   json.RawMessage `json:"oldValue,omitempty"`
+	NewValue   json.RawMessage `json:"newValue,omitempty"`
+	At         time.Time       `json:"at"`
+}
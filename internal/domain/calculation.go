@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// CalculationRecord is a calculation as it was served: its input, the pack sizes used and the result
+type CalculationRecord struct {
+	ID              int           `json:"id"`
+	OrderAmount     int           `json:"orderAmount"`
+	PackSizeVersion int           `json:"packSizeVersion"`
+	PackSizes       []int         `json:"packSizes"`
+	Packs           map[int]int   `json:"packs,omitempty"`
+	TotalItems      int           `json:"totalItems"`
+	Error           string        `json:"error,omitempty"`
+	Duration        time.Duration `json:"durationNs"`
+	CalculatedAt    time.Time     `json:"calculatedAt"`
+}
+
+// DiffPacks returns, for every pack size whose quantity differs, the change from before to after
+func DiffPacks(before, after map[int]int) map[int]int {
+	diff := make(map[int]int)
+	for size, quantity := range after {
+		if delta := quantity - before[size]; delta != 0 {
+			diff[size] = delta
+		}
+	}
+	for size, quantity := range before {
+		if _, ok := after[size]; !ok {
+			diff[size] = -quantity
+		}
+	}
+	return diff
+}
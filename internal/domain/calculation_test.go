@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"testing" // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/assert" // Import testify/assert for assertions
+)
+
+// TestDiffPacks tests the per-size differences between two solutions
+func TestDiffPacks(t *testing.T) {
+	assert.Equal(t, map[int]int{}, DiffPacks(map[int]int{500: 1}, map[int]int{500: 1}), "Equal solutions should have no differences")
+	assert.Equal(t, map[int]int{250: -1, 500: 1, 1000: -1}, DiffPacks(
+		map[int]int{250: 1, 500: 1, 1000: 1},
+		map[int]int{500: 2},
+	), "Removed, added and changed sizes should be reported")
+	assert.Equal(t, map[int]int{500: 1}, DiffPacks(nil, map[int]int{500: 1}), "A missing original should count as empty")
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// EventType names a kind of domain event
+type EventType string
+
+const (
+	// EventPackSizesUpdated is emitted when a new pack-size version is saved
+	EventPackSizesUpdated EventType = "pack_sizes.updated"
+	// EventCalculationPerformed is emitted when packs are calculated for an order
+	EventCalculationPerformed EventType = "calculation.performed"
+)
+
+// Event is a typed domain event
+type Event interface {
+	EventType() EventType
+}
+
+// PackSizesUpdated records that a new pack-size version was saved
+type PackSizesUpdated struct {
+	Version PackSizeVersion `json:"version"` // The version that was saved
+}
+
+// EventType returns EventPackSizesUpdated
+func (PackSizesUpdated) EventType() EventType { return EventPackSizesUpdated }
+
+// CalculationPerformed records that packs were calculated for an order
+type CalculationPerformed struct {
+	OrderAmount     int         `json:"orderAmount"`     // Order amount that was calculated
+	PackSizeVersion int         `json:"packSizeVersion"` // Pack-size version used for the calculation
+	Packs           map[int]int `json:"packs"`           // Pack size -> quantity
+	TotalItems      int         `json:"totalItems"`      // Total items fulfilled
+}
+
+// EventType returns EventCalculationPerformed
+func (CalculationPerformed) EventType() EventType { return EventCalculationPerformed }
+
+// EventEnvelope is the serialised form of an event, as stored in the outbox and handed to publishers
+type EventEnvelope struct {
+	ID         int             `json:"id"`               // Sequential outbox ID, assigned when the event is stored
+	Type       EventType       `json:"type"`             // Kind of event
+	Tenant     string          `json:"tenant,omitempty"` // Tenant whose catalogue the event belongs to
+	OccurredAt time.Time       `json:"occurredAt"`       // When the event happened
+	Payload    json.RawMessage `json:"payload"`          // JSON-encoded event
+}
+
+// NewEventEnvelope serialises an event into an envelope
+func NewEventEnvelope(event Event, occurredAt time.Time) (EventEnvelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return EventEnvelope{}, err
+	}
+	return EventEnvelope{Type: event.EventType(), OccurredAt: occurredAt, Payload: payload}, nil
+}
+
+// ErrUnknownEventType is returned when an event type name is not recognised
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// EventTypes lists every event type the service emits
+func EventTypes() []EventType {
+	return []EventType{EventPackSizesUpdated, EventCalculationPerformed}
+}
+
+// ParseEventType converts a name into an EventType
+func ParseEventType(name string) (EventType, error) {
+	for _, eventType := range EventTypes() {
+		if string(eventType) == name {
+			return eventType, nil
+		}
+	}
+	return "", ErrUnknownEventType
+}
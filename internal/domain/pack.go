@@ -10,24 +10,60 @@ type Pack struct {
 	Size int
 }
 
+// TieBreakPolicy decides between solutions with equal total items and pack count
+type TieBreakPolicy string
+
+const (
+	// TieBreakPreferLarger keeps as many of the largest packs as possible
+	TieBreakPreferLarger TieBreakPolicy = "prefer_larger"
+	// TieBreakPreferSmaller keeps as many of the smallest packs as possible
+	TieBreakPreferSmaller TieBreakPolicy = "prefer_smaller"
+	// TieBreakFewestSizes uses the fewest distinct pack sizes, then prefers larger packs
+	TieBreakFewestSizes TieBreakPolicy = "fewest_sizes"
+)
+
+// DefaultTieBreakPolicy is used when no policy is configured
+const DefaultTieBreakPolicy = TieBreakPreferLarger
+
+// ParseTieBreakPolicy converts a configuration value into a TieBreakPolicy
+func ParseTieBreakPolicy(value string) (TieBreakPolicy, error) {
+	switch policy := TieBreakPolicy(value); policy {
+	case TieBreakPreferLarger, TieBreakPreferSmaller, TieBreakFewestSizes:
+		return policy, nil
+	case "":
+		return DefaultTieBreakPolicy, nil
+	default:
+		return "", ErrUnknownTieBreakPolicy
+	}
+}
+
 // CalculatePacks calculates the minimum packs needed to fulfill an order
 func CalculatePacks(packSizes []int, orderAmount int) (map[int]int, int, error) {
+	return CalculatePacksWithPolicy(packSizes, orderAmount, DefaultTieBreakPolicy)
+}
+
+// CalculatePacksWithPolicy calculates the minimum packs needed to fulfill an order,
+// using policy to choose between solutions with equal total items and pack count
+func CalculatePacksWithPolicy(packSizes []int, orderAmount int, policy TieBreakPolicy) (map[int]int, int, error) {
 	if orderAmount < 0 {
 		return nil, 0, ErrInvalidOrderAmount
 	}
 	if len(packSizes) == 0 {
 		return nil, 0, ErrNoPackSizes
 	}
+	if _, err := ParseTieBreakPolicy(string(policy)); err != nil {
+		return nil, 0, err
+	}
 
 	// Sort pack sizes in descending order
 	sortedSizes := make([]int, len(packSizes))
 	copy(sortedSizes, packSizes)
 	sort.Sort(sort.Reverse(sort.IntSlice(sortedSizes)))
 
-	// Remove invalid pack sizes (<= 0)
+	// Remove invalid (<= 0) and duplicate pack sizes
 	validSizes := []int{}
-	for _, size := range sortedSizes {
-		if size > 0 {
+	for i, size := range sortedSizes {
+		if size > 0 && (i == 0 || size != sortedSizes[i-1]) {
 			validSizes = append(validSizes, size)
 		}
 	}
@@ -40,74 +76,129 @@ func CalculatePacks(packSizes []int, orderAmount int) (map[int]int, int, error)
 	// dp[i] = minimum number of packs to reach amount i
 	maxAmount := orderAmount + sortedSizes[0] // Allow slight overshoot
 	dp := make([]int, maxAmount+1)
-	prev := make([]int, maxAmount+1) // Track the last pack size used
 	for i := range dp {
 		dp[i] = -1 // -1 means unreachable
 	}
 	dp[0] = 0 // Base case: 0 packs needed for amount 0
 
-	// First pass: Fill dp to find minimum packs
 	for i := 1; i <= maxAmount; i++ {
 		for _, size := range sortedSizes {
-			if i < size {
+			if i < size || dp[i-size] == -1 {
 				continue
 			}
-			if dp[i-size] != -1 {
-				packs := dp[i-size] + 1
-				if dp[i] == -1 || packs < dp[i] {
-					dp[i] = packs
-					prev[i] = size
-				}
+			if packs := dp[i-size] + 1; dp[i] == -1 || packs < dp[i] {
+				dp[i] = packs
 			}
 		}
 	}
 
-	// Find the smallest amount >= orderAmount that can be fulfilled
+	// The smallest reachable amount >= orderAmount wins; dp already holds its minimum pack count
 	bestTotalItems := -1
-	bestPackCount := -1
-	bestResult := make(map[int]int)
-
 	for amount := orderAmount; amount <= maxAmount; amount++ {
-		if dp[amount] == -1 {
-			continue
-		}
-		// Reconstruct the solution for this amount
-		result := make(map[int]int)
-		tempAmount := amount
-		for tempAmount > 0 {
-			size := prev[tempAmount]
-			result[size]++
-			tempAmount -= size
-		}
-		packCount := countPacks(result)
-		// Prioritize exact match, then minimize pack count, then minimize overage
-		if bestTotalItems == -1 ||
-			(amount == orderAmount && bestTotalItems != orderAmount) || // Exact match takes priority
-			(amount == bestTotalItems && packCount < bestPackCount) ||
-			(amount < bestTotalItems) {
+		if dp[amount] != -1 {
 			bestTotalItems = amount
-			bestPackCount = packCount
-			bestResult = result
+			break
 		}
 	}
-
 	if bestTotalItems == -1 {
 		return nil, 0, ErrInsufficientPackSizes
 	}
 
-	return bestResult, bestTotalItems, nil
+	// Every pack-count-optimal solution for bestTotalItems is a path through dp,
+	// so the policy only decides which path to walk
+	switch policy {
+	case TieBreakPreferSmaller:
+		ascendingSizes := make([]int, len(sortedSizes))
+		for i, size := range sortedSizes {
+			ascendingSizes[len(sortedSizes)-1-i] = size
+		}
+		return reconstruct(dp, ascendingSizes, bestTotalItems, nil), bestTotalItems, nil
+	case TieBreakFewestSizes:
+		return fewestSizes(dp, sortedSizes, bestTotalItems), bestTotalItems, nil
+	default:
+		return reconstruct(dp, sortedSizes, bestTotalItems, nil), bestTotalItems, nil
+	}
+}
+
+// reconstruct walks dp back from amount, always taking the first size in order that
+// stays on an optimal path; reachable optionally restricts which amounts may be visited
+func reconstruct(dp []int, order []int, amount int, reachable []bool) map[int]int {
+	result := make(map[int]int)
+	for amount > 0 {
+		for _, size := range order {
+			if size > amount || dp[amount-size] != dp[amount]-1 {
+				continue
+			}
+			if reachable != nil && !reachable[amount-size] {
+				continue
+			}
+			result[size]++
+			amount -= size
+			break
+		}
+	}
+	return result
 }
 
-func countPacks(result map[int]int) int {
-	total := 0
-	for _, count := range result {
-		total += count
+// fewestSizes searches subsets of sortedSizes by increasing cardinality (larger sizes first)
+// for one that reaches amount with the optimal pack count
+func fewestSizes(dp []int, sortedSizes []int, amount int) map[int]int {
+	packCount := dp[amount]
+	reachable := make([]bool, amount+1) // Shared by every subset; each one overwrites it in full
+	reachable[0] = true
+	for cardinality := 1; cardinality <= len(sortedSizes); cardinality++ {
+		var result map[int]int
+		forEachSubset(sortedSizes, cardinality, func(subset []int) bool {
+			// subset is descending: skip sets that cannot possibly hit amount in packCount packs
+			if subset[0]*packCount < amount || subset[len(subset)-1]*packCount > amount {
+				return true
+			}
+			for i := 1; i <= amount; i++ {
+				reachable[i] = false // Clear what the previous subset left behind
+				for _, size := range subset {
+					if size <= i && reachable[i-size] && dp[i-size] == dp[i]-1 {
+						reachable[i] = true
+						break
+					}
+				}
+			}
+			if !reachable[amount] {
+				return true
+			}
+			result = reconstruct(dp, subset, amount, reachable)
+			return false
+		})
+		if result != nil {
+			return result
+		}
+	}
+	return reconstruct(dp, sortedSizes, amount, nil) // Unreachable: the full set always works
+}
+
+// forEachSubset calls visit with every subset of sizes of the given cardinality in
+// lexicographic index order, stopping early when visit returns false
+func forEachSubset(sizes []int, cardinality int, visit func(subset []int) bool) {
+	subset := make([]int, 0, cardinality)
+	var walk func(start int) bool
+	walk = func(start int) bool {
+		if len(subset) == cardinality {
+			return visit(subset)
+		}
+		for i := start; i <= len(sizes)-(cardinality-len(subset)); i++ {
+			subset = append(subset, sizes[i])
+			if !walk(i + 1) {
+				return false
+			}
+			subset = subset[:len(subset)-1]
+		}
+		return true
 	}
-	return total
+	walk(0)
 }
 
 var (
 	ErrInvalidOrderAmount    = errors.New("order amount cannot be negative")
 	ErrNoPackSizes           = errors.New("no pack sizes provided")
 	ErrInsufficientPackSizes = errors.New("pack sizes insufficient to fulfill order")
+	ErrUnknownTieBreakPolicy = errors.New("unknown tie-break policy")
 )
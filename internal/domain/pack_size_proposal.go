@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// ProposalStatus is the lifecycle state of a proposed pack-size change
+type ProposalStatus string
+
+const (
+	// ProposalDraft is a proposal that has been created but not sent for review
+	ProposalDraft ProposalStatus = "draft"
+	// ProposalInReview is a proposal waiting for a second user to approve or reject it
+	ProposalInReview ProposalStatus = "in_review"
+	// ProposalPublished is an approved proposal whose pack sizes were saved as a version
+	ProposalPublished ProposalStatus = "published"
+	// ProposalRejected is a proposal that was turned down during review
+	ProposalRejected ProposalStatus = "rejected"
+	// ProposalExpired is a proposal whose effective date passed while it was in review
+	ProposalExpired ProposalStatus = "expired"
+	// ProposalSuperseded is a proposal whose base version was replaced by a newer one while it was in review
+	ProposalSuperseded ProposalStatus = "superseded"
+)
+
+// PackSizeProposal is a proposed pack-size change going through the draft -> review -> published lifecycle
+type PackSizeProposal struct {
+	ID               int            `json:"id"`                         // Sequential proposal number, starting at 1
+	PackSizes        []int          `json:"packSizes"`                  // Proposed pack sizes
+	EffectiveFrom    *time.Time     `json:"effectiveFrom,omitempty"`    // When the sizes should apply; nil means on approval
+	Author           string         `json:"author"`                     // Who proposed the change
+	BaseVersion      int            `json:"baseVersion"`                // Newest pack-size version when the proposal was created
+	Status           ProposalStatus `json:"status"`                     // Current lifecycle state
+	CreatedAt        time.Time      `json:"createdAt"`                  // When the draft was created
+	SubmittedAt      *time.Time     `json:"submittedAt,omitempty"`      // When the draft was sent for review
+	ReviewedBy       string         `json:"reviewedBy,omitempty"`       // Who approved, rejected or tried to approve the proposal
+	ReviewedAt       *time.Time     `json:"reviewedAt,omitempty"`       // When the proposal left review
+	RejectionReason  string         `json:"rejectionReason,omitempty"`  // Why the proposal was rejected
+	PublishedVersion int            `json:"publishedVersion,omitempty"` // Version created when the proposal was published
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// PackSizeVersion is an immutable snapshot of the pack sizes in use at some point in time
+type PackSizeVersion struct {
+	ID            int       `json:"id"`                   // Sequential version number, starting at 1
+	PackSizes     []int     `json:"packSizes"`            // Pack sizes of this version
+	Author        string    `json:"author"`               // Who created the version
+	ApprovedBy    string    `json:"approvedBy,omitempty"` // Who approved the change, if it went through review
+	CreatedAt     time.Time `json:"createdAt"`            // When the version was created
+	EffectiveFrom time.Time `json:"effectiveFrom"`        // When the version starts to apply; zero means always
+	RollbackOf    int       `json:"rollbackOf,omitempty"` // Version this one restores, if it is a rollback
+}
+
+// ActiveVersion returns the version in effect at the given time: the one with the latest
+// EffectiveFrom not after at, preferring the newer version when two start at the same time
+func ActiveVersion(versions []PackSizeVersion, at time.Time) (PackSizeVersion, error) {
+	var active *PackSizeVersion
+	for i := range versions {
+		version := &versions[i]
+		if version.EffectiveFrom.After(at) {
+			continue
+		}
+		if active == nil || version.EffectiveFrom.After(active.EffectiveFrom) ||
+			(version.EffectiveFrom.Equal(active.EffectiveFrom) && version.ID > active.ID) {
+			active = version
+		}
+	}
+	if active == nil {
+		return PackSizeVersion{}, ErrNoActiveVersion
+	}
+	return *active, nil
+}
+
+// UpcomingVersions returns the versions that take effect after now, soonest first
+func UpcomingVersions(versions []PackSizeVersion, now time.Time) []PackSizeVersion {
+	upcoming := []PackSizeVersion{}
+	for _, version := range versions {
+		if version.EffectiveFrom.After(now) {
+			upcoming = append(upcoming, version)
+		}
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].EffectiveFrom.Before(upcoming[j].EffectiveFrom)
+	})
+	return upcoming
+}
+
+// ErrNoActiveVersion is returned when no pack-size version is in effect at the requested time
+var ErrNoActiveVersion = errors.New("no pack sizes in effect at the requested time")
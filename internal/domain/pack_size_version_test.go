@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for effective dates
+
+	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+)
+
+// PackSizeVersionTestSuite defines the test suite for pack-size version resolution
+type PackSizeVersionTestSuite struct {
+	suite.Suite                   // Embed the testify suite
+	now         time.Time         // Reference time for the test
+	versions    []PackSizeVersion // Versions under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *PackSizeVersionTestSuite) SetupTest() {
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.versions = []PackSizeVersion{
+		{ID: 1, PackSizes: []int{250, 500}},                                         // Always in effect
+		{ID: 2, PackSizes: []int{100}, EffectiveFrom: s.now.Add(-48 * time.Hour)},   // Took effect two days ago
+		{ID: 3, PackSizes: []int{300}, EffectiveFrom: s.now.Add(72 * time.Hour)},    // Takes effect in three days
+		{ID: 4, PackSizes: []int{200}, EffectiveFrom: s.now.Add(24 * time.Hour)},    // Takes effect tomorrow
+		{ID: 5, PackSizes: []int{150}, EffectiveFrom: s.now.Add(-48 * time.Hour)},   // Replaces version 2 at the same time
+		{ID: 6, PackSizes: []int{175}, EffectiveFrom: s.now.Add(-72 * time.Hour)},   // Superseded by versions 2 and 5
+		{ID: 7, PackSizes: []int{125}, EffectiveFrom: s.now.Add(-48*time.Hour - 1)}, // Also superseded
+		{ID: 8, PackSizes: []int{400}, EffectiveFrom: s.now.Add(24*time.Hour + 1)},  // After version 4
+		{ID: 9, PackSizes: []int{450}, EffectiveFrom: s.now.Add(-96 * time.Hour)},   // Oldest dated version
+	}
+}
+
+// TestPackSizeVersionTestSuite runs the test suite
+func TestPackSizeVersionTestSuite(t *testing.T) {
+	suite.Run(t, new(PackSizeVersionTestSuite))
+}
+
+// TestActiveVersion tests resolving the version in effect at a given time
+func (s *PackSizeVersionTestSuite) TestActiveVersion() {
+	// Now: versions 2 and 5 share the latest past start; the newer one wins
+	active, err := ActiveVersion(s.versions, s.now)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(5, active.ID, "Newest version among equal start times should win")
+
+	// Back-dated: three days ago only version 6, 9 and the undated version applied
+	active, err = ActiveVersion(s.versions, s.now.Add(-72*time.Hour))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(6, active.ID, "Back-dated lookup should see the version in effect then")
+
+	// Future: the scheduled change applies once its start has passed
+	active, err = ActiveVersion(s.versions, s.now.Add(25*time.Hour))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(8, active.ID, "Future lookup should see scheduled versions")
+
+	// No version in effect at all
+	_, err = ActiveVersion([]PackSizeVersion{{ID: 1, EffectiveFrom: s.now}}, s.now.Add(-time.Second))
+	s.Assert().Equal(ErrNoActiveVersion, err, "Expected no active version")
+}
+
+// TestUpcomingVersions tests listing scheduled versions soonest first
+func (s *PackSizeVersionTestSuite) TestUpcomingVersions() {
+	upcoming := UpcomingVersions(s.versions, s.now)
+	ids := make([]int, len(upcoming))
+	for i, version := range upcoming {
+		ids[i] = version.ID
+	}
+	s.Assert().Equal([]int{4, 8, 3}, ids, "Upcoming versions should be ordered by effective date")
+}
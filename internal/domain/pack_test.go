@@ -62,3 +62,90 @@ func (s *PackTestSuite) TestCalculatePacks() {
 		})
 	}
 }
+
+// TestCalculatePacksWithPolicy tests that each tie-break policy picks a stable solution
+func (s *PackTestSuite) TestCalculatePacksWithPolicy() {
+	tests := []struct { // Define a slice of test cases
+		name          string         // Name of the test case for better reporting
+		packSizes     []int          // Input pack sizes for the test
+		orderAmount   int            // Input order amount for the test
+		policy        TieBreakPolicy // Tie-break policy under test
+		expected      map[int]int    // Expected result map (pack size -> quantity)
+		expectedTotal int            // Expected total items fulfilled
+	}{
+		{ // 6+6+13 and 5+10+10 both use three packs
+			name:          "Prefer larger packs",
+			packSizes:     []int{5, 6, 8, 10, 13},
+			orderAmount:   25,
+			policy:        TieBreakPreferLarger,
+			expected:      map[int]int{13: 1, 6: 2},
+			expectedTotal: 25,
+		},
+		{
+			name:          "Prefer smaller packs",
+			packSizes:     []int{5, 6, 8, 10, 13},
+			orderAmount:   25,
+			policy:        TieBreakPreferSmaller,
+			expected:      map[int]int{10: 2, 5: 1},
+			expectedTotal: 25,
+		},
+		{ // 4x4, 3+4+4+5 and 3+3+5+5 all use four packs
+			name:          "Fewest distinct sizes",
+			packSizes:     []int{3, 4, 5},
+			orderAmount:   16,
+			policy:        TieBreakFewestSizes,
+			expected:      map[int]int{4: 4},
+			expectedTotal: 16,
+		},
+		{
+			name:          "Fewest distinct sizes falls back to larger packs",
+			packSizes:     []int{5, 6, 8, 10, 13},
+			orderAmount:   38,
+			policy:        TieBreakFewestSizes,
+			expected:      map[int]int{13: 2, 6: 2},
+			expectedTotal: 38,
+		},
+		{ // The default policy must keep the historical answer
+			name:          "Edge case from hints with larger packs",
+			packSizes:     []int{23, 31, 53},
+			orderAmount:   500000,
+			policy:        TieBreakPreferLarger,
+			expected:      map[int]int{53: 9429, 31: 7, 23: 2},
+			expectedTotal: 500000,
+		},
+		{ // Order is the same regardless of the input order of pack sizes
+			name:          "Unsorted input",
+			packSizes:     []int{10, 5, 13, 8, 6},
+			orderAmount:   25,
+			policy:        TieBreakPreferSmaller,
+			expected:      map[int]int{10: 2, 5: 1},
+			expectedTotal: 25,
+		},
+	}
+
+	for _, tt := range tests { // Loop through each test case
+		s.Run(tt.name, func() {
+			result, total, err := CalculatePacksWithPolicy(tt.packSizes, tt.orderAmount, tt.policy)
+			s.Require().NoError(err, "Expected no error")
+			s.Assert().Equal(tt.expected, result, "Result should match expected")
+			s.Assert().Equal(tt.expectedTotal, total, "Total items should match expected")
+		})
+	}
+}
+
+// TestParseTieBreakPolicy tests parsing of configured tie-break policies
+func (s *PackTestSuite) TestParseTieBreakPolicy() {
+	policy, err := ParseTieBreakPolicy("fewest_sizes")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(TieBreakFewestSizes, policy, "Policy should match")
+
+	policy, err = ParseTieBreakPolicy("")
+	s.Assert().NoError(err, "Expected no error for empty policy")
+	s.Assert().Equal(DefaultTieBreakPolicy, policy, "Empty policy should fall back to the default")
+
+	_, err = ParseTieBreakPolicy("random")
+	s.Assert().Equal(ErrUnknownTieBreakPolicy, err, "Unknown policy should be rejected")
+
+	_, _, err = CalculatePacksWithPolicy([]int{250}, 10, "random")
+	s.Assert().Equal(ErrUnknownTieBreakPolicy, err, "Unknown policy should be rejected by the calculation")
+}
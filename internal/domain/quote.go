@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Quote is a pack calculation promised to a customer, honoured until it expires
+// even if the pack sizes change in the meantime
+type Quote struct {
+	ID              string      `json:"id"`              // Opaque quote identifier
+	OrderAmount     int         `json:"orderAmount"`     // Order amount that was quoted
+	PackSizeVersion int         `json:"packSizeVersion"` // Pack-size version the solution was calculated with
+	Packs           map[int]int `json:"packs"`           // Pack size -> quantity
+	TotalItems      int         `json:"totalItems"`      // Total items fulfilled
+	CreatedBy       string      `json:"createdBy"`       // Who requested the quote
+	CreatedAt       time.Time   `json:"createdAt"`       // When the quote was created
+	ExpiresAt       time.Time   `json:"expiresAt"`       // When the quote stops being honoured
+}
+
+// IsExpired reports whether the quote can no longer be honoured at the given time
+func (q Quote) IsExpired(now time.Time) bool {
+	return !now.Before(q.ExpiresAt)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// ReservationStatus is the lifecycle state of a stock reservation
+type ReservationStatus string
+
+const (
+	// ReservationHeld is a reservation whose packs are taken from stock until it is confirmed, cancelled or expires
+	ReservationHeld ReservationStatus = "held"
+	// ReservationConfirmed is a reservation whose packs were allocated to a confirmed order
+	ReservationConfirmed ReservationStatus = "confirmed"
+	// ReservationCancelled is a reservation whose packs were returned to stock on request
+	ReservationCancelled ReservationStatus = "cancelled"
+	// ReservationExpired is a reservation whose packs were returned to stock because it was not confirmed in time
+	ReservationExpired ReservationStatus = "expired"
+)
+
+// Reservation holds the packs chosen for an order so concurrent orders cannot allocate them too
+type Reservation struct {
+	ID              string            `json:"id"`                   // Opaque reservation identifier
+	OrderAmount     int               `json:"orderAmount"`          // Order amount the packs were calculated for
+	PackSizeVersion int               `json:"packSizeVersion"`      // Pack-size version the packs were calculated with
+	Packs           map[int]int       `json:"packs"`                // Pack size -> quantity taken from stock
+	TotalItems      int               `json:"totalItems"`           // Total items fulfilled
+	Status          ReservationStatus `json:"status"`               // Current lifecycle state
+	CreatedBy       string            `json:"createdBy"`            // Who reserved the packs
+	CreatedAt       time.Time         `json:"createdAt"`            // When the packs were reserved
+	ExpiresAt       time.Time         `json:"expiresAt"`            // When a held reservation is released unless confirmed
+	ResolvedBy      string            `json:"resolvedBy,omitempty"` // Who confirmed or cancelled the reservation
+	ResolvedAt      *time.Time        `json:"resolvedAt,omitempty"` // When the reservation left the held state
+}
+
+// IsExpired reports whether a held reservation has run out at the given time
+func (r Reservation) IsExpired(now time.Time) bool {
+	return r.Status == ReservationHeld && !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// WebhookSubscription registers a URL that receives events of the given types
+type WebhookSubscription struct {
+	ID         int         `json:"id"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"events"`
+	Secret     string      `json:"secret,omitempty"` // HMAC key, only returned when the subscription is created
+	CreatedBy  string      `json:"createdBy"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
+
+// Wants reports whether the subscription receives events of the given type
+func (s WebhookSubscription) Wants(eventType EventType) bool {
+	for _, wanted := range s.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records one attempt to deliver an event to a subscription
+type WebhookDelivery struct {
+	ID             int       `json:"id"`
+	SubscriptionID int       `json:"subscriptionId"`
+	EventID        int       `json:"eventId"`
+	EventType      EventType `json:"eventType"`
+	Attempt        int       `json:"attempt"`
+	StatusCode     int       `json:"statusCode,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Succeeded      bool      `json:"succeeded"`
+	AttemptedAt    time.Time `json:"attemptedAt"`
+}
@@ -1,9 +1,13 @@
 package config // Define the package name as "config" for configuration management
 
 import (
-	"log"     // Import the log package for logging
-	"strconv" // Import strconv to check if the port is a number
-	"strings" // Import the strings package for string manipulation
+	"log"           // Import the log package for logging
+	"os"            // Import os to check for environment overrides
+	"path/filepath" // Import filepath for the default database path
+	"sort"          // Import sort for ordering tenants
+	"strconv"       // Import strconv to check if the port is a number
+	"strings"       // Import the strings package for string manipulation
+	"time"          // Import time for durations
 
 	"github.com/spf13/viper" // Import the Viper library for configuration management
 )
@@ -12,6 +16,78 @@ import (
 type Config struct {
 	Port      string // Port on which the server will listen (e.g., ":3000")
 	PackSizes []int  // Default pack sizes for the application
+	TieBreak  string // Policy for choosing between equally good pack combinations
+
+	ConfigFile       string // Configuration file that was read; empty when there was none
+	PersistPackSizes bool   // Whether pack sizes changed at runtime are written back to ConfigFile
+
+	MaxPackSizes int // Maximum number of distinct sizes in a pack-size set
+	MaxPackSize  int // Largest pack size that may be configured
+
+	RequireApproval bool          // Whether pack-size changes must go through an approved proposal
+	QuoteTTL        time.Duration // How long customer quotes are honoured
+	IdempotencyTTL  time.Duration // How long responses are replayed for a repeated Idempotency-Key
+
+	EventsEnabled          bool          // Whether domain events are emitted and delivered
+	OutboxRelayInterval    time.Duration // How often pending domain events are delivered from the outbox
+	CalculationEventsLimit int           // Most undelivered calculation events kept per tenant; the oldest are dropped
+
+	WebhookMaxAttempts int           // Attempts per webhook delivery, including the first
+	WebhookBackoff     time.Duration // Delay before the first webhook retry; doubles for each further retry
+	WebhookTimeout     time.Duration // Timeout for a single webhook request
+
+	AuditCalculations bool // Whether every calculation is recorded in the audit trail, not only changes
+
+	AdminAPIKey string // Key the admin endpoints require in X-Admin-Key; empty disables them
+
+	CalculationHistoryLimit int // Most recent calculations kept per tenant for listing and replay
+
+	ReservationTTL            time.Duration // How long reserved packs are held before they return to stock
+	ReservationExpiryInterval time.Duration // How often expired reservations are released
+
+	RepositoryType string // Where pack sizes are stored: RepositoryMemory, RepositoryFile, RepositorySQLite, RepositoryBolt, RepositoryPostgres or RepositoryRedis
+	RepositoryPath string // Directory the file repository writes to, with one subdirectory per tenant
+	RepositoryDSN  string // Database to connect to; for SQLite and bolt the database file, by default packs.db or packs.bolt under RepositoryPath, for Redis its URL
+
+	RepositoryMaxOpenConns    int           // Most open connections in the Postgres or Redis pool
+	RepositoryMaxIdleConns    int           // Most idle connections kept in the Postgres or Redis pool
+	RepositoryConnMaxLifetime time.Duration // How long a Postgres or Redis connection is reused before it is replaced
+
+	RepositoryCacheTTL      time.Duration // How long pack sizes are cached in front of the repository; zero disables the cache
+	RepositoryMetrics       bool          // Whether the duration of every repository call is recorded
+	RepositoryRetryAttempts int           // Most attempts of a repository read that fails with a transient error
+	RepositoryRetryBackoff  time.Duration // Delay before the first repository retry; doubles for each further retry
+
+	Tenants []TenantConfig // Tenants with their own catalogues; always includes DefaultTenantID
+}
+
+const (
+	// RepositoryMemory keeps pack sizes in memory; changes are lost on restart
+	RepositoryMemory = "memory"
+	// RepositoryFile persists pack sizes to a JSON file per tenant
+	RepositoryFile = "file"
+	// RepositorySQLite persists pack sizes to a SQLite database shared by all tenants
+	RepositorySQLite = "sqlite"
+	// RepositoryBolt persists pack sizes to an embedded bolt key-value file shared by all tenants
+	RepositoryBolt = "bolt"
+	// RepositoryPostgres persists pack sizes to a Postgres database that several replicas can share
+	RepositoryPostgres = "postgres"
+	// RepositoryRedis persists pack sizes to Redis and notifies the other replicas of every change
+	RepositoryRedis = "redis"
+)
+
+// maxWebhookAttempts bounds webhook_max_attempts; with the backoff capped at an hour, 20 attempts already
+// keep retrying an event for more than half a day
+const maxWebhookAttempts = 20
+
+// DefaultTenantID is the tenant used for requests that do not name one
+const DefaultTenantID = "default"
+
+// TenantConfig holds the settings of one tenant
+type TenantConfig struct {
+	ID        string   // Tenant identifier, sent in the X-Tenant header
+	PackSizes []int    // Pack sizes the tenant's catalogue starts with
+	APIKeys   []string // API keys that select this tenant; when set, the tenant can only be reached with one
 }
 
 // LoadConfig loads the configuration using Viper
@@ -31,12 +107,68 @@ func LoadConfig() (*Config, error) {
 	v.AutomaticEnv() // Automatically read environment variables
 
 	// Bind specific environment variables to Viper keys
-	v.BindEnv("port", "PORT")             // Bind PORT environment variable to "port" key
-	v.BindEnv("pack_sizes", "PACK_SIZES") // Bind PACK_SIZES environment variable to "pack_sizes" key
+	v.BindEnv("port", "PORT")                                                 // Bind PORT environment variable to "port" key
+	v.BindEnv("pack_sizes", "PACK_SIZES")                                     // Bind PACK_SIZES environment variable to "pack_sizes" key
+	v.BindEnv("tie_break", "TIE_BREAK")                                       // Bind TIE_BREAK environment variable to "tie_break" key
+	v.BindEnv("persist_pack_sizes", "PERSIST_PACK_SIZES")                     // Bind PERSIST_PACK_SIZES environment variable to "persist_pack_sizes" key
+	v.BindEnv("require_approval", "REQUIRE_APPROVAL")                         // Bind REQUIRE_APPROVAL environment variable to "require_approval" key
+	v.BindEnv("quote_ttl", "QUOTE_TTL")                                       // Bind QUOTE_TTL environment variable to "quote_ttl" key
+	v.BindEnv("idempotency_ttl", "IDEMPOTENCY_TTL")                           // Bind IDEMPOTENCY_TTL environment variable to "idempotency_ttl" key
+	v.BindEnv("events_enabled", "EVENTS_ENABLED")                             // Bind EVENTS_ENABLED environment variable to "events_enabled" key
+	v.BindEnv("outbox_relay_interval", "OUTBOX_RELAY_INTERVAL")               // Bind OUTBOX_RELAY_INTERVAL environment variable to "outbox_relay_interval" key
+	v.BindEnv("calculation_events_limit", "CALCULATION_EVENTS_LIMIT")         // Bind CALCULATION_EVENTS_LIMIT environment variable to "calculation_events_limit" key
+	v.BindEnv("audit_calculations", "AUDIT_CALCULATIONS")                     // Bind AUDIT_CALCULATIONS environment variable to "audit_calculations" key
+	v.BindEnv("admin_api_key", "ADMIN_API_KEY")                               // Bind ADMIN_API_KEY environment variable to "admin_api_key" key
+	v.BindEnv("calculation_history_limit", "CALCULATION_HISTORY_LIMIT")       // Bind CALCULATION_HISTORY_LIMIT environment variable to "calculation_history_limit" key
+	v.BindEnv("reservation_ttl", "RESERVATION_TTL")                           // Bind RESERVATION_TTL environment variable to "reservation_ttl" key
+	v.BindEnv("reservation_expiry_interval", "RESERVATION_EXPIRY_INTERVAL")   // Bind RESERVATION_EXPIRY_INTERVAL environment variable to "reservation_expiry_interval" key
+	v.BindEnv("repository.type", "REPOSITORY_TYPE")                           // Bind REPOSITORY_TYPE environment variable to "repository.type" key
+	v.BindEnv("repository.path", "REPOSITORY_PATH")                           // Bind REPOSITORY_PATH environment variable to "repository.path" key
+	v.BindEnv("repository.dsn", "REPOSITORY_DSN")                             // Bind REPOSITORY_DSN environment variable to "repository.dsn" key
+	v.BindEnv("repository.max_open_conns", "REPOSITORY_MAX_OPEN_CONNS")       // Bind REPOSITORY_MAX_OPEN_CONNS environment variable to "repository.max_open_conns" key
+	v.BindEnv("repository.max_idle_conns", "REPOSITORY_MAX_IDLE_CONNS")       // Bind REPOSITORY_MAX_IDLE_CONNS environment variable to "repository.max_idle_conns" key
+	v.BindEnv("repository.conn_max_lifetime", "REPOSITORY_CONN_MAX_LIFETIME") // Bind REPOSITORY_CONN_MAX_LIFETIME environment variable to "repository.conn_max_lifetime" key
+	v.BindEnv("repository.cache_ttl", "REPOSITORY_CACHE_TTL")                 // Bind REPOSITORY_CACHE_TTL environment variable to "repository.cache_ttl" key
+	v.BindEnv("repository.metrics", "REPOSITORY_METRICS")                     // Bind REPOSITORY_METRICS environment variable to "repository.metrics" key
+	v.BindEnv("repository.retry_attempts", "REPOSITORY_RETRY_ATTEMPTS")       // Bind REPOSITORY_RETRY_ATTEMPTS environment variable to "repository.retry_attempts" key
+	v.BindEnv("repository.retry_backoff", "REPOSITORY_RETRY_BACKOFF")         // Bind REPOSITORY_RETRY_BACKOFF environment variable to "repository.retry_backoff" key
+	v.BindEnv("webhook_max_attempts", "WEBHOOK_MAX_ATTEMPTS")                 // Bind WEBHOOK_MAX_ATTEMPTS environment variable to "webhook_max_attempts" key
+	v.BindEnv("webhook_backoff", "WEBHOOK_BACKOFF")                           // Bind WEBHOOK_BACKOFF environment variable to "webhook_backoff" key
+	v.BindEnv("webhook_timeout", "WEBHOOK_TIMEOUT")                           // Bind WEBHOOK_TIMEOUT environment variable to "webhook_timeout" key
 
 	// Set default values
 	v.SetDefault("port", ":3000")                        // Default port if not specified
 	v.SetDefault("pack_sizes", "250,500,1000,2000,5000") // Default pack sizes as a comma-separated string
+	v.SetDefault("tie_break", "prefer_larger")           // Default tie-break policy
+	v.SetDefault("persist_pack_sizes", false)            // Keep runtime pack-size changes out of the config file by default
+	v.SetDefault("max_pack_sizes", 20)                   // Allow up to 20 distinct pack sizes by default
+	v.SetDefault("max_pack_size", 1000000)               // Allow pack sizes up to one million by default
+	v.SetDefault("require_approval", false)              // Allow direct pack-size changes by default
+	v.SetDefault("quote_ttl", "168h")                    // Honour quotes for a week by default
+	v.SetDefault("idempotency_ttl", "24h")               // Replay idempotent responses for a day by default
+	v.SetDefault("events_enabled", true)                 // Emit domain events by default
+	v.SetDefault("outbox_relay_interval", "1s")          // Deliver domain events every second by default
+	v.SetDefault("calculation_events_limit", 10000)      // Keep up to 10,000 undelivered calculation events per tenant by default
+	v.SetDefault("audit_calculations", false)            // Only audit changes by default
+	v.SetDefault("admin_api_key", "")                    // Disable the admin endpoints by default
+	v.SetDefault("calculation_history_limit", 10000)     // Keep the last 10,000 calculations per tenant by default
+	v.SetDefault("reservation_ttl", "15m")               // Hold reserved packs for 15 minutes by default
+	v.SetDefault("reservation_expiry_interval", "30s")   // Release expired reservations every 30 seconds by default
+	v.SetDefault("repository.type", RepositoryMemory)    // Keep pack sizes in memory by default
+	v.SetDefault("repository.path", "data")              // Write repository files under ./data by default
+	v.SetDefault("repository.max_open_conns", 10)        // Open at most ten database connections by default
+	v.SetDefault("repository.max_idle_conns", 5)         // Keep five idle database connections by default
+	v.SetDefault("repository.conn_max_lifetime", "30m")  // Replace database connections every 30 minutes by default
+	v.SetDefault("repository.cache_ttl", "0s")           // Read the repository on every call by default
+	v.SetDefault("repository.metrics", true)             // Time repository calls by default
+	v.SetDefault("repository.retry_attempts", 3)         // Try failing repository reads three times by default
+	v.SetDefault("repository.retry_backoff", "50ms")     // Wait 50ms, then 100ms between repository retries by default
+	v.SetDefault("webhook_max_attempts", 5)              // Try each webhook delivery five times by default
+	v.SetDefault("webhook_backoff", "1s")                // Wait 1s, 2s, 4s, ... between webhook retries by default
+	v.SetDefault("webhook_timeout", "10s")               // Give webhook receivers ten seconds by default
+
+	// Create a new Config instance
+	cfg := &Config{}
 
 	// Read the configuration file (if it exists)
 	if err := v.ReadInConfig(); err != nil { // Attempt to read the config file
@@ -46,11 +178,9 @@ func LoadConfig() (*Config, error) {
 		log.Println("Config file not found; falling back to environment variables or defaults")
 	} else {
 		log.Printf("Loaded configuration from file: %s", v.ConfigFileUsed()) // Log the config file path
+		cfg.ConfigFile = v.ConfigFileUsed()                                  // Remember the file, so pack sizes can be written back to it
 	}
 
-	// Create a new Config instance
-	cfg := &Config{}
-
 	// Load the port from Viper and ensure it's in the correct format
 	port := v.GetString("port") // Get the port value as a string
 	// If the port is a plain number (e.g., "3000"), prepend ":" to make it ":3000"
@@ -64,9 +194,201 @@ func LoadConfig() (*Config, error) {
 	// Load the pack sizes from Viper
 	packSizesStr := v.GetString("pack_sizes")                  // Get the pack sizes as a comma-separated string
 	log.Printf("Raw pack sizes from config: %s", packSizesStr) // Log the raw pack sizes string
-	sizes := strings.Split(packSizesStr, ",")                  // Split the string by commas
-	cfg.PackSizes = make([]int, 0, len(sizes))                 // Initialize the PackSizes slice
-	for _, size := range sizes {                               // Loop through each size string
+	cfg.PackSizes = parsePackSizes(packSizesStr)               // Parse and validate the sizes
+
+	// Ensure there are pack sizes (fall back to defaults if none were parsed)
+	if len(cfg.PackSizes) == 0 { // Check if the PackSizes slice is empty
+		log.Println("No valid pack sizes found; using default pack sizes: 250,500,1000,2000,5000")
+		cfg.PackSizes = []int{250, 500, 1000, 2000, 5000} // Set default pack sizes
+	} else {
+		log.Printf("Loaded pack sizes: %v", cfg.PackSizes) // Log the final pack sizes
+	}
+
+	// Load the tenants; each gets its own catalogue, seeded from its pack sizes or the top-level ones
+	cfg.Tenants = loadTenants(v, cfg.PackSizes)
+
+	// Load whether pack-size changes are written back; that needs a file to write them to
+	cfg.PersistPackSizes = v.GetBool("persist_pack_sizes")
+	if cfg.PersistPackSizes && cfg.ConfigFile == "" {
+		log.Println("Persisting pack sizes needs a config file; changes will not be written back")
+		cfg.PersistPackSizes = false
+	} else if cfg.PersistPackSizes {
+		log.Printf("Writing pack-size changes back to %s", cfg.ConfigFile) // Log where changes are written
+		// The variable wins over the file on every start, so the default tenant's changes would be ignored
+		if _, set := os.LookupEnv("PACK_SIZES"); set {
+			log.Println("PACK_SIZES is set, so the default tenant starts with it rather than the pack sizes written back")
+		}
+	}
+
+	// Load the tie-break policy; it is validated when the service is built
+	cfg.TieBreak = v.GetString("tie_break")
+	log.Printf("Using tie-break policy: %s", cfg.TieBreak) // Log the tie-break policy
+
+	// Load the limits for pack-size sets; invalid values fall back to the defaults
+	cfg.MaxPackSizes = v.GetInt("max_pack_sizes")
+	if cfg.MaxPackSizes <= 0 { // Check if the number could not be parsed or is not positive
+		log.Printf("Invalid max pack sizes %q; using default 20", v.GetString("max_pack_sizes"))
+		cfg.MaxPackSizes = 20
+	}
+	cfg.MaxPackSize = v.GetInt("max_pack_size")
+	if cfg.MaxPackSize <= 0 { // Check if the number could not be parsed or is not positive
+		log.Printf("Invalid max pack size %q; using default 1000000", v.GetString("max_pack_size"))
+		cfg.MaxPackSize = 1000000
+	}
+	log.Printf("Using pack-size limits: %d sizes, largest %d", cfg.MaxPackSizes, cfg.MaxPackSize) // Log the limits
+
+	// Load whether pack-size changes need a second user's approval
+	cfg.RequireApproval = v.GetBool("require_approval")
+	log.Printf("Pack-size changes require approval: %t", cfg.RequireApproval) // Log the approval setting
+
+	// Load the quote lifetime (e.g. "72h"); invalid values fall back to the default
+	cfg.QuoteTTL = v.GetDuration("quote_ttl")
+	if cfg.QuoteTTL <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid quote TTL %q; using default 168h", v.GetString("quote_ttl"))
+		cfg.QuoteTTL = 168 * time.Hour
+	}
+	log.Printf("Using quote TTL: %s", cfg.QuoteTTL) // Log the quote TTL
+
+	// Load the idempotency replay window; invalid values fall back to the default
+	cfg.IdempotencyTTL = v.GetDuration("idempotency_ttl")
+	if cfg.IdempotencyTTL <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid idempotency TTL %q; using default 24h", v.GetString("idempotency_ttl"))
+		cfg.IdempotencyTTL = 24 * time.Hour
+	}
+	log.Printf("Using idempotency TTL: %s", cfg.IdempotencyTTL) // Log the idempotency TTL
+
+	// Load how often the outbox relay runs; invalid values fall back to the default
+	cfg.OutboxRelayInterval = v.GetDuration("outbox_relay_interval")
+	if cfg.OutboxRelayInterval <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid outbox relay interval %q; using default 1s", v.GetString("outbox_relay_interval"))
+		cfg.OutboxRelayInterval = time.Second
+	}
+	log.Printf("Using outbox relay interval: %s", cfg.OutboxRelayInterval) // Log the relay interval
+
+	// Load whether domain events are emitted at all
+	cfg.EventsEnabled = v.GetBool("events_enabled")
+	log.Printf("Emitting domain events: %t", cfg.EventsEnabled) // Log the event setting
+
+	// Load how many undelivered calculation events are kept; invalid values fall back to the default
+	cfg.CalculationEventsLimit = v.GetInt("calculation_events_limit")
+	if cfg.CalculationEventsLimit <= 0 { // Check if the number could not be parsed or is not positive
+		log.Printf("Invalid calculation events limit %q; using default 10000", v.GetString("calculation_events_limit"))
+		cfg.CalculationEventsLimit = 10000
+	}
+	log.Printf("Keeping up to %d undelivered calculation events per tenant", cfg.CalculationEventsLimit) // Log the events limit
+
+	// Load whether calculations are audited as well as changes
+	cfg.AuditCalculations = v.GetBool("audit_calculations")
+	log.Printf("Auditing calculations: %t", cfg.AuditCalculations) // Log the audit setting
+
+	// Load the admin key; the key itself is never logged
+	cfg.AdminAPIKey = v.GetString("admin_api_key")
+	log.Printf("Admin endpoints enabled: %t", cfg.AdminAPIKey != "") // Log whether snapshots can be taken and restored
+
+	// Load how many calculations are kept; invalid values fall back to the default
+	cfg.CalculationHistoryLimit = v.GetInt("calculation_history_limit")
+	if cfg.CalculationHistoryLimit <= 0 { // Check if the number could not be parsed or is not positive
+		log.Printf("Invalid calculation history limit %q; using default 10000", v.GetString("calculation_history_limit"))
+		cfg.CalculationHistoryLimit = 10000
+	}
+	log.Printf("Keeping up to %d calculations per tenant", cfg.CalculationHistoryLimit) // Log the history limit
+
+	// Load the reservation settings; invalid values fall back to the defaults
+	cfg.ReservationTTL = v.GetDuration("reservation_ttl")
+	if cfg.ReservationTTL <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid reservation TTL %q; using default 15m", v.GetString("reservation_ttl"))
+		cfg.ReservationTTL = 15 * time.Minute
+	}
+	cfg.ReservationExpiryInterval = v.GetDuration("reservation_expiry_interval")
+	if cfg.ReservationExpiryInterval <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid reservation expiry interval %q; using default 30s", v.GetString("reservation_expiry_interval"))
+		cfg.ReservationExpiryInterval = 30 * time.Second
+	}
+	log.Printf("Holding reservations for %s, releasing expired ones every %s", cfg.ReservationTTL, cfg.ReservationExpiryInterval) // Log the reservation settings
+
+	// Load where pack sizes are stored; the type is checked when the repositories are created
+	cfg.RepositoryType = strings.ToLower(strings.TrimSpace(v.GetString("repository.type")))
+	cfg.RepositoryPath = v.GetString("repository.path")
+	cfg.RepositoryDSN = v.GetString("repository.dsn")
+	if cfg.RepositoryType == RepositorySQLite && cfg.RepositoryDSN == "" { // Keep the database next to the other data files
+		cfg.RepositoryDSN = filepath.Join(cfg.RepositoryPath, "packs.db")
+	}
+	if cfg.RepositoryType == RepositoryBolt && cfg.RepositoryDSN == "" { // Likewise for the bolt file
+		cfg.RepositoryDSN = filepath.Join(cfg.RepositoryPath, "packs.bolt")
+	}
+	log.Printf("Using %s pack repository", cfg.RepositoryType) // Log the repository type
+	switch cfg.RepositoryType {
+	case RepositoryFile:
+		log.Printf("Storing pack sizes under %s", cfg.RepositoryPath) // Log where the files are written
+	case RepositorySQLite, RepositoryBolt:
+		log.Printf("Storing pack sizes in %s", cfg.RepositoryDSN) // Log the database file
+	}
+
+	// Load the database pool settings; invalid values fall back to the defaults
+	cfg.RepositoryMaxOpenConns = v.GetInt("repository.max_open_conns")
+	if cfg.RepositoryMaxOpenConns <= 0 { // Check if the number could not be parsed or is not positive
+		log.Printf("Invalid max open connections %q; using default 10", v.GetString("repository.max_open_conns"))
+		cfg.RepositoryMaxOpenConns = 10
+	}
+	cfg.RepositoryMaxIdleConns = v.GetInt("repository.max_idle_conns")
+	if cfg.RepositoryMaxIdleConns < 0 || cfg.RepositoryMaxIdleConns > cfg.RepositoryMaxOpenConns { // Idle connections cannot exceed open ones
+		log.Printf("Invalid max idle connections %q; using %d", v.GetString("repository.max_idle_conns"), cfg.RepositoryMaxOpenConns)
+		cfg.RepositoryMaxIdleConns = cfg.RepositoryMaxOpenConns
+	}
+	cfg.RepositoryConnMaxLifetime = v.GetDuration("repository.conn_max_lifetime")
+	if cfg.RepositoryConnMaxLifetime <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid connection max lifetime %q; using default 30m", v.GetString("repository.conn_max_lifetime"))
+		cfg.RepositoryConnMaxLifetime = 30 * time.Minute
+	}
+	if cfg.RepositoryType == RepositoryPostgres || cfg.RepositoryType == RepositoryRedis {
+		log.Printf("Using database pool: %d open, %d idle, %s lifetime", cfg.RepositoryMaxOpenConns, cfg.RepositoryMaxIdleConns, cfg.RepositoryConnMaxLifetime) // Log the pool settings
+	}
+
+	// Load the repository decorator settings; invalid values fall back to the defaults
+	cfg.RepositoryCacheTTL = v.GetDuration("repository.cache_ttl")
+	if cfg.RepositoryCacheTTL < 0 { // Check if the duration is negative; one that cannot be parsed reads as zero
+		log.Printf("Invalid repository cache TTL %q; caching disabled", v.GetString("repository.cache_ttl"))
+		cfg.RepositoryCacheTTL = 0
+	}
+	cfg.RepositoryMetrics = v.GetBool("repository.metrics")
+	cfg.RepositoryRetryAttempts = v.GetInt("repository.retry_attempts")
+	if cfg.RepositoryRetryAttempts <= 0 { // Check if the number could not be parsed or is not positive
+		log.Printf("Invalid repository retry attempts %q; using default 3", v.GetString("repository.retry_attempts"))
+		cfg.RepositoryRetryAttempts = 3
+	}
+	cfg.RepositoryRetryBackoff = v.GetDuration("repository.retry_backoff")
+	if cfg.RepositoryRetryBackoff <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid repository retry backoff %q; using default 50ms", v.GetString("repository.retry_backoff"))
+		cfg.RepositoryRetryBackoff = 50 * time.Millisecond
+	}
+	log.Printf("Using repository cache TTL %s, %d read attempts with %s backoff, metrics %t", cfg.RepositoryCacheTTL, cfg.RepositoryRetryAttempts, cfg.RepositoryRetryBackoff, cfg.RepositoryMetrics) // Log the decorator settings
+
+	// Load the webhook delivery settings; invalid values fall back to the defaults
+	cfg.WebhookMaxAttempts = v.GetInt("webhook_max_attempts")
+	if cfg.WebhookMaxAttempts <= 0 || cfg.WebhookMaxAttempts > maxWebhookAttempts { // Check if the number could not be parsed or is out of range
+		log.Printf("Invalid webhook max attempts %q (1 to %d); using default 5", v.GetString("webhook_max_attempts"), maxWebhookAttempts)
+		cfg.WebhookMaxAttempts = 5
+	}
+	cfg.WebhookBackoff = v.GetDuration("webhook_backoff")
+	if cfg.WebhookBackoff <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid webhook backoff %q; using default 1s", v.GetString("webhook_backoff"))
+		cfg.WebhookBackoff = time.Second
+	}
+	cfg.WebhookTimeout = v.GetDuration("webhook_timeout")
+	if cfg.WebhookTimeout <= 0 { // Check if the duration could not be parsed or is not positive
+		log.Printf("Invalid webhook timeout %q; using default 10s", v.GetString("webhook_timeout"))
+		cfg.WebhookTimeout = 10 * time.Second
+	}
+	log.Printf("Using webhook delivery: %d attempts, %s backoff, %s timeout", cfg.WebhookMaxAttempts, cfg.WebhookBackoff, cfg.WebhookTimeout) // Log the webhook settings
+
+	return cfg, nil // Return the loaded configuration and nil error
+}
+
+// parsePackSizes converts a comma-separated list into pack sizes, skipping invalid entries
+func parsePackSizes(packSizesStr string) []int {
+	sizes := strings.Split(packSizesStr, ",") // Split the string by commas
+	packSizes := make([]int, 0, len(sizes))   // Initialize the pack sizes slice
+	for _, size := range sizes {              // Loop through each size string
 		size = strings.TrimSpace(size) // Remove any whitespace
 		if size == "" {                // Skip empty entries
 			continue
@@ -76,16 +398,41 @@ func LoadConfig() (*Config, error) {
 			log.Printf("Skipping invalid pack size: %s", size) // Log invalid pack size
 			continue
 		}
-		cfg.PackSizes = append(cfg.PackSizes, num) // Add the valid pack size to the slice
+		packSizes = append(packSizes, num) // Add the valid pack size to the slice
 	}
+	return packSizes
+}
 
-	// Ensure there are pack sizes (fall back to defaults if none were parsed)
-	if len(cfg.PackSizes) == 0 { // Check if the PackSizes slice is empty
-		log.Println("No valid pack sizes found; using default pack sizes: 250,500,1000,2000,5000")
-		cfg.PackSizes = []int{250, 500, 1000, 2000, 5000} // Set default pack sizes
-	} else {
-		log.Printf("Loaded pack sizes: %v", cfg.PackSizes) // Log the final pack sizes
+// loadTenants reads the "tenants" section, for example:
+//
+//	tenants:
+//	  wholesale:
+//	    pack_sizes: "1000,5000"
+//	    api_keys: ["wholesale-key"]
+//
+// The default tenant is always present and uses the top-level pack sizes unless configured otherwise.
+func loadTenants(v *viper.Viper, defaultPackSizes []int) []TenantConfig {
+	ids := make([]string, 0)
+	for id := range v.GetStringMap("tenants") { // Collect the configured tenant IDs
+		ids = append(ids, id)
 	}
+	if _, ok := v.GetStringMap("tenants")[DefaultTenantID]; !ok { // Single-tenant setups keep working unchanged
+		ids = append(ids, DefaultTenantID)
+	}
+	sort.Strings(ids) // Keep the order stable for logging and tests
 
-	return cfg, nil // Return the loaded configuration and nil error
+	tenants := make([]TenantConfig, 0, len(ids))
+	for _, id := range ids {
+		tenant := TenantConfig{
+			ID:        id,
+			PackSizes: parsePackSizes(v.GetString("tenants." + id + ".pack_sizes")),
+			APIKeys:   v.GetStringSlice("tenants." + id + ".api_keys"),
+		}
+		if len(tenant.PackSizes) == 0 { // Fall back to the top-level pack sizes
+			tenant.PackSizes = defaultPackSizes
+		}
+		log.Printf("Loaded tenant %s with pack sizes %v and %d API key(s)", tenant.ID, tenant.PackSizes, len(tenant.APIKeys)) // Log the tenant
+		tenants = append(tenants, tenant)
+	}
+	return tenants
 }
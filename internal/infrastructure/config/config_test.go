@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"      // Import os for setting environment variables
 	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for durations
 
 	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
 )
@@ -28,6 +29,34 @@ func (s *ConfigTestSuite) SetupTest() {
 	// Clear environment variables
 	os.Unsetenv("PORT")
 	os.Unsetenv("PACK_SIZES")
+	os.Unsetenv("TIE_BREAK")
+	os.Unsetenv("PERSIST_PACK_SIZES")
+	os.Unsetenv("REQUIRE_APPROVAL")
+	os.Unsetenv("MAX_PACK_SIZES")
+	os.Unsetenv("MAX_PACK_SIZE")
+	os.Unsetenv("QUOTE_TTL")
+	os.Unsetenv("IDEMPOTENCY_TTL")
+	os.Unsetenv("OUTBOX_RELAY_INTERVAL")
+	os.Unsetenv("EVENTS_ENABLED")
+	os.Unsetenv("CALCULATION_EVENTS_LIMIT")
+	os.Unsetenv("WEBHOOK_MAX_ATTEMPTS")
+	os.Unsetenv("AUDIT_CALCULATIONS")
+	os.Unsetenv("ADMIN_API_KEY")
+	os.Unsetenv("CALCULATION_HISTORY_LIMIT")
+	os.Unsetenv("RESERVATION_TTL")
+	os.Unsetenv("RESERVATION_EXPIRY_INTERVAL")
+	os.Unsetenv("REPOSITORY_TYPE")
+	os.Unsetenv("REPOSITORY_PATH")
+	os.Unsetenv("REPOSITORY_DSN")
+	os.Unsetenv("REPOSITORY_MAX_OPEN_CONNS")
+	os.Unsetenv("REPOSITORY_MAX_IDLE_CONNS")
+	os.Unsetenv("REPOSITORY_CONN_MAX_LIFETIME")
+	os.Unsetenv("REPOSITORY_CACHE_TTL")
+	os.Unsetenv("REPOSITORY_METRICS")
+	os.Unsetenv("REPOSITORY_RETRY_ATTEMPTS")
+	os.Unsetenv("REPOSITORY_RETRY_BACKOFF")
+	os.Unsetenv("WEBHOOK_BACKOFF")
+	os.Unsetenv("WEBHOOK_TIMEOUT")
 }
 
 // TearDownTest cleans up the test environment after each test
@@ -51,6 +80,39 @@ func (s *ConfigTestSuite) TestDefaultValues() {
 	// Verify default values
 	s.Assert().Equal(":3000", cfg.Port, "Port should match default")
 	s.Assert().Equal([]int{250, 500, 1000, 2000, 5000}, cfg.PackSizes, "Pack sizes should match default")
+	s.Assert().Equal("prefer_larger", cfg.TieBreak, "Tie-break policy should match default")
+	s.Assert().Empty(cfg.ConfigFile, "No config file should be read")
+	s.Assert().False(cfg.PersistPackSizes, "Pack sizes should not be written back by default")
+	s.Assert().False(cfg.RequireApproval, "Approval should not be required by default")
+	s.Assert().Equal(20, cfg.MaxPackSizes, "Max pack sizes should match default")
+	s.Assert().Equal(1000000, cfg.MaxPackSize, "Max pack size should match default")
+	s.Assert().Equal(168*time.Hour, cfg.QuoteTTL, "Quote TTL should match default")
+	s.Assert().Equal(24*time.Hour, cfg.IdempotencyTTL, "Idempotency TTL should match default")
+	s.Assert().Equal(time.Second, cfg.OutboxRelayInterval, "Outbox relay interval should match default")
+	s.Assert().True(cfg.EventsEnabled, "Events should be emitted by default")
+	s.Assert().Equal(10000, cfg.CalculationEventsLimit, "Calculation events limit should match default")
+	s.Assert().Equal(5, cfg.WebhookMaxAttempts, "Webhook attempts should match default")
+	s.Assert().False(cfg.AuditCalculations, "Calculations should not be audited by default")
+	s.Assert().Empty(cfg.AdminAPIKey, "Admin endpoints should be disabled by default")
+	s.Assert().Equal(10000, cfg.CalculationHistoryLimit, "Calculation history limit should match default")
+	s.Assert().Equal(15*time.Minute, cfg.ReservationTTL, "Reservation TTL should match default")
+	s.Assert().Equal(30*time.Second, cfg.ReservationExpiryInterval, "Reservation expiry interval should match default")
+	s.Assert().Equal(RepositoryMemory, cfg.RepositoryType, "Repository type should match default")
+	s.Assert().Equal("data", cfg.RepositoryPath, "Repository path should match default")
+	s.Assert().Empty(cfg.RepositoryDSN, "In-memory repositories need no DSN")
+	s.Assert().Equal(10, cfg.RepositoryMaxOpenConns, "Max open connections should match default")
+	s.Assert().Equal(5, cfg.RepositoryMaxIdleConns, "Max idle connections should match default")
+	s.Assert().Equal(30*time.Minute, cfg.RepositoryConnMaxLifetime, "Connection lifetime should match default")
+	s.Assert().Zero(cfg.RepositoryCacheTTL, "Repository cache should be disabled by default")
+	s.Assert().True(cfg.RepositoryMetrics, "Repository calls should be timed by default")
+	s.Assert().Equal(3, cfg.RepositoryRetryAttempts, "Retry attempts should match default")
+	s.Assert().Equal(50*time.Millisecond, cfg.RepositoryRetryBackoff, "Retry backoff should match default")
+	s.Assert().Equal(time.Second, cfg.WebhookBackoff, "Webhook backoff should match default")
+	s.Assert().Equal(10*time.Second, cfg.WebhookTimeout, "Webhook timeout should match default")
+	s.Require().Len(cfg.Tenants, 1, "Only the default tenant should exist")
+	s.Assert().Equal(DefaultTenantID, cfg.Tenants[0].ID, "Default tenant should exist")
+	s.Assert().Equal(cfg.PackSizes, cfg.Tenants[0].PackSizes, "Default tenant should use the top-level pack sizes")
+	s.Assert().Empty(cfg.Tenants[0].APIKeys, "Default tenant should be open")
 }
 
 // TestEnvironmentVariables tests loading from environment variables
@@ -58,6 +120,23 @@ func (s *ConfigTestSuite) TestEnvironmentVariables() {
 	// Set environment variables
 	os.Setenv("PORT", "4000")
 	os.Setenv("PACK_SIZES", "100,200,300")
+	os.Setenv("REQUIRE_APPROVAL", "true")
+	os.Setenv("PERSIST_PACK_SIZES", "true")
+	os.Setenv("MAX_PACK_SIZE", "5000")
+	os.Setenv("IDEMPOTENCY_TTL", "10m")
+	os.Setenv("OUTBOX_RELAY_INTERVAL", "5s")
+	os.Setenv("EVENTS_ENABLED", "false")
+	os.Setenv("CALCULATION_EVENTS_LIMIT", "50")
+	os.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+	os.Setenv("AUDIT_CALCULATIONS", "true")
+	os.Setenv("ADMIN_API_KEY", "s3cret")
+	os.Setenv("REPOSITORY_PATH", "/var/lib/packs")
+	os.Setenv("REPOSITORY_TYPE", "sqlite")
+	os.Setenv("REPOSITORY_MAX_OPEN_CONNS", "20")
+	os.Setenv("REPOSITORY_CACHE_TTL", "5s")
+	os.Setenv("REPOSITORY_METRICS", "false")
+	os.Setenv("REPOSITORY_RETRY_ATTEMPTS", "0")
+	os.Setenv("REPOSITORY_RETRY_BACKOFF", "200ms")
 
 	// Load the configuration
 	cfg, err := LoadConfig()
@@ -66,6 +145,42 @@ func (s *ConfigTestSuite) TestEnvironmentVariables() {
 	// Verify environment variable values
 	s.Assert().Equal(":4000", cfg.Port, "Port should match environment variable")
 	s.Assert().Equal([]int{100, 200, 300}, cfg.PackSizes, "Pack sizes should match environment variable")
+	s.Assert().True(cfg.RequireApproval, "Approval setting should match environment variable")
+	s.Assert().False(cfg.PersistPackSizes, "Pack sizes cannot be written back without a config file")
+	s.Assert().Equal(5000, cfg.MaxPackSize, "Max pack size should match environment variable")
+	s.Assert().Equal(10*time.Minute, cfg.IdempotencyTTL, "Idempotency TTL should match environment variable")
+	s.Assert().Equal(5*time.Second, cfg.OutboxRelayInterval, "Outbox relay interval should match environment variable")
+	s.Assert().False(cfg.EventsEnabled, "Event setting should match environment variable")
+	s.Assert().Equal(50, cfg.CalculationEventsLimit, "Calculation events limit should match environment variable")
+	s.Assert().Equal(3, cfg.WebhookMaxAttempts, "Webhook attempts should match environment variable")
+	s.Assert().True(cfg.AuditCalculations, "Audit setting should match environment variable")
+	s.Assert().Equal("s3cret", cfg.AdminAPIKey, "Admin key should match environment variable")
+	s.Assert().Equal("/var/lib/packs", cfg.RepositoryPath, "Repository path should match environment variable")
+	s.Assert().Equal("/var/lib/packs/packs.db", cfg.RepositoryDSN, "SQLite database should default to the repository path")
+	s.Assert().Equal(20, cfg.RepositoryMaxOpenConns, "Max open connections should match environment variable")
+	s.Assert().Equal(5*time.Second, cfg.RepositoryCacheTTL, "Repository cache TTL should match environment variable")
+	s.Assert().False(cfg.RepositoryMetrics, "Repository metrics setting should match environment variable")
+	s.Assert().Equal(3, cfg.RepositoryRetryAttempts, "Invalid retry attempts should fall back to the default")
+	s.Assert().Equal(200*time.Millisecond, cfg.RepositoryRetryBackoff, "Retry backoff should match environment variable")
+
+	// Webhook attempts are bounded, so retries cannot go on for ever
+	os.Setenv("WEBHOOK_MAX_ATTEMPTS", "100")
+	cfg, err = LoadConfig()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(5, cfg.WebhookMaxAttempts, "Too many webhook attempts should fall back to the default")
+	os.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+
+	// The bolt file also defaults to the repository path
+	os.Setenv("REPOSITORY_TYPE", "bolt")
+	cfg, err = LoadConfig()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal("/var/lib/packs/packs.bolt", cfg.RepositoryDSN, "Bolt database should default to the repository path")
+
+	// An explicit DSN replaces the default database path
+	os.Setenv("REPOSITORY_DSN", "/tmp/other.db")
+	cfg, err = LoadConfig()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal("/tmp/other.db", cfg.RepositoryDSN, "DSN should match environment variable")
 }
 
 // TestConfigFile tests loading from a config.yaml file
@@ -74,6 +189,13 @@ func (s *ConfigTestSuite) TestConfigFile() {
 	configContent := `
 port: "5000"
 pack_sizes: "50,100,150"
+tie_break: "fewest_sizes"
+persist_pack_sizes: true
+quote_ttl: "72h"
+repository:
+  type: "File"
+  max_open_conns: 4
+  max_idle_conns: 8
 `
 	err := ioutil.WriteFile("config.yaml", []byte(configContent), 0644)
 	s.Require().NoError(err, "Failed to create config.yaml")
@@ -85,6 +207,37 @@ pack_sizes: "50,100,150"
 	// Verify config file values
 	s.Assert().Equal(":5000", cfg.Port, "Port should match config file")
 	s.Assert().Equal([]int{50, 100, 150}, cfg.PackSizes, "Pack sizes should match config file")
+	s.Assert().Equal("fewest_sizes", cfg.TieBreak, "Tie-break policy should match config file")
+	s.Assert().Contains(cfg.ConfigFile, "config.yaml", "Config file should be recorded")
+	s.Assert().True(cfg.PersistPackSizes, "Pack sizes should be written back to the config file")
+	s.Assert().Equal(72*time.Hour, cfg.QuoteTTL, "Quote TTL should match config file")
+	s.Assert().Equal(RepositoryFile, cfg.RepositoryType, "Repository type should match config file, case-insensitively")
+	s.Assert().Equal(4, cfg.RepositoryMaxOpenConns, "Max open connections should match config file")
+	s.Assert().Equal(4, cfg.RepositoryMaxIdleConns, "Idle connections should be capped at the open ones")
+}
+
+// TestTenants tests loading per-tenant pack sizes and API keys
+func (s *ConfigTestSuite) TestTenants() {
+	configContent := `
+pack_sizes: "250,500"
+tenants:
+  wholesale:
+    pack_sizes: "1000,5000"
+    api_keys: ["wk-1", "wk-2"]
+  retail:
+    api_keys: ["rk-1"]
+`
+	err := ioutil.WriteFile("config.yaml", []byte(configContent), 0644)
+	s.Require().NoError(err, "Failed to create config.yaml")
+
+	cfg, err := LoadConfig()
+	s.Require().NoError(err, "Expected no error")
+
+	s.Assert().Equal([]TenantConfig{
+		{ID: DefaultTenantID, PackSizes: []int{250, 500}},
+		{ID: "retail", PackSizes: []int{250, 500}, APIKeys: []string{"rk-1"}},
+		{ID: "wholesale", PackSizes: []int{1000, 5000}, APIKeys: []string{"wk-1", "wk-2"}},
+	}, cfg.Tenants, "Tenants should match config file, sorted, with the default added")
 }
 
 // TestInvalidPackSizes tests handling of invalid pack sizes in config
@@ -0,0 +1,154 @@
+package config // Define the package name as "config" for configuration management
+
+import (
+	"bytes"         // Import bytes for encoding the updated file
+	"fmt"           // Import fmt for describing files that cannot be updated
+	"os"            // Import os for reading and replacing the file
+	"path/filepath" // Import filepath for creating the replacement next to the file
+	"strconv"       // Import strconv for formatting pack sizes
+	"strings"       // Import strings for joining pack sizes and matching keys
+	"sync"          // Import sync for serialising rewrites
+
+	"gopkg.in/yaml.v3" // Import yaml.v3, whose node tree keeps comments and key order
+)
+
+// PackSizesWriter writes pack sizes changed at runtime back to the configuration file LoadConfig read,
+// so that they are what the catalogues are seeded with after a restart
+type PackSizesWriter struct {
+	path string     // Configuration file to rewrite
+	mu   sync.Mutex // Every tenant is in the same file, so rewrites must not interleave
+}
+
+// NewPackSizesWriter creates a writer for the configuration file at path
+func NewPackSizesWriter(path string) *PackSizesWriter {
+	return &PackSizesWriter{path: path}
+}
+
+// WritePackSizes sets the pack sizes of a tenant in the configuration file. Only that key changes:
+// other keys keep their values, order and comments, although blank lines and indentation are
+// normalised. The default tenant's sizes go to the top-level pack_sizes unless it has its own entry
+// under tenants; other tenants get tenants.<id>.pack_sizes
+func (w *PackSizesWriter) WritePackSizes(tenantID string, sizes []int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("reading %s: %w", w.path, err)
+	}
+	if document.Kind == 0 { // An empty file has no document yet
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: top level is not a mapping", w.path)
+	}
+
+	// Find the mapping the pack_sizes key belongs in
+	owner := root
+	tenants := mappingValue(root, "tenants")
+	if tenant := mappingValue(tenants, tenantID); tenantID != DefaultTenantID || mappingValue(tenant, "pack_sizes") != nil {
+		if isNull(tenants) {
+			tenants = setMappingValue(root, "tenants", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+		}
+		if tenants.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s: tenants is not a mapping", w.path)
+		}
+		if isNull(tenant) { // Tenants listed without settings have a null value
+			tenant = setMappingValue(tenants, tenantID, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+		}
+		if tenant.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s: tenant %s is not a mapping", w.path, tenantID)
+		}
+		owner = tenant
+	}
+
+	// Write the sizes the way LoadConfig reads them, keeping the comments of an existing value
+	formatted := make([]string, len(sizes))
+	for i, size := range sizes {
+		formatted[i] = strconv.Itoa(size)
+	}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: strings.Join(formatted, ","), Style: yaml.DoubleQuotedStyle}
+	if existing := mappingValue(owner, "pack_sizes"); existing != nil {
+		value.HeadComment, value.LineComment, value.FootComment = existing.HeadComment, existing.LineComment, existing.FootComment
+	}
+	setMappingValue(owner, "pack_sizes", value)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return replaceFile(w.path, buf.Bytes(), info.Mode().Perm())
+}
+
+// mappingValue returns the value of key in a mapping node, matching keys case-insensitively as Viper
+// does, or nil when the node is not a mapping or has no such key
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isNull reports whether a value is missing or empty
+func isNull(node *yaml.Node) bool {
+	return node == nil || (node.Kind == yaml.ScalarNode && node.Tag == "!!null")
+}
+
+// setMappingValue sets the value of key in a mapping node, appending the key when it is missing, and
+// returns the value
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			mapping.Content[i+1] = value
+			return value
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
+
+// replaceFile writes data next to path and renames it over path, so a crash never leaves a truncated
+// configuration behind
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once the rename succeeded
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"            // Import os for reading the rewritten file
+	"path/filepath" // Import filepath for the file path
+	"testing"       // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+)
+
+// PackSizesWriterTestSuite defines the test suite for writing pack sizes back to the config file
+type PackSizesWriterTestSuite struct {
+	suite.Suite        // Embed the testify suite
+	path        string // Config file under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *PackSizesWriterTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "config.yaml")
+}
+
+// TestPackSizesWriterTestSuite runs the test suite
+func TestPackSizesWriterTestSuite(t *testing.T) {
+	suite.Run(t, new(PackSizesWriterTestSuite))
+}
+
+// write creates the config file with the given contents
+func (s *PackSizesWriterTestSuite) write(content string) {
+	s.Require().NoError(os.WriteFile(s.path, []byte(content), 0600), "Failed to create config file")
+}
+
+// read returns the contents of the config file
+func (s *PackSizesWriterTestSuite) read() string {
+	data, err := os.ReadFile(s.path)
+	s.Require().NoError(err, "Failed to read config file")
+	return string(data)
+}
+
+// TestDefaultTenant tests that the top-level pack sizes are replaced and everything else is kept
+func (s *PackSizesWriterTestSuite) TestDefaultTenant() {
+	s.write(`# Server settings
+port: "3000"
+pack_sizes: "250,500" # Sizes on the shelf
+tenants:
+  retail:
+    api_keys: ["rk-1"]
+`)
+	writer := NewPackSizesWriter(s.path)
+	s.Require().NoError(writer.WritePackSizes(DefaultTenantID, []int{100, 200}), "Expected no error")
+
+	s.Assert().Equal(`# Server settings
+port: "3000"
+pack_sizes: "100,200" # Sizes on the shelf
+tenants:
+  retail:
+    api_keys: ["rk-1"]
+`, s.read(), "Only the pack sizes should change")
+	info, err := os.Stat(s.path)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(os.FileMode(0600), info.Mode().Perm(), "File mode should be kept")
+}
+
+// TestTenants tests that other tenants get their own pack sizes, even when they had none or are new
+func (s *PackSizesWriterTestSuite) TestTenants() {
+	s.write(`pack_sizes: "250,500"
+tenants:
+  Wholesale:
+    pack_sizes: "1000"
+  retail:
+  default:
+    pack_sizes: "5"
+`)
+	writer := NewPackSizesWriter(s.path)
+	s.Require().NoError(writer.WritePackSizes("wholesale", []int{2000}), "Expected no error")
+	s.Require().NoError(writer.WritePackSizes("retail", []int{10, 20}), "Expected no error")
+	s.Require().NoError(writer.WritePackSizes("outlet", []int{1}), "Expected no error")
+	s.Require().NoError(writer.WritePackSizes(DefaultTenantID, []int{50}), "Expected no error")
+
+	s.Assert().Equal(`pack_sizes: "250,500"
+tenants:
+  Wholesale:
+    pack_sizes: "2000"
+  retail:
+    pack_sizes: "10,20"
+  default:
+    pack_sizes: "50"
+  outlet:
+    pack_sizes: "1"
+`, s.read(), "Each tenant should get its own pack sizes")
+
+	// The written file is read back as written
+	s.Require().NoError(os.Chdir(filepath.Dir(s.path)), "Failed to change working directory")
+	cfg, err := LoadConfig()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]TenantConfig{
+		{ID: DefaultTenantID, PackSizes: []int{50}},
+		{ID: "outlet", PackSizes: []int{1}},
+		{ID: "retail", PackSizes: []int{10, 20}},
+		{ID: "wholesale", PackSizes: []int{2000}},
+	}, cfg.Tenants, "Tenants should be loaded with the written pack sizes")
+}
+
+// TestInvalidFile tests that files that are missing or not a mapping are left alone
+func (s *PackSizesWriterTestSuite) TestInvalidFile() {
+	writer := NewPackSizesWriter(s.path)
+	s.Assert().Error(writer.WritePackSizes(DefaultTenantID, []int{1}), "Missing files should be reported")
+
+	s.write("- not a mapping\n")
+	s.Assert().Error(writer.WritePackSizes(DefaultTenantID, []int{1}), "Lists should be rejected")
+	s.write("tenants: [retail]\n")
+	s.Assert().Error(writer.WritePackSizes("retail", []int{1}), "Tenants that are not a mapping should be rejected")
+	s.Assert().Equal("tenants: [retail]\n", s.read(), "Rejected files should not change")
+}
@@ -0,0 +1,89 @@
+package events // Define the package name as "events" for event delivery
+
+import (
+	"errors" // Import errors for joining handler failures
+	"fmt"    // Import fmt for formatting log messages
+	"sync"   // Import sync for guarding subscriptions
+
+	"order-packs-calculator/internal/domain"                 // Import the domain package for event envelopes
+	"order-packs-calculator/internal/infrastructure/logging" // Import the logging package for logging
+)
+
+// Publisher delivers domain events to the outside world
+type Publisher interface {
+	Publish(event domain.EventEnvelope) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface
+type PublisherFunc func(event domain.EventEnvelope) error
+
+// Publish calls f(event)
+func (f PublisherFunc) Publish(event domain.EventEnvelope) error {
+	return f(event)
+}
+
+// LogPublisher writes every event to the application log, which is enough for local use
+type LogPublisher struct {
+	logger *logging.Logger // Logger instance for writing events
+}
+
+// NewLogPublisher creates a new instance of LogPublisher
+func NewLogPublisher(logger *logging.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs the event
+func (p *LogPublisher) Publish(event domain.EventEnvelope) error {
+	p.logger.Info(fmt.Sprintf("Event #%d %s: %s", event.ID, event.Type, event.Payload))
+	return nil
+}
+
+// InProcessPublisher fans events out to subscribers within the same process
+type InProcessPublisher struct {
+	mu          sync.RWMutex                     // Guards the subscriber lists
+	subscribers map[domain.EventType][]Publisher // Subscribers for one event type
+	all         []Publisher                      // Subscribers for every event type
+}
+
+// NewInProcessPublisher creates a new instance of InProcessPublisher
+func NewInProcessPublisher() *InProcessPublisher {
+	return &InProcessPublisher{subscribers: make(map[domain.EventType][]Publisher)}
+}
+
+// Subscribe registers a subscriber for the given event types, or for every event when none are given
+func (p *InProcessPublisher) Subscribe(subscriber Publisher, types ...domain.EventType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(types) == 0 {
+		p.all = append(p.all, subscriber)
+		return
+	}
+	for _, eventType := range types {
+		p.subscribers[eventType] = append(p.subscribers[eventType], subscriber)
+	}
+}
+
+// Publish hands the event to every matching subscriber, reporting all of their failures
+func (p *InProcessPublisher) Publish(event domain.EventEnvelope) error {
+	p.mu.RLock()
+	subscribers := append(append([]Publisher{}, p.all...), p.subscribers[event.Type]...)
+	p.mu.RUnlock()
+
+	var errs []error
+	for _, subscriber := range subscribers {
+		if err := subscriber.Publish(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ForTenant wraps a publisher so that it only receives the events of one tenant
+func ForTenant(tenant string, publisher Publisher) Publisher {
+	return PublisherFunc(func(event domain.EventEnvelope) error {
+		if event.Tenant != tenant { // Other tenants' events are not for this subscriber
+			return nil
+		}
+		return publisher.Publish(event)
+	})
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"errors"  // Import errors for simulated subscriber failures
+	"testing" // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for events
+)
+
+// InProcessPublisherTestSuite defines the test suite for the in-process publisher
+type InProcessPublisherTestSuite struct {
+	suite.Suite
+	publisher *InProcessPublisher // Publisher under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *InProcessPublisherTestSuite) SetupTest() {
+	s.publisher = NewInProcessPublisher()
+}
+
+// TestInProcessPublisherTestSuite runs the test suite
+func TestInProcessPublisherTestSuite(t *testing.T) {
+	suite.Run(t, new(InProcessPublisherTestSuite))
+}
+
+// recorder returns a subscriber that appends received event types to the given slice
+func recorder(received *[]domain.EventType) Publisher {
+	return PublisherFunc(func(event domain.EventEnvelope) error {
+		*received = append(*received, event.Type)
+		return nil
+	})
+}
+
+// TestPublishRoutesByType tests that subscribers only receive the event types they asked for
+func (s *InProcessPublisherTestSuite) TestPublishRoutesByType() {
+	var all, updates []domain.EventType
+	s.publisher.Subscribe(recorder(&all))
+	s.publisher.Subscribe(recorder(&updates), domain.EventPackSizesUpdated)
+
+	s.Require().NoError(s.publisher.Publish(domain.EventEnvelope{Type: domain.EventPackSizesUpdated}))
+	s.Require().NoError(s.publisher.Publish(domain.EventEnvelope{Type: domain.EventCalculationPerformed}))
+
+	s.Assert().Equal([]domain.EventType{domain.EventPackSizesUpdated, domain.EventCalculationPerformed}, all,
+		"Subscribers without types should receive every event")
+	s.Assert().Equal([]domain.EventType{domain.EventPackSizesUpdated}, updates,
+		"Typed subscribers should only receive their events")
+}
+
+// TestPublishReportsFailures tests that a failing subscriber does not stop the others
+func (s *InProcessPublisherTestSuite) TestPublishReportsFailures() {
+	var received []domain.EventType
+	s.publisher.Subscribe(PublisherFunc(func(domain.EventEnvelope) error { return errors.New("subscriber down") }))
+	s.publisher.Subscribe(recorder(&received))
+
+	err := s.publisher.Publish(domain.EventEnvelope{Type: domain.EventPackSizesUpdated})
+	s.Assert().EqualError(err, "subscriber down", "Failure should be reported")
+	s.Assert().Len(received, 1, "Other subscribers should still receive the event")
+}
+
+// TestForTenant tests that tenant-scoped subscribers only receive their tenant's events
+func (s *InProcessPublisherTestSuite) TestForTenant() {
+	var received []domain.EventType
+	s.publisher.Subscribe(ForTenant("retail", recorder(&received)))
+
+	s.Require().NoError(s.publisher.Publish(domain.EventEnvelope{Type: domain.EventPackSizesUpdated, Tenant: "wholesale"}))
+	s.Require().NoError(s.publisher.Publish(domain.EventEnvelope{Type: domain.EventCalculationPerformed, Tenant: "retail"}))
+
+	s.Assert().Equal([]domain.EventType{domain.EventCalculationPerformed}, received, "Only the tenant's events should be received")
+}
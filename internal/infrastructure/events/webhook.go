@@ -0,0 +1,84 @@
+package events // Define the package name as "events" for event delivery
+
+import (
+	"bytes"         // Import bytes for the request body
+	"crypto/hmac"   // Import hmac for signing payloads
+	"crypto/sha256" // Import sha256 as the HMAC hash
+	"encoding/hex"  // Import hex for encoding signatures
+	"encoding/json" // Import json for encoding payloads
+	"fmt"           // Import fmt for error messages
+	"net/http"      // Import net/http for posting webhooks
+	"strconv"       // Import strconv for formatting timestamps
+	"time"          // Import time for timestamps and timeouts
+
+	"order-packs-calculator/internal/domain" // Import the domain package for event envelopes
+)
+
+const (
+	// WebhookEventHeader carries the event type
+	WebhookEventHeader = "X-Webhook-Event"
+	// WebhookDeliveryHeader carries the event ID, which receivers can use to drop duplicates
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+	// WebhookTimestampHeader carries the Unix time the payload was signed at
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookSignatureHeader carries "sha256=" followed by the hex HMAC of "<timestamp>.<body>"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookSender delivers a single event to a webhook URL
+type WebhookSender interface {
+	// Send posts the event and returns the response status code; non-2xx responses are errors
+	Send(url, secret string, event domain.EventEnvelope) (int, error)
+}
+
+// HTTPWebhookSender posts signed JSON payloads over HTTP
+type HTTPWebhookSender struct {
+	client *http.Client     // HTTP client used for deliveries
+	now    func() time.Time // Clock used for the signature timestamp
+}
+
+// NewHTTPWebhookSender creates a new instance of HTTPWebhookSender with the given request timeout
+func NewHTTPWebhookSender(timeout time.Duration) *HTTPWebhookSender {
+	return &HTTPWebhookSender{
+		client: &http.Client{Timeout: timeout}, // Give up on slow receivers
+		now:    time.Now,                       // Use the wall clock
+	}
+}
+
+// Send posts the event to url, signed with secret
+func (s *HTTPWebhookSender) Send(url, secret string, event domain.EventEnvelope) (int, error) {
+	body, err := json.Marshal(event) // Receivers get the full envelope
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.Type))
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(event.ID))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil { // Network errors are retried by the caller
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 { // Only 2xx counts as delivered
+		return resp.StatusCode, fmt.Errorf("webhook receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value for a payload; receivers recompute it to verify deliveries
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"encoding/json"     // Import json for decoding payloads
+	"io"                // Import io for reading request bodies
+	"net/http"          // Import net/http for the test receiver
+	"net/http/httptest" // Import httptest for a local webhook receiver
+	"testing"           // Import the testing package for writing unit tests
+	"time"              // Import time for the sender clock
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for events
+)
+
+// HTTPWebhookSenderTestSuite defines the test suite for the webhook sender
+type HTTPWebhookSenderTestSuite struct {
+	suite.Suite
+	sender   *HTTPWebhookSender // Sender under test
+	status   int                // Status the receiver responds with
+	received *http.Request      // Last request the receiver got
+	body     []byte             // Body of the last request
+	server   *httptest.Server   // Local webhook receiver
+}
+
+// SetupTest sets up the test environment before each test
+func (s *HTTPWebhookSenderTestSuite) SetupTest() {
+	s.status = http.StatusOK
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.received = r
+		s.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(s.status)
+	}))
+	s.sender = NewHTTPWebhookSender(time.Second)
+	s.sender.now = func() time.Time { return time.Unix(1748779200, 0) }
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *HTTPWebhookSenderTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// TestHTTPWebhookSenderTestSuite runs the test suite
+func TestHTTPWebhookSenderTestSuite(t *testing.T) {
+	suite.Run(t, new(HTTPWebhookSenderTestSuite))
+}
+
+// TestSendSignsPayload tests that deliveries carry the event and a verifiable signature
+func (s *HTTPWebhookSenderTestSuite) TestSendSignsPayload() {
+	event := domain.EventEnvelope{ID: 7, Type: domain.EventPackSizesUpdated, Payload: json.RawMessage(`{"version":{"id":2}}`)}
+
+	status, err := s.sender.Send(s.server.URL, "secret", event)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(http.StatusOK, status, "Status should be returned")
+
+	s.Assert().Equal("pack_sizes.updated", s.received.Header.Get(WebhookEventHeader), "Event type header should be set")
+	s.Assert().Equal("7", s.received.Header.Get(WebhookDeliveryHeader), "Delivery header should carry the event ID")
+	s.Assert().Equal("1748779200", s.received.Header.Get(WebhookTimestampHeader), "Timestamp header should be set")
+	s.Assert().Equal(SignWebhookPayload("secret", "1748779200", s.body), s.received.Header.Get(WebhookSignatureHeader),
+		"Signature should verify with the shared secret")
+	s.Assert().NotEqual(SignWebhookPayload("other", "1748779200", s.body), s.received.Header.Get(WebhookSignatureHeader),
+		"Signature should not verify with another secret")
+
+	var delivered domain.EventEnvelope
+	s.Require().NoError(json.Unmarshal(s.body, &delivered), "Body should be the event envelope")
+	s.Assert().Equal(event.ID, delivered.ID, "Envelope should be delivered")
+}
+
+// TestSendRejectsNon2xx tests that receiver errors are reported
+func (s *HTTPWebhookSenderTestSuite) TestSendRejectsNon2xx() {
+	s.status = http.StatusServiceUnavailable
+
+	status, err := s.sender.Send(s.server.URL, "secret", domain.EventEnvelope{ID: 1})
+	s.Assert().Error(err, "Expected error for non-2xx response")
+	s.Assert().Equal(http.StatusServiceUnavailable, status, "Status should be returned")
+}
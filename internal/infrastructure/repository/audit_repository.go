@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// AuditFilter selects audit entries; zero fields match everything
+type AuditFilter struct {
+	Tenant string
+	Actor  string
+	Action string
+	From   time.Time // Inclusive
+	To     time.Time // Exclusive
+	Offset int
+	Limit  int // Zero means no limit
+}
+
+// Matches reports whether an entry passes the filter, ignoring pagination
+func (f AuditFilter) Matches(entry domain.AuditEntry) bool {
+	return (f.Tenant == "" || entry.Tenant == f.Tenant) &&
+		(f.Actor == "" || entry.Actor == f.Actor) &&
+		(f.Action == "" || entry.Action == f.Action) &&
+		(f.From.IsZero() || !entry.At.Before(f.From)) &&
+		(f.To.IsZero() || entry.At.Before(f.To))
+}
+
+// AuditRepository stores the audit trail; entries are never changed once written
+type AuditRepository interface {
+	// AppendAudit stores an entry, assigning its ID
+	AppendAudit(entry domain.AuditEntry) (domain.AuditEntry, error)
+	// ListAudit returns one page of matching entries, newest first, and the total number of matches
+	ListAudit(filter AuditFilter) ([]domain.AuditEntry, int, error)
+}
+
+// InMemoryAuditRepository keeps the audit trail in memory
+type InMemoryAuditRepository struct {
+	mu      sync.RWMutex
+	entries []domain.AuditEntry
+}
+
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{}
+}
+
+func (r *InMemoryAuditRepository) AppendAudit(entry domain.AuditEntry) (domain.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.ID = len(r.entries) + 1
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func (r *InMemoryAuditRepository) ListAudit(filter AuditFilter) ([]domain.AuditEntry, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	page := []domain.AuditEntry{}
+	total := 0
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if !filter.Matches(r.entries[i]) {
+			continue
+		}
+		if total >= filter.Offset && (filter.Limit == 0 || len(page) < filter.Limit) {
+			page = append(page, r.entries[i])
+		}
+		total++
+	}
+	return page, total, nil
+}
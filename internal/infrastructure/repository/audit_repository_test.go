@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for audit timestamps
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for audit entries
+)
+
+// AuditRepositoryTestSuite defines the test suite for the audit repository
+type AuditRepositoryTestSuite struct {
+	suite.Suite                          // Embed the testify suite
+	repo        *InMemoryAuditRepository // Repository under test
+	start       time.Time                // Time of the first entry
+}
+
+// SetupTest sets up the test environment before each test
+func (s *AuditRepositoryTestSuite) SetupTest() {
+	s.repo = NewInMemoryAuditRepository()
+	s.start = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []domain.AuditEntry{
+		{Tenant: "default", Actor: "alice", Action: "pack_sizes.update"},
+		{Tenant: "default", Actor: "bob", Action: "quote.create"},
+		{Tenant: "retail", Actor: "alice", Action: "pack_sizes.update"},
+		{Tenant: "default", Actor: "alice", Action: "quote.create"},
+	}
+	for i, entry := range entries {
+		entry.At = s.start.Add(time.Duration(i) * time.Hour)
+		_, err := s.repo.AppendAudit(entry)
+		s.Require().NoError(err, "Expected no error")
+	}
+}
+
+// TestAuditRepositoryTestSuite runs the test suite
+func TestAuditRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditRepositoryTestSuite))
+}
+
+// auditIDs returns the IDs of the given entries
+func auditIDs(entries []domain.AuditEntry) []int {
+	result := make([]int, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.ID
+	}
+	return result
+}
+
+// TestListAuditFilters tests filtering by tenant, actor, action and time
+func (s *AuditRepositoryTestSuite) TestListAuditFilters() {
+	cases := []struct {
+		name     string
+		filter   AuditFilter
+		expected []int
+	}{
+		{name: "All", filter: AuditFilter{}, expected: []int{4, 3, 2, 1}},
+		{name: "Tenant", filter: AuditFilter{Tenant: "default"}, expected: []int{4, 2, 1}},
+		{name: "Actor", filter: AuditFilter{Tenant: "default", Actor: "alice"}, expected: []int{4, 1}},
+		{name: "Action", filter: AuditFilter{Action: "pack_sizes.update"}, expected: []int{3, 1}},
+		{name: "TimeRange", filter: AuditFilter{From: s.start.Add(time.Hour), To: s.start.Add(3 * time.Hour)}, expected: []int{3, 2}},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			entries, total, err := s.repo.ListAudit(tc.filter)
+			s.Assert().NoError(err, "Expected no error")
+			s.Assert().Equal(tc.expected, auditIDs(entries), "Entries should match, newest first")
+			s.Assert().Equal(len(tc.expected), total, "Total should match")
+		})
+	}
+}
+
+// TestListAuditPagination tests offsets and limits
+func (s *AuditRepositoryTestSuite) TestListAuditPagination() {
+	entries, total, err := s.repo.ListAudit(AuditFilter{Offset: 1, Limit: 2})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{3, 2}, auditIDs(entries), "Page should skip the offset")
+	s.Assert().Equal(4, total, "Total should count every match")
+
+	entries, total, err = s.repo.ListAudit(AuditFilter{Offset: 10, Limit: 2})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(entries, "Pages past the end should be empty")
+	s.Assert().Equal(4, total, "Total should count every match")
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// BoltCheck is the result of checking a bolt database
+type BoltCheck struct {
+	Tenants  []BoltTenantCheck // Tenants found, in order
+	Problems []string          // Inconsistencies found; empty when the database is sound
+}
+
+// BoltTenantCheck counts what is stored for a tenant
+type BoltTenantCheck struct {
+	Tenant   string
+	Versions int
+	Changes  int // Entries in the change history
+}
+
+// OpenBoltReadOnly opens the existing bolt database at path without changing it, e.g. to check it. A
+// missing file is an error rather than created, and the database must not be open in another process
+func OpenBoltReadOnly(path string) (*bolt.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return openBoltFile(path, 0o600, &bolt.Options{Timeout: boltLockTimeout, ReadOnly: true})
+}
+
+// CheckBolt reads the whole database and reports every inconsistency: damaged pages, missing buckets,
+// versions that cannot be read or are not numbered 1, 2, 3, ..., a newest ID that does not match the
+// versions, and a history that does not end with the versions stored. Nothing is changed
+func CheckBolt(db *bolt.DB) (BoltCheck, error) {
+	var check BoltCheck
+	err := db.View(func(tx *bolt.Tx) error {
+		for err := range tx.Check() { // Page-level damage, such as pages that are both used and free
+			check.Problems = append(check.Problems, err.Error())
+		}
+
+		tenants := tx.Bucket(boltTenantsBucket)
+		if tenants == nil {
+			return nil // Nothing has been stored yet
+		}
+		return tenants.ForEach(func(name, value []byte) error {
+			if value != nil {
+				check.Problems = append(check.Problems, fmt.Sprintf("tenants: %q is a value, not a tenant bucket", name))
+				return nil
+			}
+			tenant := BoltTenantCheck{Tenant: string(name)}
+			problems := checkBoltTenant(tenants.Bucket(name), &tenant)
+			for _, problem := range problems {
+				check.Problems = append(check.Problems, fmt.Sprintf("tenant %q: %s", name, problem))
+			}
+			check.Tenants = append(check.Tenants, tenant)
+			return nil
+		})
+	})
+	sort.Slice(check.Tenants, func(i, j int) bool { return check.Tenants[i].Tenant < check.Tenants[j].Tenant })
+	return check, err
+}
+
+// checkBoltTenant checks one tenant's buckets, counting what it finds
+func checkBoltTenant(bucket *bolt.Bucket, tenant *BoltTenantCheck) []string {
+	var problems []string
+	current, versions, history := bucket.Bucket(boltCurrentBucket), bucket.Bucket(boltVersionsBucket), bucket.Bucket(boltHistoryBucket)
+	if current == nil || versions == nil || history == nil {
+		return []string{"current, versions or history bucket is missing"}
+	}
+
+	versions.ForEach(func(key, data []byte) error {
+		tenant.Versions++
+		var version domain.PackSizeVersion
+		switch {
+		case boltID(key) != tenant.Versions:
+			problems = append(problems, fmt.Sprintf("version %d is stored under key %x", tenant.Versions, key))
+		case json.Unmarshal(data, &version) != nil:
+			problems = append(problems, fmt.Sprintf("version %d cannot be read", tenant.Versions))
+		case version.ID != tenant.Versions:
+			problems = append(problems, fmt.Sprintf("version %d holds ID %d", tenant.Versions, version.ID))
+		case len(version.PackSizes) == 0:
+			problems = append(problems, fmt.Sprintf("version %d has no pack sizes", tenant.Versions))
+		}
+		return nil
+	})
+	if tenant.Versions == 0 {
+		problems = append(problems, "no versions are stored")
+	}
+	if latest := boltID(current.Get(boltLatestKey)); latest != tenant.Versions {
+		problems = append(problems, fmt.Sprintf("newest version is recorded as %d, but %d are stored", latest, tenant.Versions))
+	}
+
+	// Entries describe the versions as they were then, since a replace drops versions saved before it,
+	// so only the last entry has to match what is stored now
+	last := -1
+	history.ForEach(func(key, data []byte) error {
+		tenant.Changes++
+		var change boltChange
+		switch {
+		case json.Unmarshal(data, &change) != nil:
+			problems = append(problems, fmt.Sprintf("history entry %d cannot be read", boltID(key)))
+		case change.Change == boltChangeSaved && change.VersionID != change.Versions:
+			problems = append(problems, fmt.Sprintf("history entry %d saves version %d, but records %d versions", boltID(key), change.VersionID, change.Versions))
+		default:
+			last = change.Versions
+		}
+		return nil
+	})
+	if last >= 0 && last != tenant.Versions {
+		problems = append(problems, fmt.Sprintf("history ends with %d versions, but %d are stored", last, tenant.Versions))
+	}
+	return problems
+}
+
+// boltCompactTxSize is how much is copied per transaction while compacting
+const boltCompactTxSize = 64 << 20
+
+// CompactBolt rewrites the bolt database at path without the space freed by earlier changes, which bolt
+// reuses but never returns to the file system. The copy is written next to the database and renamed
+// over it, so a failure leaves the original in place. The database must not be open, in this process or
+// another, and the sizes before and after are returned
+func CompactBolt(path string) (before, after int64, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	src, err := openBoltFile(path, 0o600, &bolt.Options{Timeout: boltLockTimeout, ReadOnly: true})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".compact-*")
+	if err != nil {
+		return 0, 0, err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()              // bolt opens the file itself
+	defer os.Remove(tmpPath) // No-op once the rename succeeded
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return 0, 0, err
+	}
+
+	dst, err := bolt.Open(tmpPath, info.Mode().Perm(), &bolt.Options{Timeout: boltLockTimeout})
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := bolt.Compact(dst, src, boltCompactTxSize); err != nil {
+		dst.Close()
+		return 0, 0, err
+	}
+	if err := dst.Close(); err != nil {
+		return 0, 0, err
+	}
+	compacted, err := os.Stat(tmpPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, 0, err
+	}
+	return info.Size(), compacted.Size(), nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"os"            // Import os for checking that files are not created
+	"path/filepath" // Import filepath for the database path
+	"testing"       // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	bolt "go.etcd.io/bbolt"                  // Import bbolt for damaging the test database
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// BoltMaintenanceTestSuite defines the test suite for checking and compacting bolt databases
+type BoltMaintenanceTestSuite struct {
+	suite.Suite        // Embed the testify suite
+	path        string // Path of the database file
+}
+
+// SetupTest sets up the test environment before each test
+func (s *BoltMaintenanceTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "packs.bolt")
+}
+
+// TestBoltMaintenanceTestSuite runs the test suite
+func TestBoltMaintenanceTestSuite(t *testing.T) {
+	suite.Run(t, new(BoltMaintenanceTestSuite))
+}
+
+// TestCheck tests that a sound database passes and damaged tenants are reported
+func (s *BoltMaintenanceTestSuite) TestCheck() {
+	db, err := OpenBolt(s.path)
+	s.Require().NoError(err, "Expected no error")
+	defer db.Close()
+
+	check, err := CheckBolt(db)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Empty(check.Tenants, "An empty database should have no tenants")
+	s.Assert().Empty(check.Problems, "An empty database should be sound")
+
+	repo, err := NewBoltPackRepository(db, "default", []int{250})
+	s.Require().NoError(err, "Expected no error")
+	_, err = repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}})
+	s.Require().NoError(err, "Expected no error")
+	_, err = NewBoltPackRepository(db, "acme", []int{5})
+	s.Require().NoError(err, "Expected no error")
+
+	check, err = CheckBolt(db)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]BoltTenantCheck{
+		{Tenant: "acme", Versions: 1, Changes: 1},
+		{Tenant: "default", Versions: 2, Changes: 2},
+	}, check.Tenants, "Tenants should be counted")
+	s.Assert().Empty(check.Problems, "Database should be sound")
+
+	// Damage the default tenant behind the repository's back
+	err = db.Update(func(tx *bolt.Tx) error {
+		tenant := tx.Bucket(boltTenantsBucket).Bucket([]byte("default"))
+		if err := tenant.Bucket(boltVersionsBucket).Put(boltKey(2), []byte("{")); err != nil {
+			return err
+		}
+		return tenant.Bucket(boltCurrentBucket).Put(boltLatestKey, boltKey(5))
+	})
+	s.Require().NoError(err, "Expected no error")
+
+	check, err = CheckBolt(db)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]string{
+		`tenant "default": version 2 cannot be read`,
+		`tenant "default": newest version is recorded as 5, but 2 are stored`,
+	}, check.Problems, "Every problem should be reported")
+}
+
+// TestOpenReadOnly tests that checking opens an existing database without creating or changing anything
+func (s *BoltMaintenanceTestSuite) TestOpenReadOnly() {
+	_, err := OpenBoltReadOnly(s.path)
+	s.Assert().ErrorIs(err, os.ErrNotExist, "A missing database should be reported")
+	_, err = os.Stat(s.path)
+	s.Assert().ErrorIs(err, os.ErrNotExist, "A missing database should not be created")
+
+	db, err := OpenBolt(s.path)
+	s.Require().NoError(err, "Expected no error")
+	_, err = NewBoltPackRepository(db, "default", []int{250})
+	s.Require().NoError(err, "Expected no error")
+	s.Require().NoError(db.Close(), "Expected no error")
+
+	db, err = OpenBoltReadOnly(s.path)
+	s.Require().NoError(err, "Expected no error")
+	defer db.Close()
+	check, err := CheckBolt(db)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]BoltTenantCheck{{Tenant: "default", Versions: 1, Changes: 1}}, check.Tenants, "Existing tenants should be read")
+	err = db.Update(func(tx *bolt.Tx) error { return nil })
+	s.Assert().ErrorIs(err, bolt.ErrDatabaseReadOnly, "The database should not be writable")
+}
+
+// TestCompact tests that compacting returns freed space and keeps every version
+func (s *BoltMaintenanceTestSuite) TestCompact() {
+	db, err := OpenBolt(s.path)
+	s.Require().NoError(err, "Expected no error")
+	repo, err := NewBoltPackRepository(db, "default", []int{250})
+	s.Require().NoError(err, "Expected no error")
+	sizes := make([]int, 5000)
+	for i := range sizes {
+		sizes[i] = i + 1
+	}
+	for i := 0; i < 20; i++ { // Leave pages behind that later versions no longer need
+		_, err := repo.SaveVersion(domain.PackSizeVersion{PackSizes: sizes})
+		s.Require().NoError(err, "Expected no error")
+	}
+	s.Require().NoError(repo.ReplaceVersions([]domain.PackSizeVersion{{ID: 1, PackSizes: []int{100}}}), "Expected no error")
+
+	_, _, err = CompactBolt(s.path)
+	s.Assert().ErrorContains(err, "in use by another process", "Open databases should not be compacted")
+	s.Require().NoError(db.Close(), "Expected no error")
+
+	before, after, err := CompactBolt(s.path)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Less(after, before, "Compacted file should be smaller")
+
+	db, err = OpenBolt(s.path)
+	s.Require().NoError(err, "Expected no error")
+	defer db.Close()
+	repo, err = NewBoltPackRepository(db, "default", []int{250})
+	s.Require().NoError(err, "Expected no error")
+	versions, err := repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(versions, 1, "Versions should survive compaction")
+	s.Assert().Equal([]int{100}, versions[0].PackSizes, "Sizes should survive compaction")
+	check, err := CheckBolt(db)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(check.Problems, "Compacted database should be sound")
+}
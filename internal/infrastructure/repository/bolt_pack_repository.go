@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// Layout of a bolt database: one bucket per tenant under boltTenantsBucket, each holding
+//
+//	current   boltLatestKey: ID of the newest version
+//	versions  ID: version, as JSON
+//	history   sequence: boltChange, as JSON, one per save or replace
+//	outbox    sequence: pending event, as JSON; published events are deleted
+//
+// IDs and sequences are big-endian, so keys sort in numeric order
+var (
+	boltTenantsBucket  = []byte("tenants")
+	boltCurrentBucket  = []byte("current")
+	boltVersionsBucket = []byte("versions")
+	boltHistoryBucket  = []byte("history")
+	boltOutboxBucket   = []byte("outbox")
+	boltLatestKey      = []byte("latest")
+)
+
+// Kinds of boltChange
+const (
+	boltChangeSaved    = "saved"
+	boltChangeReplaced = "replaced"
+)
+
+// boltChange records one change to a tenant's versions
+type boltChange struct {
+	At        time.Time `json:"at"`
+	Change    string    `json:"change"`              // boltChangeSaved or boltChangeReplaced
+	VersionID int       `json:"versionId,omitempty"` // Version saved; unset for replaces
+	Versions  int       `json:"versions"`            // Versions stored after the change
+}
+
+// boltLockTimeout bounds the wait for the file lock, which another process holding the database keeps
+const boltLockTimeout = time.Second
+
+// OpenBolt opens (creating if needed) the bolt database at path. The file is locked while it is open,
+// so a second process fails after boltLockTimeout instead of waiting forever
+func OpenBolt(path string) (*bolt.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return openBoltFile(path, 0o600, &bolt.Options{Timeout: boltLockTimeout})
+}
+
+// openBoltFile opens a bolt database, explaining a lock timeout
+func openBoltFile(path string, mode os.FileMode, options *bolt.Options) (*bolt.DB, error) {
+	db, err := bolt.Open(path, mode, options)
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("opening %s: the database is in use by another process", path)
+	}
+	return db, err
+}
+
+// BoltPackRepository stores the pack-size versions and pending events of one tenant in a bolt database
+// shared by all tenants. Every change is a single transaction, synced before it returns
+type BoltPackRepository struct {
+	db     *bolt.DB
+	tenant []byte
+	feed   changeFeed
+}
+
+// NewBoltPackRepository returns the repository of a tenant, saving a first version with the default
+// sizes when the tenant has none yet
+func NewBoltPackRepository(db *bolt.DB, tenant string, defaultSizes []int) (*BoltPackRepository, error) {
+	r := &BoltPackRepository{db: db, tenant: []byte(tenant)}
+
+	latest, err := r.LatestVersionID()
+	if err != nil {
+		return nil, err
+	}
+	if latest == 0 {
+		if _, err := r.SaveVersion(domain.PackSizeVersion{PackSizes: defaultSizes, Author: InitialVersionAuthor}); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *BoltPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	versions, err := r.ListVersions()
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return domain.ActiveVersion(versions, at)
+}
+
+func (r *BoltPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	return r.save(version, AnyLatestVersion, nil)
+}
+
+func (r *BoltPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	return r.save(version, latestID, nil)
+}
+
+func (r *BoltPackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	return r.save(version, latestID, newEvent)
+}
+
+func (r *BoltPackRepository) LatestVersionID() (int, error) {
+	latest := 0
+	err := r.db.View(func(tx *bolt.Tx) error {
+		if current := r.bucket(tx, boltCurrentBucket); current != nil {
+			latest = boltID(current.Get(boltLatestKey))
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// save appends a version with the next ID, unless latestID is set and no longer the newest ID, and its
+// event when newEvent is set. bolt runs one write transaction at a time, so the check and the append
+// cannot interleave with another save
+func (r *BoltPackRepository) save(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	version = copyVersion(version)
+	version.CreatedAt = time.Now()
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		tenant, err := r.createBuckets(tx)
+		if err != nil {
+			return err
+		}
+		latest := boltID(tenant.Bucket(boltCurrentBucket).Get(boltLatestKey))
+		if latestID != AnyLatestVersion && latest != latestID {
+			return ErrVersionConflict
+		}
+		version.ID = latest + 1
+		if err := putBoltVersion(tenant.Bucket(boltVersionsBucket), version); err != nil {
+			return err
+		}
+		if err := tenant.Bucket(boltCurrentBucket).Put(boltLatestKey, boltKey(version.ID)); err != nil {
+			return err
+		}
+		if newEvent != nil {
+			event, err := newEvent(copyVersion(version))
+			if err != nil {
+				return err
+			}
+			if _, err := putBoltEvent(tenant.Bucket(boltOutboxBucket), event); err != nil {
+				return err
+			}
+		}
+		return appendBoltChange(tenant.Bucket(boltHistoryBucket), boltChange{At: version.CreatedAt, Change: boltChangeSaved, VersionID: version.ID, Versions: version.ID})
+	})
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	r.feed.publish(version)
+	return copyVersion(version), nil
+}
+
+func (r *BoltPackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		tenant, err := r.createBuckets(tx)
+		if err != nil {
+			return err
+		}
+		event, err = putBoltEvent(tenant.Bucket(boltOutboxBucket), event)
+		return err
+	})
+	if err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	return event, nil
+}
+
+func (r *BoltPackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	events := []domain.EventEnvelope{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := r.bucket(tx, boltOutboxBucket)
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for key, data := cursor.First(); key != nil && (limit <= 0 || len(events) < limit); key, data = cursor.Next() {
+			var event domain.EventEnvelope
+			if err := json.Unmarshal(data, &event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *BoltPackRepository) MarkEventPublished(id int) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		bucket := r.bucket(tx, boltOutboxBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.Delete(boltKey(id))
+	})
+}
+
+func (r *BoltPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	var version domain.PackSizeVersion
+	err := r.db.View(func(tx *bolt.Tx) error {
+		versions := r.bucket(tx, boltVersionsBucket)
+		if versions == nil || id < 1 {
+			return ErrVersionNotFound
+		}
+		data := versions.Get(boltKey(id))
+		if data == nil {
+			return ErrVersionNotFound
+		}
+		return json.Unmarshal(data, &version)
+	})
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return version, nil
+}
+
+func (r *BoltPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	versions := []domain.PackSizeVersion{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := r.bucket(tx, boltVersionsBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_, data []byte) error {
+			var version domain.PackSizeVersion
+			if err := json.Unmarshal(data, &version); err != nil {
+				return err
+			}
+			versions = append(versions, version)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// ReplaceVersions swaps the tenant's versions in one transaction, so readers see either all old or all new versions
+func (r *BoltPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	if err := CheckVersions(versions); err != nil {
+		return err
+	}
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		tenant, err := r.createBuckets(tx)
+		if err != nil {
+			return err
+		}
+		if err := tenant.DeleteBucket(boltVersionsBucket); err != nil {
+			return err
+		}
+		bucket, err := tenant.CreateBucket(boltVersionsBucket)
+		if err != nil {
+			return err
+		}
+		for _, version := range versions {
+			if err := putBoltVersion(bucket, version); err != nil {
+				return err
+			}
+		}
+		if err := tenant.Bucket(boltCurrentBucket).Put(boltLatestKey, boltKey(len(versions))); err != nil {
+			return err
+		}
+		return appendBoltChange(tenant.Bucket(boltHistoryBucket), boltChange{At: time.Now(), Change: boltChangeReplaced, Versions: len(versions)})
+	})
+	if err != nil {
+		return err
+	}
+	r.feed.publish(copyVersion(versions[len(versions)-1]))
+	return nil
+}
+
+func (r *BoltPackRepository) Watch(ctx context.Context) <-chan PackSizesChange {
+	return r.feed.watch(ctx)
+}
+
+// bucket returns one of the tenant's buckets, or nil when the tenant has none yet
+func (r *BoltPackRepository) bucket(tx *bolt.Tx, name []byte) *bolt.Bucket {
+	tenants := tx.Bucket(boltTenantsBucket)
+	if tenants == nil {
+		return nil
+	}
+	tenant := tenants.Bucket(r.tenant)
+	if tenant == nil {
+		return nil
+	}
+	return tenant.Bucket(name)
+}
+
+// createBuckets returns the tenant's bucket, creating it and the buckets inside it when missing
+func (r *BoltPackRepository) createBuckets(tx *bolt.Tx) (*bolt.Bucket, error) {
+	tenants, err := tx.CreateBucketIfNotExists(boltTenantsBucket)
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := tenants.CreateBucketIfNotExists(r.tenant)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range [][]byte{boltCurrentBucket, boltVersionsBucket, boltHistoryBucket, boltOutboxBucket} {
+		if _, err := tenant.CreateBucketIfNotExists(name); err != nil {
+			return nil, err
+		}
+	}
+	return tenant, nil
+}
+
+// putBoltVersion stores a version under its ID
+func putBoltVersion(bucket *bolt.Bucket, version domain.PackSizeVersion) error {
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(boltKey(version.ID), data)
+}
+
+// putBoltEvent stores an event under the next sequence of the outbox, which becomes its ID
+func putBoltEvent(bucket *bolt.Bucket, event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	event.ID = int(seq)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	return event, bucket.Put(boltKey(event.ID), data)
+}
+
+// appendBoltChange adds a change to the end of the history
+func appendBoltChange(bucket *bolt.Bucket, change boltChange) error {
+	seq, err := bucket.NextSequence()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(boltKey(int(seq)), data)
+}
+
+// boltKey encodes an ID or sequence as a key
+func boltKey(id int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(id))
+	return key
+}
+
+// boltID decodes a key written by boltKey; missing or malformed keys read as 0
+func boltID(key []byte) int {
+	if len(key) != 8 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint64(key))
+}
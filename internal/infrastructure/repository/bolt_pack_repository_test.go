@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"path/filepath" // Import filepath for the database path
+	"sync"          // Import sync for concurrent writers
+	"testing"       // Import the testing package for writing unit tests
+	"time"          // Import time for effective dates
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	bolt "go.etcd.io/bbolt"                  // Import bbolt for the test database
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// BoltPackRepositoryTestSuite defines the test suite for the bolt pack repository
+type BoltPackRepositoryTestSuite struct {
+	suite.Suite                     // Embed the testify suite
+	path        string              // Path of the database file
+	db          *bolt.DB            // Open database
+	repo        *BoltPackRepository // Repository of the default tenant
+}
+
+// SetupTest sets up the test environment before each test
+func (s *BoltPackRepositoryTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "data", "packs.bolt")
+	s.open()
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *BoltPackRepositoryTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+// TestBoltPackRepositoryTestSuite runs the test suite
+func TestBoltPackRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BoltPackRepositoryTestSuite))
+}
+
+// open opens the database and creates the default tenant's repository
+func (s *BoltPackRepositoryTestSuite) open() {
+	db, err := OpenBolt(s.path)
+	s.Require().NoError(err, "Expected no error")
+	s.db = db
+	s.repo, err = NewBoltPackRepository(db, "default", []int{250, 500, 1000})
+	s.Require().NoError(err, "Expected no error")
+}
+
+// TestVersions tests saving, looking up and listing versions
+func (s *BoltPackRepositoryTestSuite) TestVersions() {
+	initial, err := s.repo.GetVersion(1)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500, 1000}, initial.PackSizes, "Seeded version should hold the defaults")
+	s.Assert().Equal(InitialVersionAuthor, initial.Author, "Seeded version should be attributed to the config")
+
+	saved, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100, 200}, Author: "alice", ApprovedBy: "bob", RollbackOf: 1})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, saved.ID, "New version should follow the seeded one")
+
+	stored, err := s.repo.GetVersion(2)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, stored.PackSizes, "Sizes should be stored")
+	s.Assert().Equal("bob", stored.ApprovedBy, "Approver should be stored")
+	s.Assert().Equal(1, stored.RollbackOf, "Rollback source should be stored")
+	s.Assert().True(saved.CreatedAt.Equal(stored.CreatedAt), "Creation time should be stored")
+
+	versions, err := s.repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "Versions should be listed oldest first")
+
+	_, err = s.repo.GetVersion(3)
+	s.Assert().Equal(ErrVersionNotFound, err, "Expected version not found")
+	_, err = s.repo.GetVersion(0)
+	s.Assert().Equal(ErrVersionNotFound, err, "Expected version not found")
+}
+
+// TestActiveVersion tests that scheduled versions only apply from their effective date
+func (s *BoltPackRepositoryTestSuite) TestActiveVersion() {
+	now := time.Now()
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}, EffectiveFrom: now})
+	s.Require().NoError(err, "Expected no error")
+	_, err = s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{200}, EffectiveFrom: now.Add(time.Hour)})
+	s.Require().NoError(err, "Expected no error")
+
+	active, err := s.repo.GetActiveVersion(now.Add(-time.Minute))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(1, active.ID, "Only the seeded version applies before the first change")
+
+	active, err = s.repo.GetActiveVersion(now)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(2, active.ID, "Immediate change should apply")
+
+	active, err = s.repo.GetActiveVersion(now.Add(time.Hour))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(3, active.ID, "Scheduled change should apply from its effective date")
+}
+
+// TestPersistenceAndTenants tests that versions survive reopening and tenants do not see each other's versions
+func (s *BoltPackRepositoryTestSuite) TestPersistenceAndTenants() {
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}})
+	s.Require().NoError(err, "Expected no error")
+	s.db.Close()
+	s.open() // Reopening must not seed the defaults again
+
+	versions, err := s.repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "Versions should survive reopening")
+
+	other, err := NewBoltPackRepository(s.db, "wholesale", []int{5000})
+	s.Require().NoError(err, "Expected no error")
+	versions, err = other.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(versions, 1, "New tenant should only see its own seeded version")
+	s.Assert().Equal([]int{5000}, versions[0].PackSizes, "New tenant should start with its defaults")
+}
+
+// TestLocked tests that a database open in another process is reported instead of waited for
+func (s *BoltPackRepositoryTestSuite) TestLocked() {
+	_, err := OpenBolt(s.path) // bolt locks the file per open, so a second open in this process conflicts too
+	s.Assert().ErrorContains(err, "in use by another process", "Expected the lock to be reported")
+}
+
+// TestSaveVersionIfLatest tests saving only on top of the newest version
+func (s *BoltPackRepositoryTestSuite) TestSaveVersionIfLatest() {
+	testSaveVersionIfLatest(&s.Suite, s.repo)
+}
+
+// TestWatch tests that saved versions are reported to watchers
+func (s *BoltPackRepositoryTestSuite) TestWatch() {
+	testWatch(&s.Suite, s.repo)
+}
+
+// TestOutbox tests saving versions together with their events, and that pending events survive reopening
+func (s *BoltPackRepositoryTestSuite) TestOutbox() {
+	testOutbox(&s.Suite, s.repo)
+
+	_, err := s.repo.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed})
+	s.Require().NoError(err, "Expected no error")
+	s.db.Close()
+	s.open()
+	pending, err := s.repo.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(pending, 1, "Undelivered event should survive reopening")
+}
+
+// TestReplaceVersions tests replacing one tenant's versions without touching the others
+func (s *BoltPackRepositoryTestSuite) TestReplaceVersions() {
+	other, err := NewBoltPackRepository(s.db, "other", []int{42})
+	s.Require().NoError(err, "Expected no error")
+
+	testReplaceVersions(&s.Suite, s.repo)
+	versions, err := other.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 1, "Other tenants should keep their versions")
+}
+
+// TestConcurrentSaves tests that concurrent writers get unique, sequential IDs
+func (s *BoltPackRepositoryTestSuite) TestConcurrentSaves() {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{i + 1}})
+			s.Assert().NoError(err, "Expected no error")
+		}(i)
+	}
+	wg.Wait()
+
+	versions, err := s.repo.ListVersions()
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(versions, 21, "Every save should be kept")
+	for i, version := range versions {
+		s.Assert().Equal(i+1, version.ID, "IDs should be unique and sequential")
+	}
+}
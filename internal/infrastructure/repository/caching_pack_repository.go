@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// CachingPackRepository is a read-through cache in front of another PackRepository. All versions are
+// read at once and kept for ttl, so calculations stop reaching a remote store on every call. Saves made
+// through the cache drop it right away; changes made elsewhere, e.g. by another replica, show up once
+// the ttl runs out, or as soon as a conditional save through the cache runs into them
+type CachingPackRepository struct {
+	next PackRepository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu         sync.RWMutex
+	versions   []domain.PackSizeVersion // Cached versions; nil when they must be loaded again
+	loadedAt   time.Time
+	generation int // Bumped on every invalidation, so a load racing one is not cached
+}
+
+func NewCachingPackRepository(next PackRepository, ttl time.Duration) *CachingPackRepository {
+	return &CachingPackRepository{next: next, ttl: ttl, now: time.Now}
+}
+
+func (r *CachingPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	versions, err := r.load()
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	version, err := domain.ActiveVersion(versions, at)
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return copyVersion(version), nil
+}
+
+func (r *CachingPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	defer r.Invalidate()
+	return r.next.SaveVersion(version)
+}
+
+func (r *CachingPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	// Dropped on a conflict too: the cache evidently missed a change, and the caller is about to reload
+	defer r.Invalidate()
+	return r.next.SaveVersionIfLatest(version, latestID)
+}
+
+func (r *CachingPackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	defer r.Invalidate()
+	return r.next.SaveVersionWithEvent(version, latestID, newEvent)
+}
+
+func (r *CachingPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	defer r.Invalidate()
+	return r.next.ReplaceVersions(versions)
+}
+
+func (r *CachingPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	versions, err := r.load()
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	if id >= 1 && id <= len(versions) {
+		return copyVersion(versions[id-1]), nil
+	}
+	return r.next.GetVersion(id) // The version may have been saved since the cache was filled
+}
+
+func (r *CachingPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	cached, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]domain.PackSizeVersion, len(cached))
+	for i, version := range cached {
+		versions[i] = copyVersion(version)
+	}
+	return versions, nil
+}
+
+// LatestVersionID is read from the cache, so it always matches the versions the cache serves
+func (r *CachingPackRepository) LatestVersionID() (int, error) {
+	versions, err := r.load()
+	return len(versions), err
+}
+
+// Events are not cached: only the relay reads them, and it must see every one
+
+func (r *CachingPackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	return r.next.AppendEvent(event)
+}
+
+func (r *CachingPackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	return r.next.ListPendingEvents(limit)
+}
+
+func (r *CachingPackRepository) MarkEventPublished(id int) error {
+	return r.next.MarkEventPublished(id)
+}
+
+// Invalidate drops the cache so the next read goes to the wrapped repository
+func (r *CachingPackRepository) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions = nil
+	r.generation++
+}
+
+// load returns the cached versions, reading them again once they are older than the ttl.
+// The returned slice is shared and must not be modified
+func (r *CachingPackRepository) load() ([]domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	versions, loadedAt, generation := r.versions, r.loadedAt, r.generation
+	r.mu.RUnlock()
+	if versions != nil && r.now().Sub(loadedAt) < r.ttl {
+		return versions, nil
+	}
+
+	loadedAt = r.now()
+	versions, err := r.next.ListVersions()
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil { // Keep nil meaning "not cached"
+		versions = []domain.PackSizeVersion{}
+	}
+
+	r.mu.Lock()
+	if r.generation == generation { // Only cache what was read if nothing was invalidated meanwhile
+		r.versions, r.loadedAt = versions, loadedAt
+	}
+	r.mu.Unlock()
+	return versions, nil
+}
+
+// Unwrap returns the cached repository, so WatcherOf finds its capabilities
+func (r *CachingPackRepository) Unwrap() PackRepository {
+	return r.next
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for the cache ttl
+
+	"github.com/stretchr/testify/assert"     // Import assert for its sample error
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// stubPackRepository wraps an in-memory repository, counting the calls to each method and failing
+// them with queued errors
+type stubPackRepository struct {
+	PackRepository                    // Repository the calls go to
+	calls          map[string]int     // Calls by method
+	errors         map[string][]error // Errors the next calls of a method fail with
+}
+
+// newStubPackRepository returns a stub seeded with one version
+func newStubPackRepository() *stubPackRepository {
+	return &stubPackRepository{
+		PackRepository: NewInMemoryPackRepository([]int{250, 500}),
+		calls:          map[string]int{},
+		errors:         map[string][]error{},
+	}
+}
+
+// call counts a call and returns the error it should fail with, if any
+func (r *stubPackRepository) call(method string) error {
+	r.calls[method]++
+	if queued := r.errors[method]; len(queued) > 0 {
+		r.errors[method] = queued[1:]
+		return queued[0]
+	}
+	return nil
+}
+
+func (r *stubPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	if err := r.call("GetActiveVersion"); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return r.PackRepository.GetActiveVersion(at)
+}
+
+func (r *stubPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	if err := r.call("SaveVersion"); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return r.PackRepository.SaveVersion(version)
+}
+
+func (r *stubPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	if err := r.call("SaveVersionIfLatest"); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return r.PackRepository.SaveVersionIfLatest(version, latestID)
+}
+
+func (r *stubPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	if err := r.call("GetVersion"); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return r.PackRepository.GetVersion(id)
+}
+
+func (r *stubPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	if err := r.call("ListVersions"); err != nil {
+		return nil, err
+	}
+	return r.PackRepository.ListVersions()
+}
+
+func (r *stubPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	if err := r.call("ReplaceVersions"); err != nil {
+		return err
+	}
+	return r.PackRepository.ReplaceVersions(versions)
+}
+
+func (r *stubPackRepository) LatestVersionID() (int, error) {
+	if err := r.call("LatestVersionID"); err != nil {
+		return 0, err
+	}
+	return r.PackRepository.LatestVersionID()
+}
+
+// CachingPackRepositoryTestSuite defines the test suite for the caching decorator
+type CachingPackRepositoryTestSuite struct {
+	suite.Suite                        // Embed the testify suite
+	next        *stubPackRepository    // Wrapped repository
+	repo        *CachingPackRepository // Decorator under test
+	now         time.Time              // Fixed clock of the cache
+}
+
+// SetupTest sets up the test environment before each test
+func (s *CachingPackRepositoryTestSuite) SetupTest() {
+	s.next = newStubPackRepository()
+	s.repo = NewCachingPackRepository(s.next, time.Minute)
+	s.now = time.Now()
+	s.repo.now = func() time.Time { return s.now }
+}
+
+// TestCachingPackRepositoryTestSuite runs the test suite
+func TestCachingPackRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CachingPackRepositoryTestSuite))
+}
+
+// latest returns the newest version ID the cache serves
+func (s *CachingPackRepositoryTestSuite) latest() int {
+	latest, err := s.repo.LatestVersionID()
+	s.Require().NoError(err, "Expected no error")
+	return latest
+}
+
+// TestReadThrough tests that reads within the ttl are served from one load
+func (s *CachingPackRepositoryTestSuite) TestReadThrough() {
+	active, err := s.repo.GetActiveVersion(s.now)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500}, active.PackSizes, "Seeded version should be active")
+	first, err := s.repo.GetVersion(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(1, first.ID, "Version should be served from the cache")
+	s.Assert().Equal(1, s.latest(), "Newest ID should come from the cache")
+	versions, err := s.repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 1, "All versions should be listed")
+	s.Assert().Equal(map[string]int{"ListVersions": 1}, s.next.calls, "Every read should be served from one load")
+
+	// Callers cannot change what the cache holds
+	versions[0].PackSizes[0] = 1
+	first, _ = s.repo.GetVersion(1)
+	s.Assert().Equal([]int{250, 500}, first.PackSizes, "Cached versions should be copied")
+}
+
+// TestExpiry tests that changes made elsewhere are read once the ttl runs out
+func (s *CachingPackRepositoryTestSuite) TestExpiry() {
+	s.Require().Equal(1, s.latest(), "Expected the seeded version")
+	s.next.PackRepository.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}}) // Bypasses the cache, like another replica
+
+	s.now = s.now.Add(59 * time.Second)
+	s.Assert().Equal(1, s.latest(), "Cache should be used within the ttl")
+	s.now = s.now.Add(time.Second)
+	s.Assert().Equal(2, s.latest(), "Cache should be filled again after the ttl")
+}
+
+// TestInvalidationOnSave tests that saves through the cache, including conflicting ones, drop it
+func (s *CachingPackRepositoryTestSuite) TestInvalidationOnSave() {
+	s.Require().Equal(1, s.latest(), "Expected the seeded version")
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, s.latest(), "Saved version should be read right after the save")
+
+	s.next.PackRepository.SaveVersion(domain.PackSizeVersion{PackSizes: []int{7}}) // Bypasses the cache
+	_, err = s.repo.SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{5}}, 2)
+	s.Assert().ErrorIs(err, ErrVersionConflict, "Conflict should be passed on")
+	s.Assert().Equal(3, s.latest(), "Change missed by the cache should be read after the conflict")
+}
+
+// TestInvalidationOnReplace tests that replacing the versions through the cache drops it
+func (s *CachingPackRepositoryTestSuite) TestInvalidationOnReplace() {
+	s.Require().Equal(1, s.latest(), "Expected the seeded version")
+	versions := []domain.PackSizeVersion{{ID: 1, PackSizes: []int{1}}, {ID: 2, PackSizes: []int{2}}}
+	s.Require().NoError(s.repo.ReplaceVersions(versions), "Expected no error")
+	s.Assert().Equal(2, s.latest(), "Replaced versions should be read right after the replace")
+	s.Assert().Equal(1, s.next.calls["ReplaceVersions"], "Replace should reach the wrapped repository")
+}
+
+// TestUncachedVersion tests that versions saved since the cache was filled are looked up
+func (s *CachingPackRepositoryTestSuite) TestUncachedVersion() {
+	s.Require().Equal(1, s.latest(), "Expected the seeded version")
+	s.next.PackRepository.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}}) // Bypasses the cache
+
+	version, err := s.repo.GetVersion(2)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100}, version.PackSizes, "Version should be looked up in the wrapped repository")
+}
+
+// TestLoadError tests that failed loads are reported and not cached
+func (s *CachingPackRepositoryTestSuite) TestLoadError() {
+	s.next.errors["ListVersions"] = []error{assert.AnError}
+
+	_, err := s.repo.GetActiveVersion(s.now)
+	s.Assert().Equal(assert.AnError, err, "Expected the load error")
+	_, err = s.repo.GetActiveVersion(s.now)
+	s.Assert().NoError(err, "Next read should load again")
+}
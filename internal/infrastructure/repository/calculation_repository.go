@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// CalculationFilter selects calculation records; zero fields match everything
+type CalculationFilter struct {
+	OrderAmount     int
+	PackSizeVersion int
+	From            time.Time // Inclusive
+	To              time.Time // Exclusive
+	Offset          int
+	Limit           int // Zero means no limit
+}
+
+// Matches reports whether a record passes the filter, ignoring pagination
+func (f CalculationFilter) Matches(record domain.CalculationRecord) bool {
+	return (f.OrderAmount == 0 || record.OrderAmount == f.OrderAmount) &&
+		(f.PackSizeVersion == 0 || record.PackSizeVersion == f.PackSizeVersion) &&
+		(f.From.IsZero() || !record.CalculatedAt.Before(f.From)) &&
+		(f.To.IsZero() || record.CalculatedAt.Before(f.To))
+}
+
+// CalculationRepository stores the history of served calculations
+type CalculationRepository interface {
+	// SaveCalculation stores a record, assigning its ID
+	SaveCalculation(record domain.CalculationRecord) (domain.CalculationRecord, error)
+	// GetCalculation returns the record with the given ID
+	GetCalculation(id int) (domain.CalculationRecord, error)
+	// ListCalculations returns one page of matching records, newest first, and the total number of matches
+	ListCalculations(filter CalculationFilter) ([]domain.CalculationRecord, int, error)
+}
+
+// ErrCalculationNotFound is returned when a calculation record does not exist
+var ErrCalculationNotFound = errors.New("calculation not found")
+
+// InMemoryCalculationRepository keeps the most recent calculations in memory
+type InMemoryCalculationRepository struct {
+	mu       sync.RWMutex
+	capacity int
+	nextID   int
+	records  []domain.CalculationRecord
+}
+
+// NewInMemoryCalculationRepository keeps at most capacity records, dropping the oldest; zero keeps everything
+func NewInMemoryCalculationRepository(capacity int) *InMemoryCalculationRepository {
+	return &InMemoryCalculationRepository{capacity: capacity, nextID: 1}
+}
+
+func (r *InMemoryCalculationRepository) SaveCalculation(record domain.CalculationRecord) (domain.CalculationRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record.ID = r.nextID
+	r.nextID++
+	r.records = append(r.records, record)
+	if r.capacity > 0 && len(r.records) > r.capacity {
+		r.records = append([]domain.CalculationRecord(nil), r.records[len(r.records)-r.capacity:]...)
+	}
+	return record, nil
+}
+
+func (r *InMemoryCalculationRepository) GetCalculation(id int) (domain.CalculationRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, record := range r.records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return domain.CalculationRecord{}, ErrCalculationNotFound
+}
+
+func (r *InMemoryCalculationRepository) ListCalculations(filter CalculationFilter) ([]domain.CalculationRecord, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	page := []domain.CalculationRecord{}
+	total := 0
+	for i := len(r.records) - 1; i >= 0; i-- {
+		if !filter.Matches(r.records[i]) {
+			continue
+		}
+		if total >= filter.Offset && (filter.Limit == 0 || len(page) < filter.Limit) {
+			page = append(page, r.records[i])
+		}
+		total++
+	}
+	return page, total, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for calculation timestamps
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for calculation records
+)
+
+// CalculationRepositoryTestSuite defines the test suite for the calculation history repository
+type CalculationRepositoryTestSuite struct {
+	suite.Suite                                // Embed the testify suite
+	repo        *InMemoryCalculationRepository // Repository under test
+	start       time.Time                      // Time of the first record
+}
+
+// SetupTest sets up the test environment before each test
+func (s *CalculationRepositoryTestSuite) SetupTest() {
+	s.repo = NewInMemoryCalculationRepository(3)
+	s.start = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, record := range []domain.CalculationRecord{
+		{OrderAmount: 1, PackSizeVersion: 1},
+		{OrderAmount: 263, PackSizeVersion: 1},
+		{OrderAmount: 263, PackSizeVersion: 2},
+		{OrderAmount: 12001, PackSizeVersion: 2},
+	} {
+		record.CalculatedAt = s.start.Add(time.Duration(i) * time.Hour)
+		_, err := s.repo.SaveCalculation(record)
+		s.Require().NoError(err, "Expected no error")
+	}
+}
+
+// TestCalculationRepositoryTestSuite runs the test suite
+func TestCalculationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CalculationRepositoryTestSuite))
+}
+
+// calculationIDs returns the IDs of the given records
+func calculationIDs(records []domain.CalculationRecord) []int {
+	result := make([]int, len(records))
+	for i, record := range records {
+		result[i] = record.ID
+	}
+	return result
+}
+
+// TestCapacity tests that the oldest records are dropped once the capacity is reached
+func (s *CalculationRepositoryTestSuite) TestCapacity() {
+	_, err := s.repo.GetCalculation(1)
+	s.Assert().Equal(ErrCalculationNotFound, err, "Oldest record should be dropped")
+
+	record, err := s.repo.GetCalculation(4)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(12001, record.OrderAmount, "Newest record should be kept")
+}
+
+// TestListCalculations tests filtering and pagination
+func (s *CalculationRepositoryTestSuite) TestListCalculations() {
+	cases := []struct {
+		name     string
+		filter   CalculationFilter
+		expected []int
+		total    int
+	}{
+		{name: "All", filter: CalculationFilter{}, expected: []int{4, 3, 2}, total: 3},
+		{name: "OrderAmount", filter: CalculationFilter{OrderAmount: 263}, expected: []int{3, 2}, total: 2},
+		{name: "Version", filter: CalculationFilter{PackSizeVersion: 2}, expected: []int{4, 3}, total: 2},
+		{name: "TimeRange", filter: CalculationFilter{From: s.start.Add(2 * time.Hour), To: s.start.Add(3 * time.Hour)}, expected: []int{3}, total: 1},
+		{name: "Page", filter: CalculationFilter{Offset: 1, Limit: 1}, expected: []int{3}, total: 3},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			records, total, err := s.repo.ListCalculations(tc.filter)
+			s.Assert().NoError(err, "Expected no error")
+			s.Assert().Equal(tc.expected, calculationIDs(records), "Records should match, newest first")
+			s.Assert().Equal(tc.total, total, "Total should match")
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// FilePackRepository keeps versions and pending events in memory and persists every change to a JSON
+// file, so pack sizes and undelivered events survive restarts. Writes go to a temporary file that is
+// synced and renamed over the old one, so a crash leaves either the old or the new file, never a partial one
+type FilePackRepository struct {
+	mu       sync.RWMutex
+	path     string
+	versions []domain.PackSizeVersion
+	events   []domain.EventEnvelope // Pending events, oldest first
+	lastID   int                    // ID of the newest event ever stored, so IDs are never reused
+	feed     changeFeed
+}
+
+// packFile is the on-disk layout of a FilePackRepository
+type packFile struct {
+	Versions    []domain.PackSizeVersion `json:"versions"`
+	Events      []domain.EventEnvelope   `json:"events,omitempty"`
+	LastEventID int                      `json:"lastEventId,omitempty"`
+}
+
+// NewFilePackRepository loads the versions stored at path; when the file does not exist yet
+// it is created with a first version holding the default sizes
+func NewFilePackRepository(path string, defaultSizes []int) (*FilePackRepository, error) {
+	r := &FilePackRepository{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		if _, err := r.SaveVersion(domain.PackSizeVersion{PackSizes: defaultSizes, Author: InitialVersionAuthor}); err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file packFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	for i, version := range file.Versions {
+		if version.ID != i+1 {
+			return nil, fmt.Errorf("reading %s: version %d is stored as ID %d", path, i+1, version.ID)
+		}
+	}
+	r.versions, r.events, r.lastID = file.Versions, file.Events, file.LastEventID
+	return r, nil
+}
+
+func (r *FilePackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	version, err := domain.ActiveVersion(r.versions, at)
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return copyVersion(version), nil
+}
+
+func (r *FilePackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.save(version, nil)
+}
+
+func (r *FilePackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.versions) != latestID {
+		return domain.PackSizeVersion{}, ErrVersionConflict
+	}
+	return r.save(version, nil)
+}
+
+func (r *FilePackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if latestID != AnyLatestVersion && len(r.versions) != latestID {
+		return domain.PackSizeVersion{}, ErrVersionConflict
+	}
+	return r.save(version, newEvent)
+}
+
+func (r *FilePackRepository) LatestVersionID() (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.versions), nil
+}
+
+// save appends a version and, when newEvent is set, its event, and writes both in one go; the caller
+// holds the write lock
+func (r *FilePackRepository) save(version domain.PackSizeVersion, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	version = copyVersion(version)
+	version.ID = len(r.versions) + 1
+	version.CreatedAt = time.Now()
+
+	events, lastID := r.events, r.lastID
+	if newEvent != nil {
+		event, err := newEvent(copyVersion(version))
+		if err != nil {
+			return domain.PackSizeVersion{}, err
+		}
+		lastID++
+		event.ID = lastID
+		events = append(events[:len(events):len(events)], event)
+	}
+	versions := append(r.versions[:len(r.versions):len(r.versions)], version) // Never write into the current backing array
+	if err := r.write(versions, events, lastID); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	r.feed.publish(version)
+	return copyVersion(version), nil
+}
+
+func (r *FilePackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = r.lastID + 1
+	if err := r.write(r.versions, append(r.events[:len(r.events):len(r.events)], event), event.ID); err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	return event, nil
+}
+
+func (r *FilePackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if limit <= 0 || limit > len(r.events) {
+		limit = len(r.events)
+	}
+	events := make([]domain.EventEnvelope, limit)
+	copy(events, r.events[:limit])
+	return events, nil
+}
+
+func (r *FilePackRepository) MarkEventPublished(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, event := range r.events {
+		if event.ID == id {
+			events := append(append([]domain.EventEnvelope(nil), r.events[:i]...), r.events[i+1:]...)
+			return r.write(r.versions, events, r.lastID)
+		}
+	}
+	return nil
+}
+
+// write stores the versions and events in the file, and in memory once the file is written, so memory
+// is left unchanged when it could not be; the caller holds the write lock
+func (r *FilePackRepository) write(versions []domain.PackSizeVersion, events []domain.EventEnvelope, lastID int) error {
+	if err := writeFileAtomic(r.path, packFile{Versions: versions, Events: events, LastEventID: lastID}); err != nil {
+		return err
+	}
+	r.versions, r.events, r.lastID = versions, events, lastID
+	return nil
+}
+
+func (r *FilePackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if id < 1 || id > len(r.versions) {
+		return domain.PackSizeVersion{}, ErrVersionNotFound
+	}
+	return copyVersion(r.versions[id-1]), nil
+}
+
+func (r *FilePackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := make([]domain.PackSizeVersion, len(r.versions))
+	for i, version := range r.versions {
+		versions[i] = copyVersion(version)
+	}
+	return versions, nil
+}
+
+func (r *FilePackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	if err := CheckVersions(versions); err != nil {
+		return err
+	}
+	replaced := make([]domain.PackSizeVersion, len(versions))
+	for i, version := range versions {
+		replaced[i] = copyVersion(version)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.write(replaced, r.events, r.lastID); err != nil { // Pending events are still delivered
+		return err
+	}
+	r.feed.publish(replaced[len(replaced)-1])
+	return nil
+}
+
+func (r *FilePackRepository) Watch(ctx context.Context) <-chan PackSizesChange {
+	return r.feed.watch(ctx)
+}
+
+// writeFileAtomic writes value as JSON to a temporary file next to path, syncs it and renames
+// it over path, then syncs the directory so the rename itself is durable
+func writeFileAtomic(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once the rename succeeded
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"os"            // Import os for inspecting and corrupting the file
+	"path/filepath" // Import filepath for building file paths
+	"testing"       // Import the testing package for writing unit tests
+	"time"          // Import time for active-version lookups
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// FilePackRepositoryTestSuite defines the test suite for the file-backed pack repository
+type FilePackRepositoryTestSuite struct {
+	suite.Suite        // Embed the testify suite
+	dir         string // Temporary directory holding the file
+	path        string // Path of the repository file
+}
+
+// SetupTest sets up the test environment before each test
+func (s *FilePackRepositoryTestSuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.path = filepath.Join(s.dir, "default", "pack_sizes.json")
+}
+
+// TestFilePackRepositoryTestSuite runs the test suite
+func TestFilePackRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(FilePackRepositoryTestSuite))
+}
+
+// TestPersistsAcrossRestarts tests that versions saved by one instance are loaded by the next
+func (s *FilePackRepositoryTestSuite) TestPersistsAcrossRestarts() {
+	repo, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	_, err = repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100, 200}, Author: "alice"})
+	s.Require().NoError(err, "Expected no error")
+
+	reopened, err := NewFilePackRepository(s.path, []int{1}) // Defaults only apply to a new file
+	s.Require().NoError(err, "Expected no error")
+	versions, err := reopened.ListVersions()
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(versions, 2, "Both versions should be loaded")
+	s.Assert().Equal([]int{250, 500}, versions[0].PackSizes, "Seeded version should be stored")
+	s.Assert().Equal(InitialVersionAuthor, versions[0].Author, "Seeded version should be attributed to the config")
+	s.Assert().Equal("alice", versions[1].Author, "Saved version should be stored")
+
+	active, err := reopened.GetActiveVersion(time.Now())
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, active.PackSizes, "Latest version should be active after a restart")
+
+	next, err := reopened.SaveVersion(domain.PackSizeVersion{PackSizes: []int{300}})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(3, next.ID, "IDs should continue after a restart")
+}
+
+// TestAtomicWrites tests that no temporary files are left behind and failed writes change nothing
+func (s *FilePackRepositoryTestSuite) TestAtomicWrites() {
+	repo, err := NewFilePackRepository(s.path, []int{250})
+	s.Require().NoError(err, "Expected no error")
+	_, err = repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{500}})
+	s.Require().NoError(err, "Expected no error")
+
+	entries, err := os.ReadDir(filepath.Dir(s.path))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Len(entries, 1, "Only the repository file should exist")
+
+	s.Require().NoError(os.RemoveAll(filepath.Dir(s.path)), "Expected no error") // Make the next write fail
+	_, err = repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{1000}})
+	s.Assert().Error(err, "Expected the write to fail")
+
+	versions, err := repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "A failed write should not change the versions")
+}
+
+// TestInvalidFile tests that a corrupt file is reported instead of being overwritten
+func (s *FilePackRepositoryTestSuite) TestInvalidFile() {
+	s.Require().NoError(os.MkdirAll(filepath.Dir(s.path), 0o755), "Expected no error")
+	s.Require().NoError(os.WriteFile(s.path, []byte(`{"versions":[`), 0o644), "Expected no error")
+
+	_, err := NewFilePackRepository(s.path, []int{250})
+	s.Assert().Error(err, "Expected a corrupt file to be rejected")
+
+	data, err := os.ReadFile(s.path)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(`{"versions":[`, string(data), "Corrupt file should be left for inspection")
+}
+
+// TestSaveVersionIfLatest tests saving only on top of the newest version
+func (s *FilePackRepositoryTestSuite) TestSaveVersionIfLatest() {
+	repo, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	testSaveVersionIfLatest(&s.Suite, repo)
+
+	// The conflicting saves must not have reached the file either
+	reopened, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	versions, err := reopened.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 3, "Only the winning writers' versions should be written")
+}
+
+// TestOutbox tests that versions and their events are written together and pending events survive a restart
+func (s *FilePackRepositoryTestSuite) TestOutbox() {
+	repo, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	testOutbox(&s.Suite, repo)
+
+	_, err = repo.SaveVersionWithEvent(domain.PackSizeVersion{PackSizes: []int{400}}, AnyLatestVersion, func(saved domain.PackSizeVersion) (domain.EventEnvelope, error) {
+		return domain.NewEventEnvelope(domain.PackSizesUpdated{Version: saved}, time.Now())
+	})
+	s.Require().NoError(err, "Expected no error")
+	reopened, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	pending, err := reopened.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(pending, 1, "Undelivered event should survive a restart")
+	appended, err := reopened.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Greater(appended.ID, pending[0].ID, "IDs should not be reused after a restart")
+}
+
+// TestWatch tests that saved versions are reported to watchers
+func (s *FilePackRepositoryTestSuite) TestWatch() {
+	repo, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	testWatch(&s.Suite, repo)
+}
+
+// TestReplaceVersions tests that replaced versions are written to the file
+func (s *FilePackRepositoryTestSuite) TestReplaceVersions() {
+	repo, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	testReplaceVersions(&s.Suite, repo)
+
+	reopened, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+	latest, _ := reopened.LatestVersionID()
+	s.Assert().Equal(4, latest, "Replaced versions should survive a restart")
+}
+
+// TestDefensiveCopies tests that callers cannot change stored pack sizes through returned slices
+func (s *FilePackRepositoryTestSuite) TestDefensiveCopies() {
+	repo, err := NewFilePackRepository(s.path, []int{250, 500})
+	s.Require().NoError(err, "Expected no error")
+
+	version, err := repo.GetVersion(1)
+	s.Require().NoError(err, "Expected no error")
+	version.PackSizes[0] = 999
+
+	version, err = repo.GetVersion(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500}, version.PackSizes, "Stored sizes should be unaffected")
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// StoredResponse is a response recorded for an idempotency key
+type StoredResponse struct {
+	StatusCode int                 // HTTP status code of the original response
+	Header     map[string][]string // Headers of the original response, such as Content-Type and ETag
+	Body       []byte              // Body of the original response
+}
+
+// IdempotencyRepository remembers the responses of requests sent with an idempotency key
+type IdempotencyRepository interface {
+	// Begin claims key for a request with the given fingerprint; it returns the stored response
+	// if the key has already completed, or nil if the caller should execute the request
+	Begin(key string, fingerprint string) (*StoredResponse, error)
+	// Complete stores the response for a claimed key until ttl has passed
+	Complete(key string, response StoredResponse, ttl time.Duration) error
+	// Release gives up a claimed key without storing a response, so the request can be retried
+	Release(key string) error
+}
+
+var (
+	// ErrIdempotencyKeyInFlight is returned when a request with the same key is still running
+	ErrIdempotencyKeyInFlight = errors.New("a request with this idempotency key is still in progress")
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+)
+
+// idempotencyEntry is the state of one idempotency key
+type idempotencyEntry struct {
+	fingerprint string          // Fingerprint of the request that claimed the key
+	response    *StoredResponse // Stored response, nil while the request is in flight
+	expiresAt   time.Time       // When the stored response is forgotten
+}
+
+// InMemoryIdempotencyRepository keeps idempotency keys in memory
+type InMemoryIdempotencyRepository struct {
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	now     func() time.Time
+}
+
+func NewInMemoryIdempotencyRepository() *InMemoryIdempotencyRepository {
+	return &InMemoryIdempotencyRepository{entries: make(map[string]*idempotencyEntry), now: time.Now}
+}
+
+func (r *InMemoryIdempotencyRepository) Begin(key string, fingerprint string) (*StoredResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.purgeExpired()
+
+	entry, ok := r.entries[key]
+	if !ok {
+		r.entries[key] = &idempotencyEntry{fingerprint: fingerprint}
+		return nil, nil
+	}
+	if entry.fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if entry.response == nil {
+		return nil, ErrIdempotencyKeyInFlight
+	}
+	response := *entry.response
+	return &response, nil
+}
+
+func (r *InMemoryIdempotencyRepository) Complete(key string, response StoredResponse, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[key]
+	if !ok {
+		entry = &idempotencyEntry{}
+		r.entries[key] = entry
+	}
+	entry.response = &response
+	entry.expiresAt = r.now().Add(ttl)
+	return nil
+}
+
+func (r *InMemoryIdempotencyRepository) Release(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, key)
+	return nil
+}
+
+// purgeExpired drops completed entries whose TTL has passed; in-flight entries are kept
+func (r *InMemoryIdempotencyRepository) purgeExpired() {
+	now := r.now()
+	for key, entry := range r.entries {
+		if entry.response != nil && !now.Before(entry.expiresAt) {
+			delete(r.entries, key)
+		}
+	}
+}
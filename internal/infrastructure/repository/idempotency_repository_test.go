@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for TTLs
+
+	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+)
+
+// IdempotencyRepositoryTestSuite defines the test suite for the idempotency store
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite                                // Embed the testify suite
+	repo        *InMemoryIdempotencyRepository // Repository under test
+	now         time.Time                      // Controllable clock
+}
+
+// SetupTest sets up the test environment before each test
+func (s *IdempotencyRepositoryTestSuite) SetupTest() {
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.repo = NewInMemoryIdempotencyRepository()
+	s.repo.now = func() time.Time { return s.now }
+}
+
+// TestIdempotencyRepositoryTestSuite runs the test suite
+func TestIdempotencyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositoryTestSuite))
+}
+
+// TestLifecycle tests claiming, completing and replaying a key
+func (s *IdempotencyRepositoryTestSuite) TestLifecycle() {
+	stored, err := s.repo.Begin("key", "fp")
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Nil(stored, "First request should execute")
+
+	// A concurrent retry is refused while the first request runs
+	_, err = s.repo.Begin("key", "fp")
+	s.Assert().Equal(ErrIdempotencyKeyInFlight, err, "Expected key in flight")
+
+	s.Require().NoError(s.repo.Complete("key", StoredResponse{StatusCode: 200, Body: []byte("ok")}, time.Hour))
+
+	// A retry after completion gets the stored response
+	stored, err = s.repo.Begin("key", "fp")
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().NotNil(stored, "Expected a stored response")
+	s.Assert().Equal([]byte("ok"), stored.Body, "Stored body should match")
+
+	// The same key with another payload is refused
+	_, err = s.repo.Begin("key", "other")
+	s.Assert().Equal(ErrIdempotencyKeyReused, err, "Expected key reuse to be detected")
+}
+
+// TestExpiryAndRelease tests that keys can be used again after expiry or release
+func (s *IdempotencyRepositoryTestSuite) TestExpiryAndRelease() {
+	_, err := s.repo.Begin("key", "fp")
+	s.Require().NoError(err, "Expected no error")
+	s.Require().NoError(s.repo.Complete("key", StoredResponse{StatusCode: 200}, time.Minute))
+
+	s.now = s.now.Add(time.Minute)
+	stored, err := s.repo.Begin("key", "other")
+	s.Assert().NoError(err, "Expired keys should be forgotten")
+	s.Assert().Nil(stored, "Expired keys should execute again")
+
+	s.Require().NoError(s.repo.Release("key"))
+	stored, err = s.repo.Begin("key", "fp")
+	s.Assert().NoError(err, "Released keys should be claimable")
+	s.Assert().Nil(stored, "Released keys should execute again")
+}
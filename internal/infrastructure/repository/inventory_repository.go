@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// InventoryRepository stores per-size pack stock and the reservations taken from it
+type InventoryRepository interface {
+	// GetStock returns the packs in stock per size, not counting held or confirmed reservations
+	GetStock() (map[int]int, error)
+	// SetStock replaces the stock level of the given sizes; other sizes are left unchanged
+	SetStock(stock map[int]int) error
+	// Reserve takes the reservation's packs from stock and stores the reservation; when any
+	// size is short nothing is taken and ErrInsufficientStock is returned
+	Reserve(reservation domain.Reservation) error
+	// GetReservation returns the reservation with the given ID
+	GetReservation(id string) (domain.Reservation, error)
+	// ListReservations returns the reservations in the given status (all when empty), oldest first
+	ListReservations(status domain.ReservationStatus) ([]domain.Reservation, error)
+	// ResolveReservation moves a held reservation to the given status, returning its packs to stock
+	// unless it is confirmed; ErrReservationNotHeld is returned together with the reservation otherwise.
+	// A reservation that has run out at the given time is expired instead and ErrReservationExpired returned
+	ResolveReservation(id string, status domain.ReservationStatus, by string, at time.Time) (domain.Reservation, error)
+}
+
+var (
+	// ErrReservationNotFound is returned when a reservation does not exist
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationNotHeld is returned when a reservation was already confirmed, cancelled or expired
+	ErrReservationNotHeld = errors.New("reservation is no longer held")
+	// ErrReservationExpired is returned when a reservation is confirmed or cancelled after it expired
+	ErrReservationExpired = errors.New("reservation has expired")
+	// ErrInsufficientStock is returned when there are not enough packs in stock for a reservation
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// InMemoryInventoryRepository keeps stock and reservations in memory; one lock covers both
+// so a reservation either takes all of its packs or none
+type InMemoryInventoryRepository struct {
+	mu           sync.RWMutex
+	stock        map[int]int
+	reservations []domain.Reservation
+}
+
+func NewInMemoryInventoryRepository() *InMemoryInventoryRepository {
+	return &InMemoryInventoryRepository{stock: make(map[int]int)}
+}
+
+func (r *InMemoryInventoryRepository) GetStock() (map[int]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return copyPacks(r.stock), nil
+}
+
+func (r *InMemoryInventoryRepository) SetStock(stock map[int]int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for size, quantity := range stock {
+		r.stock[size] = quantity
+	}
+	return nil
+}
+
+func (r *InMemoryInventoryRepository) Reserve(reservation domain.Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := shortages(r.stock, reservation.Packs); err != nil {
+		return err
+	}
+	for size, quantity := range reservation.Packs {
+		r.stock[size] -= quantity
+	}
+	reservation.Packs = copyPacks(reservation.Packs)
+	r.reservations = append(r.reservations, reservation)
+	return nil
+}
+
+func (r *InMemoryInventoryRepository) GetReservation(id string) (domain.Reservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, reservation := range r.reservations {
+		if reservation.ID == id {
+			reservation.Packs = copyPacks(reservation.Packs)
+			return reservation, nil
+		}
+	}
+	return domain.Reservation{}, ErrReservationNotFound
+}
+
+func (r *InMemoryInventoryRepository) ListReservations(status domain.ReservationStatus) ([]domain.Reservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	reservations := make([]domain.Reservation, 0, len(r.reservations))
+	for _, reservation := range r.reservations {
+		if status == "" || reservation.Status == status {
+			reservation.Packs = copyPacks(reservation.Packs)
+			reservations = append(reservations, reservation)
+		}
+	}
+	return reservations, nil
+}
+
+func (r *InMemoryInventoryRepository) ResolveReservation(id string, status domain.ReservationStatus, by string, at time.Time) (domain.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.reservations {
+		reservation := &r.reservations[i]
+		if reservation.ID != id {
+			continue
+		}
+		if reservation.Status != domain.ReservationHeld {
+			return *reservation, ErrReservationNotHeld
+		}
+		var err error
+		if status != domain.ReservationExpired && reservation.IsExpired(at) {
+			status, by, err = domain.ReservationExpired, "", ErrReservationExpired
+		}
+		if status != domain.ReservationConfirmed {
+			for size, quantity := range reservation.Packs {
+				r.stock[size] += quantity
+			}
+		}
+		reservation.Status = status
+		reservation.ResolvedBy = by
+		reservation.ResolvedAt = &at
+		result := *reservation
+		result.Packs = copyPacks(reservation.Packs)
+		return result, err
+	}
+	return domain.Reservation{}, ErrReservationNotFound
+}
+
+// shortages returns ErrInsufficientStock, naming every short size, when stock cannot cover the packs
+func shortages(stock, packs map[int]int) error {
+	sizes := make([]int, 0, len(packs))
+	for size, quantity := range packs {
+		if quantity > stock[size] {
+			sizes = append(sizes, size)
+		}
+	}
+	if len(sizes) == 0 {
+		return nil
+	}
+	sort.Ints(sizes)
+	details := make([]string, len(sizes))
+	for i, size := range sizes {
+		details[i] = fmt.Sprintf("pack size %d needs %d, %d available", size, packs[size], stock[size])
+	}
+	return fmt.Errorf("%w: %s", ErrInsufficientStock, strings.Join(details, "; "))
+}
+
+// copyPacks returns a copy of a pack size -> quantity map
+func copyPacks(packs map[int]int) map[int]int {
+	result := make(map[int]int, len(packs))
+	for size, quantity := range packs {
+		result[size] = quantity
+	}
+	return result
+}
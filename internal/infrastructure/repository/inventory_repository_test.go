@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"errors"  // Import errors for matching wrapped errors
+	"fmt"     // Import fmt for reservation IDs
+	"sync"    // Import sync for concurrent reservations
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for resolution timestamps
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for reservations
+)
+
+// InventoryRepositoryTestSuite defines the test suite for the inventory repository
+type InventoryRepositoryTestSuite struct {
+	suite.Suite                              // Embed the testify suite
+	repo        *InMemoryInventoryRepository // Repository under test
+	now         time.Time                    // Time reservations are resolved at
+}
+
+// SetupTest sets up the test environment before each test
+func (s *InventoryRepositoryTestSuite) SetupTest() {
+	s.repo = NewInMemoryInventoryRepository()
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.Require().NoError(s.repo.SetStock(map[int]int{250: 2, 500: 1}), "Expected no error")
+}
+
+// TestInventoryRepositoryTestSuite runs the test suite
+func TestInventoryRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(InventoryRepositoryTestSuite))
+}
+
+// held returns a held reservation with the given ID and packs that runs out after the test's resolution time
+func (s *InventoryRepositoryTestSuite) held(id string, packs map[int]int) domain.Reservation {
+	return domain.Reservation{ID: id, Packs: packs, Status: domain.ReservationHeld, ExpiresAt: s.now.Add(15 * time.Minute)}
+}
+
+// TestReserveIsAllOrNothing tests that a reservation short of any size takes nothing
+func (s *InventoryRepositoryTestSuite) TestReserveIsAllOrNothing() {
+	err := s.repo.Reserve(s.held("a", map[int]int{250: 1, 500: 2}))
+	s.Assert().True(errors.Is(err, ErrInsufficientStock), "Expected insufficient stock")
+	s.Assert().EqualError(err, "insufficient stock: pack size 500 needs 2, 1 available", "Shortages should be named")
+
+	stock, err := s.repo.GetStock()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(map[int]int{250: 2, 500: 1}, stock, "Nothing should be taken")
+
+	_, err = s.repo.GetReservation("a")
+	s.Assert().Equal(ErrReservationNotFound, err, "Failed reservations should not be stored")
+}
+
+// TestResolveReservation tests that cancelled packs return to stock and confirmed packs do not
+func (s *InventoryRepositoryTestSuite) TestResolveReservation() {
+	s.Require().NoError(s.repo.Reserve(s.held("a", map[int]int{250: 1})), "Expected no error")
+	s.Require().NoError(s.repo.Reserve(s.held("b", map[int]int{250: 1, 500: 1})), "Expected no error")
+
+	stock, _ := s.repo.GetStock()
+	s.Assert().Equal(map[int]int{250: 0, 500: 0}, stock, "Reserved packs should be taken")
+
+	confirmed, err := s.repo.ResolveReservation("a", domain.ReservationConfirmed, "alice", s.now)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(domain.ReservationConfirmed, confirmed.Status, "Status should change")
+	s.Assert().Equal("alice", confirmed.ResolvedBy, "Resolver should be recorded")
+
+	_, err = s.repo.ResolveReservation("b", domain.ReservationCancelled, "bob", s.now)
+	s.Assert().NoError(err, "Expected no error")
+	stock, _ = s.repo.GetStock()
+	s.Assert().Equal(map[int]int{250: 1, 500: 1}, stock, "Only cancelled packs should return")
+
+	current, err := s.repo.ResolveReservation("b", domain.ReservationExpired, "", s.now)
+	s.Assert().Equal(ErrReservationNotHeld, err, "Resolved reservations cannot be resolved again")
+	s.Assert().Equal(domain.ReservationCancelled, current.Status, "Current state should be returned")
+	stock, _ = s.repo.GetStock()
+	s.Assert().Equal(map[int]int{250: 1, 500: 1}, stock, "Packs should not return twice")
+
+	_, err = s.repo.ResolveReservation("missing", domain.ReservationCancelled, "", s.now)
+	s.Assert().Equal(ErrReservationNotFound, err, "Expected reservation not found")
+
+	list, err := s.repo.ListReservations(domain.ReservationConfirmed)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(list, 1, "Reservations should be filtered by status")
+}
+
+// TestResolveExpiredReservation tests that a reservation that has run out is expired instead of confirmed
+func (s *InventoryRepositoryTestSuite) TestResolveExpiredReservation() {
+	reservation := s.held("a", map[int]int{250: 1})
+	reservation.ExpiresAt = s.now
+	s.Require().NoError(s.repo.Reserve(reservation), "Expected no error")
+
+	expired, err := s.repo.ResolveReservation("a", domain.ReservationConfirmed, "alice", s.now)
+	s.Assert().Equal(ErrReservationExpired, err, "Expected reservation expired")
+	s.Assert().Equal(domain.ReservationExpired, expired.Status, "Reservation should be expired")
+	s.Assert().Empty(expired.ResolvedBy, "Expiry should not be attributed to the caller")
+	stock, _ := s.repo.GetStock()
+	s.Assert().Equal(map[int]int{250: 2, 500: 1}, stock, "Expired packs should return")
+
+	_, err = s.repo.ResolveReservation("a", domain.ReservationConfirmed, "alice", s.now)
+	s.Assert().Equal(ErrReservationNotHeld, err, "Expired reservations cannot be resolved again")
+}
+
+// TestConcurrentReservations tests that concurrent orders never take more packs than are in stock
+func (s *InventoryRepositoryTestSuite) TestConcurrentReservations() {
+	s.Require().NoError(s.repo.SetStock(map[int]int{250: 10}), "Expected no error")
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := s.repo.Reserve(s.held(fmt.Sprint(i), map[int]int{250: 1})); err == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stock, _ := s.repo.GetStock()
+	s.Assert().Equal(10, reserved, "Exactly the packs in stock should be reserved")
+	s.Assert().Equal(0, stock[250], "Stock should never go negative")
+}
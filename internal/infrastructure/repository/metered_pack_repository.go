@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// CallStats summarises the calls made to one repository operation
+type CallStats struct {
+	Calls  int64
+	Errors int64
+	Total  time.Duration
+	Max    time.Duration
+}
+
+// RepositoryMetrics collects the duration and outcome of repository calls by name
+type RepositoryMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*CallStats
+}
+
+func NewRepositoryMetrics() *RepositoryMetrics {
+	return &RepositoryMetrics{stats: map[string]*CallStats{}}
+}
+
+// Observe records one call
+func (m *RepositoryMetrics) Observe(name string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, ok := m.stats[name]
+	if !ok {
+		stats = &CallStats{}
+		m.stats[name] = stats
+	}
+	stats.Calls++
+	if err != nil {
+		stats.Errors++
+	}
+	stats.Total += duration
+	if duration > stats.Max {
+		stats.Max = duration
+	}
+}
+
+// Snapshot returns a copy of the statistics of every call name observed so far
+func (m *RepositoryMetrics) Snapshot() map[string]CallStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]CallStats, len(m.stats))
+	for name, stats := range m.stats {
+		snapshot[name] = *stats
+	}
+	return snapshot
+}
+
+// MeteredPackRepository times every call to another PackRepository and records it as
+// "<prefix>.<method>", e.g. "default.pack_sizes.GetActiveVersion"
+type MeteredPackRepository struct {
+	next    PackRepository
+	metrics *RepositoryMetrics
+	prefix  string
+}
+
+func NewMeteredPackRepository(next PackRepository, metrics *RepositoryMetrics, prefix string) *MeteredPackRepository {
+	return &MeteredPackRepository{next: next, metrics: metrics, prefix: prefix}
+}
+
+func (r *MeteredPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	start := time.Now()
+	version, err := r.next.GetActiveVersion(at)
+	r.observe("GetActiveVersion", start, err)
+	return version, err
+}
+
+func (r *MeteredPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	start := time.Now()
+	saved, err := r.next.SaveVersion(version)
+	r.observe("SaveVersion", start, err)
+	return saved, err
+}
+
+func (r *MeteredPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	start := time.Now()
+	saved, err := r.next.SaveVersionIfLatest(version, latestID)
+	r.observe("SaveVersionIfLatest", start, err)
+	return saved, err
+}
+
+func (r *MeteredPackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	start := time.Now()
+	saved, err := r.next.SaveVersionWithEvent(version, latestID, newEvent)
+	r.observe("SaveVersionWithEvent", start, err)
+	return saved, err
+}
+
+func (r *MeteredPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	start := time.Now()
+	version, err := r.next.GetVersion(id)
+	r.observe("GetVersion", start, err)
+	return version, err
+}
+
+func (r *MeteredPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	start := time.Now()
+	versions, err := r.next.ListVersions()
+	r.observe("ListVersions", start, err)
+	return versions, err
+}
+
+func (r *MeteredPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	start := time.Now()
+	err := r.next.ReplaceVersions(versions)
+	r.observe("ReplaceVersions", start, err)
+	return err
+}
+
+func (r *MeteredPackRepository) LatestVersionID() (int, error) {
+	start := time.Now()
+	latestID, err := r.next.LatestVersionID()
+	r.observe("LatestVersionID", start, err)
+	return latestID, err
+}
+
+func (r *MeteredPackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	start := time.Now()
+	appended, err := r.next.AppendEvent(event)
+	r.observe("AppendEvent", start, err)
+	return appended, err
+}
+
+func (r *MeteredPackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	start := time.Now()
+	events, err := r.next.ListPendingEvents(limit)
+	r.observe("ListPendingEvents", start, err)
+	return events, err
+}
+
+func (r *MeteredPackRepository) MarkEventPublished(id int) error {
+	start := time.Now()
+	err := r.next.MarkEventPublished(id)
+	r.observe("MarkEventPublished", start, err)
+	return err
+}
+
+// observe records a call that started at start; every error counts, including not found and conflicts
+func (r *MeteredPackRepository) observe(method string, start time.Time, err error) {
+	r.metrics.Observe(r.prefix+"."+method, time.Since(start), err)
+}
+
+// Unwrap returns the timed repository
+func (r *MeteredPackRepository) Unwrap() PackRepository {
+	return r.next
+}
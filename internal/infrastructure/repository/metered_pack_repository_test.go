@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for durations
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// MeteredPackRepositoryTestSuite defines the test suite for the metrics decorator
+type MeteredPackRepositoryTestSuite struct {
+	suite.Suite                        // Embed the testify suite
+	metrics     *RepositoryMetrics     // Metrics the calls are recorded in
+	repo        *MeteredPackRepository // Decorator under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *MeteredPackRepositoryTestSuite) SetupTest() {
+	s.metrics = NewRepositoryMetrics()
+	s.repo = NewMeteredPackRepository(NewInMemoryPackRepository([]int{250, 500}), s.metrics, "default.pack_sizes")
+}
+
+// TestMeteredPackRepositoryTestSuite runs the test suite
+func TestMeteredPackRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(MeteredPackRepositoryTestSuite))
+}
+
+// TestRecordsCalls tests that every call is counted under its method, with failed calls as errors
+func (s *MeteredPackRepositoryTestSuite) TestRecordsCalls() {
+	s.repo.GetActiveVersion(time.Now())
+	s.repo.GetActiveVersion(time.Now())
+	s.repo.GetVersion(9) // Not found
+	s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}})
+	s.repo.SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{100}}, 1) // Conflict
+	s.repo.ListVersions()
+	s.repo.LatestVersionID()
+
+	snapshot := s.metrics.Snapshot()
+	s.Assert().Len(snapshot, 6, "Each method should be recorded separately")
+	active := snapshot["default.pack_sizes.GetActiveVersion"]
+	s.Assert().Equal(int64(2), active.Calls, "Both reads should be counted")
+	s.Assert().Zero(active.Errors, "Successful reads are no errors")
+	s.Assert().GreaterOrEqual(active.Total, active.Max, "Total time should include the slowest call")
+	s.Assert().Equal(int64(1), snapshot["default.pack_sizes.GetVersion"].Errors, "Failed lookup should be an error")
+	s.Assert().Equal(int64(1), snapshot["default.pack_sizes.SaveVersionIfLatest"].Errors, "Conflict should be an error")
+	s.Assert().Equal(int64(1), snapshot["default.pack_sizes.SaveVersion"].Calls, "Save should be counted")
+}
+
+// TestObserve tests the statistics kept per name
+func (s *MeteredPackRepositoryTestSuite) TestObserve() {
+	s.metrics.Observe("op", 10*time.Millisecond, nil)
+	s.metrics.Observe("op", 30*time.Millisecond, ErrVersionNotFound)
+
+	snapshot := s.metrics.Snapshot()
+	s.Assert().Equal(CallStats{Calls: 2, Errors: 1, Total: 40 * time.Millisecond, Max: 30 * time.Millisecond}, snapshot["op"], "Statistics should add up")
+
+	// Snapshots are copies
+	s.metrics.Observe("op", time.Millisecond, nil)
+	s.Assert().Equal(int64(2), snapshot["op"].Calls, "Earlier snapshot should not change")
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Migration is one versioned schema change, read from a file named like 0001_create_table.sql
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationStatus reports whether a migration has been applied to a database
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// migrationFile matches migration file names and captures the version and name
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.sql$`)
+
+// LoadMigrations reads the migrations in the root of fsys, ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[2], SQL: string(data)})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Dialect describes what differs between databases in the SQL the migrator issues
+type Dialect struct {
+	// Placeholder returns the n-th (1-based) bind parameter
+	Placeholder func(n int) string
+	// Lock and Unlock, when set, serialise migrators running at the same time, e.g. replicas starting together
+	Lock, Unlock string
+}
+
+// SQLiteDialect is the dialect of SQLite; it needs no lock as it only allows one writer
+var SQLiteDialect = Dialect{Placeholder: func(int) string { return "?" }}
+
+// Migrator applies migrations to a database in version order and records them in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, dialect Dialect, migrations []Migration) *Migrator {
+	return &Migrator{db: db, dialect: dialect, migrations: migrations}
+}
+
+// Status lists every known migration with the time it was applied, if it was
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied(context.Background(), m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i].Migration = migration
+		if at, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Migrate applies the pending migrations, each in its own transaction, and returns the ones it applied.
+// It refuses to run against a database migrated by a newer build
+func (m *Migrator) Migrate() ([]Migration, error) {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx) // One connection, so a session lock covers every statement
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if m.dialect.Lock != "" {
+		if _, err := conn.ExecContext(ctx, m.dialect.Lock); err != nil {
+			return nil, err
+		}
+		defer conn.ExecContext(ctx, m.dialect.Unlock)
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+	for version := range applied {
+		if !known[version] {
+			return nil, fmt.Errorf("database has migration %d, which this build does not know; upgrade first", version)
+		}
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, conn, migration); err != nil {
+			return done, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// apply runs one migration and records it in the same transaction, so it is applied fully or not at all
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // No-op once committed
+
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return err
+	}
+	p := m.dialect.Placeholder
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, applied_at) VALUES (`+p(1)+`, `+p(2)+`, `+p(3)+`)`,
+		migration.Version, migration.Name, time.Now().UnixNano()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// queryer is the part of *sql.DB and *sql.Conn the migrator reads through
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// applied creates schema_migrations if needed and returns the applied versions with their times
+func (m *Migrator) applied(ctx context.Context, q queryer) (map[int]time.Time, error) {
+	if _, err := q.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT    NOT NULL,
+		applied_at BIGINT  NOT NULL
+	)`); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at int64
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = time.Unix(0, at).UTC()
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,14 @@
+-- Pack-size versions of every tenant; versions are append-only and numbered per tenant
+CREATE TABLE pack_size_versions (
+    tenant         TEXT        NOT NULL,
+    id             INTEGER     NOT NULL,
+    pack_sizes     JSONB       NOT NULL, -- JSON array of sizes
+    author         TEXT        NOT NULL DEFAULT '',
+    approved_by    TEXT        NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL,
+    effective_from TIMESTAMPTZ,          -- NULL means always
+    rollback_of    INTEGER     NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant, id)
+);
+
+CREATE INDEX pack_size_versions_effective ON pack_size_versions (tenant, effective_from, id);
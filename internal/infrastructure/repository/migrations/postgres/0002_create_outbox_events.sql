@@ -0,0 +1,10 @@
+-- Domain events of every tenant waiting to be published; an event is stored in the transaction of the change it reports
+CREATE TABLE outbox_events (
+    id          BIGSERIAL   PRIMARY KEY, -- Never reused, even once the newest event is published
+    tenant      TEXT        NOT NULL,
+    type        TEXT        NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    payload     JSONB       NOT NULL     -- JSON-encoded event
+);
+
+CREATE INDEX outbox_events_tenant ON outbox_events (tenant, id);
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"   // Import database/sql for the test database
+	"path/filepath"  // Import filepath for the database path
+	"testing"        // Import the testing package for writing unit tests
+	"testing/fstest" // Import fstest for in-memory migration files
+
+	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+)
+
+// MigratorTestSuite defines the test suite for the schema migration runner
+type MigratorTestSuite struct {
+	suite.Suite         // Embed the testify suite
+	db          *sql.DB // Empty SQLite database
+}
+
+// SetupTest sets up the test environment before each test
+func (s *MigratorTestSuite) SetupTest() {
+	db, err := OpenSQLite(filepath.Join(s.T().TempDir(), "test.db"))
+	s.Require().NoError(err, "Expected no error")
+	s.db = db
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *MigratorTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+// TestMigratorTestSuite runs the test suite
+func TestMigratorTestSuite(t *testing.T) {
+	suite.Run(t, new(MigratorTestSuite))
+}
+
+// TestLoadMigrations tests that migration files are read in version order and duplicates are rejected
+func (s *MigratorTestSuite) TestLoadMigrations() {
+	migrations, err := LoadMigrations(fstest.MapFS{
+		"0002_add_b.sql": {Data: []byte("B")},
+		"0001_add_a.sql": {Data: []byte("A")},
+		"README.md":      {Data: []byte("not a migration")},
+	})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]Migration{{Version: 1, Name: "add_a", SQL: "A"}, {Version: 2, Name: "add_b", SQL: "B"}}, migrations,
+		"Migrations should be ordered by version")
+
+	_, err = LoadMigrations(fstest.MapFS{"0001_a.sql": {}, "1_b.sql": {}})
+	s.Assert().Error(err, "Expected duplicate versions to be rejected")
+
+	migrations, err = SQLiteMigrations()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().NotEmpty(migrations, "Embedded SQLite migrations should load")
+}
+
+// TestMigrate tests applying pending migrations once, in order, and reporting their status
+func (s *MigratorTestSuite) TestMigrate() {
+	first := []Migration{{Version: 1, Name: "create_a", SQL: "CREATE TABLE a (id INTEGER)"}}
+	applied, err := NewMigrator(s.db, SQLiteDialect, first).Migrate()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Len(applied, 1, "First migration should be applied")
+
+	both := append(first, Migration{Version: 2, Name: "create_b", SQL: "CREATE TABLE b (id INTEGER)"})
+	migrator := NewMigrator(s.db, SQLiteDialect, both)
+	statuses, err := migrator.Status()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().NotNil(statuses[0].AppliedAt, "First migration should be applied")
+	s.Assert().Nil(statuses[1].AppliedAt, "Second migration should be pending")
+
+	applied, err = migrator.Migrate()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]Migration{both[1]}, applied, "Only the pending migration should be applied")
+
+	applied, err = migrator.Migrate()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(applied, "Migrating again should do nothing")
+
+	_, err = NewMigrator(s.db, SQLiteDialect, first).Migrate()
+	s.Assert().Error(err, "Expected an older build to refuse a newer schema")
+}
+
+// TestFailedMigration tests that a failing migration is rolled back and not recorded
+func (s *MigratorTestSuite) TestFailedMigration() {
+	migrations := []Migration{
+		{Version: 1, Name: "create_a", SQL: "CREATE TABLE a (id INTEGER)"},
+		{Version: 2, Name: "broken", SQL: "CREATE TABLE b (id INTEGER); INSERT INTO missing VALUES (1)"},
+	}
+	applied, err := NewMigrator(s.db, SQLiteDialect, migrations).Migrate()
+	s.Assert().Error(err, "Expected the broken migration to fail")
+	s.Assert().Len(applied, 1, "Migrations before the failure should stay applied")
+
+	var tables int
+	s.Require().NoError(s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'b'`).Scan(&tables), "Expected no error")
+	s.Assert().Equal(0, tables, "The failed migration should be rolled back")
+
+	statuses, err := NewMigrator(s.db, SQLiteDialect, migrations).Status()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Nil(statuses[1].AppliedAt, "The failed migration should stay pending")
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/infrastructure/repository/audit_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	repository "order-packs-calculator/internal/infrastructure/repository"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendAudit mocks base method.
+func (m *MockAuditRepository) AppendAudit(entry domain.AuditEntry) (domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAudit", entry)
+	ret0, _ := ret[0].(domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendAudit indicates an expected call of AppendAudit.
+func (mr *MockAuditRepositoryMockRecorder) AppendAudit(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAudit", reflect.TypeOf((*MockAuditRepository)(nil).AppendAudit), entry)
+}
+
+// ListAudit mocks base method.
+func (m *MockAuditRepository) ListAudit(filter repository.AuditFilter) ([]domain.AuditEntry, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudit", filter)
+	ret0, _ := ret[0].([]domain.AuditEntry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAudit indicates an expected call of ListAudit.
+func (mr *MockAuditRepositoryMockRecorder) ListAudit(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudit", reflect.TypeOf((*MockAuditRepository)(nil).ListAudit), filter)
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/infrastructure/repository/calculation_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	repository "order-packs-calculator/internal/infrastructure/repository"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCalculationRepository is a mock of CalculationRepository interface.
+type MockCalculationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalculationRepositoryMockRecorder
+}
+
+// MockCalculationRepositoryMockRecorder is the mock recorder for MockCalculationRepository.
+type MockCalculationRepositoryMockRecorder struct {
+	mock *MockCalculationRepository
+}
+
+// NewMockCalculationRepository creates a new mock instance.
+func NewMockCalculationRepository(ctrl *gomock.Controller) *MockCalculationRepository {
+	mock := &MockCalculationRepository{ctrl: ctrl}
+	mock.recorder = &MockCalculationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalculationRepository) EXPECT() *MockCalculationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetCalculation mocks base method.
+func (m *MockCalculationRepository) GetCalculation(id int) (domain.CalculationRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalculation", id)
+	ret0, _ := ret[0].(domain.CalculationRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalculation indicates an expected call of GetCalculation.
+func (mr *MockCalculationRepositoryMockRecorder) GetCalculation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalculation", reflect.TypeOf((*MockCalculationRepository)(nil).GetCalculation), id)
+}
+
+// ListCalculations mocks base method.
+func (m *MockCalculationRepository) ListCalculations(filter repository.CalculationFilter) ([]domain.CalculationRecord, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCalculations", filter)
+	ret0, _ := ret[0].([]domain.CalculationRecord)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCalculations indicates an expected call of ListCalculations.
+func (mr *MockCalculationRepositoryMockRecorder) ListCalculations(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCalculations", reflect.TypeOf((*MockCalculationRepository)(nil).ListCalculations), filter)
+}
+
+// SaveCalculation mocks base method.
+func (m *MockCalculationRepository) SaveCalculation(record domain.CalculationRecord) (domain.CalculationRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCalculation", record)
+	ret0, _ := ret[0].(domain.CalculationRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveCalculation indicates an expected call of SaveCalculation.
+func (mr *MockCalculationRepositoryMockRecorder) SaveCalculation(record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCalculation", reflect.TypeOf((*MockCalculationRepository)(nil).SaveCalculation), record)
+}
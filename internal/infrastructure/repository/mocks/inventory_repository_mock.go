@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/infrastructure/repository/inventory_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockInventoryRepository is a mock of InventoryRepository interface.
+type MockInventoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInventoryRepositoryMockRecorder
+}
+
+// MockInventoryRepositoryMockRecorder is the mock recorder for MockInventoryRepository.
+type MockInventoryRepositoryMockRecorder struct {
+	mock *MockInventoryRepository
+}
+
+// NewMockInventoryRepository creates a new mock instance.
+func NewMockInventoryRepository(ctrl *gomock.Controller) *MockInventoryRepository {
+	mock := &MockInventoryRepository{ctrl: ctrl}
+	mock.recorder = &MockInventoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInventoryRepository) EXPECT() *MockInventoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetReservation mocks base method.
+func (m *MockInventoryRepository) GetReservation(id string) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservation", id)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservation indicates an expected call of GetReservation.
+func (mr *MockInventoryRepositoryMockRecorder) GetReservation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservation", reflect.TypeOf((*MockInventoryRepository)(nil).GetReservation), id)
+}
+
+// GetStock mocks base method.
+func (m *MockInventoryRepository) GetStock() (map[int]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock")
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockInventoryRepositoryMockRecorder) GetStock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockInventoryRepository)(nil).GetStock))
+}
+
+// ListReservations mocks base method.
+func (m *MockInventoryRepository) ListReservations(status domain.ReservationStatus) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReservations", status)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReservations indicates an expected call of ListReservations.
+func (mr *MockInventoryRepositoryMockRecorder) ListReservations(status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReservations", reflect.TypeOf((*MockInventoryRepository)(nil).ListReservations), status)
+}
+
+// Reserve mocks base method.
+func (m *MockInventoryRepository) Reserve(reservation domain.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockInventoryRepositoryMockRecorder) Reserve(reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockInventoryRepository)(nil).Reserve), reservation)
+}
+
+// ResolveReservation mocks base method.
+func (m *MockInventoryRepository) ResolveReservation(id string, status domain.ReservationStatus, by string, at time.Time) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReservation", id, status, by, at)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReservation indicates an expected call of ResolveReservation.
+func (mr *MockInventoryRepositoryMockRecorder) ResolveReservation(id, status, by, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReservation", reflect.TypeOf((*MockInventoryRepository)(nil).ResolveReservation), id, status, by, at)
+}
+
+// SetStock mocks base method.
+func (m *MockInventoryRepository) SetStock(stock map[int]int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStock", stock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockInventoryRepositoryMockRecorder) SetStock(stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockInventoryRepository)(nil).SetStock), stock)
+}
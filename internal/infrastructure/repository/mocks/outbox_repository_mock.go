@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/infrastructure/repository/outbox_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendEvent mocks base method.
+func (m *MockOutboxRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendEvent", event)
+	ret0, _ := ret[0].(domain.EventEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendEvent indicates an expected call of AppendEvent.
+func (mr *MockOutboxRepositoryMockRecorder) AppendEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvent", reflect.TypeOf((*MockOutboxRepository)(nil).AppendEvent), event)
+}
+
+// ListPendingEvents mocks base method.
+func (m *MockOutboxRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingEvents", limit)
+	ret0, _ := ret[0].([]domain.EventEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingEvents indicates an expected call of ListPendingEvents.
+func (mr *MockOutboxRepositoryMockRecorder) ListPendingEvents(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingEvents", reflect.TypeOf((*MockOutboxRepository)(nil).ListPendingEvents), limit)
+}
+
+// MarkEventPublished mocks base method.
+func (m *MockOutboxRepository) MarkEventPublished(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventPublished", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventPublished indicates an expected call of MarkEventPublished.
+func (mr *MockOutboxRepositoryMockRecorder) MarkEventPublished(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventPublished", reflect.TypeOf((*MockOutboxRepository)(nil).MarkEventPublished), id)
+}
@@ -5,7 +5,10 @@
 package mocks
 
 import (
+	domain "order-packs-calculator/internal/domain"
+	repository "order-packs-calculator/internal/infrastructure/repository"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -33,31 +36,165 @@ func (m *MockPackRepository) EXPECT() *MockPackRepositoryMockRecorder {
 	return m.recorder
 }
 
-// GetPackSizes mocks base method.
-func (m *MockPackRepository) GetPackSizes() ([]int, error) {
+// AppendEvent mocks base method.
+func (m *MockPackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPackSizes")
-	ret0, _ := ret[0].([]int)
+	ret := m.ctrl.Call(m, "AppendEvent", event)
+	ret0, _ := ret[0].(domain.EventEnvelope)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPackSizes indicates an expected call of GetPackSizes.
-func (mr *MockPackRepositoryMockRecorder) GetPackSizes() *gomock.Call {
+// AppendEvent indicates an expected call of AppendEvent.
+func (mr *MockPackRepositoryMockRecorder) AppendEvent(event interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackSizes", reflect.TypeOf((*MockPackRepository)(nil).GetPackSizes))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvent", reflect.TypeOf((*MockPackRepository)(nil).AppendEvent), event)
 }
 
-// UpdatePackSizes mocks base method.
-func (m *MockPackRepository) UpdatePackSizes(newSizes []int) error {
+// GetActiveVersion mocks base method.
+func (m *MockPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePackSizes", newSizes)
+	ret := m.ctrl.Call(m, "GetActiveVersion", at)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveVersion indicates an expected call of GetActiveVersion.
+func (mr *MockPackRepositoryMockRecorder) GetActiveVersion(at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveVersion", reflect.TypeOf((*MockPackRepository)(nil).GetActiveVersion), at)
+}
+
+// GetVersion mocks base method.
+func (m *MockPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", id)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockPackRepositoryMockRecorder) GetVersion(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockPackRepository)(nil).GetVersion), id)
+}
+
+// LatestVersionID mocks base method.
+func (m *MockPackRepository) LatestVersionID() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestVersionID")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestVersionID indicates an expected call of LatestVersionID.
+func (mr *MockPackRepositoryMockRecorder) LatestVersionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestVersionID", reflect.TypeOf((*MockPackRepository)(nil).LatestVersionID))
+}
+
+// ListPendingEvents mocks base method.
+func (m *MockPackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingEvents", limit)
+	ret0, _ := ret[0].([]domain.EventEnvelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingEvents indicates an expected call of ListPendingEvents.
+func (mr *MockPackRepositoryMockRecorder) ListPendingEvents(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingEvents", reflect.TypeOf((*MockPackRepository)(nil).ListPendingEvents), limit)
+}
+
+// ListVersions mocks base method.
+func (m *MockPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions")
+	ret0, _ := ret[0].([]domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockPackRepositoryMockRecorder) ListVersions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockPackRepository)(nil).ListVersions))
+}
+
+// MarkEventPublished mocks base method.
+func (m *MockPackRepository) MarkEventPublished(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventPublished", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventPublished indicates an expected call of MarkEventPublished.
+func (mr *MockPackRepositoryMockRecorder) MarkEventPublished(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventPublished", reflect.TypeOf((*MockPackRepository)(nil).MarkEventPublished), id)
+}
+
+// ReplaceVersions mocks base method.
+func (m *MockPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceVersions", versions)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdatePackSizes indicates an expected call of UpdatePackSizes.
-func (mr *MockPackRepositoryMockRecorder) UpdatePackSizes(newSizes interface{}) *gomock.Call {
+// ReplaceVersions indicates an expected call of ReplaceVersions.
+func (mr *MockPackRepositoryMockRecorder) ReplaceVersions(versions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceVersions", reflect.TypeOf((*MockPackRepository)(nil).ReplaceVersions), versions)
+}
+
+// SaveVersion mocks base method.
+func (m *MockPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVersion", version)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveVersion indicates an expected call of SaveVersion.
+func (mr *MockPackRepositoryMockRecorder) SaveVersion(version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVersion", reflect.TypeOf((*MockPackRepository)(nil).SaveVersion), version)
+}
+
+// SaveVersionIfLatest mocks base method.
+func (m *MockPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVersionIfLatest", version, latestID)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveVersionIfLatest indicates an expected call of SaveVersionIfLatest.
+func (mr *MockPackRepositoryMockRecorder) SaveVersionIfLatest(version, latestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVersionIfLatest", reflect.TypeOf((*MockPackRepository)(nil).SaveVersionIfLatest), version, latestID)
+}
+
+// SaveVersionWithEvent mocks base method.
+func (m *MockPackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent repository.NewEvent) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVersionWithEvent", version, latestID, newEvent)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveVersionWithEvent indicates an expected call of SaveVersionWithEvent.
+func (mr *MockPackRepositoryMockRecorder) SaveVersionWithEvent(version, latestID, newEvent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePackSizes", reflect.TypeOf((*MockPackRepository)(nil).UpdatePackSizes), newSizes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVersionWithEvent", reflect.TypeOf((*MockPackRepository)(nil).SaveVersionWithEvent), version, latestID, newEvent)
 }
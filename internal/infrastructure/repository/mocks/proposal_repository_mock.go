@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/infrastructure/repository/proposal_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProposalRepository is a mock of ProposalRepository interface.
+type MockProposalRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProposalRepositoryMockRecorder
+}
+
+// MockProposalRepositoryMockRecorder is the mock recorder for MockProposalRepository.
+type MockProposalRepositoryMockRecorder struct {
+	mock *MockProposalRepository
+}
+
+// NewMockProposalRepository creates a new mock instance.
+func NewMockProposalRepository(ctrl *gomock.Controller) *MockProposalRepository {
+	mock := &MockProposalRepository{ctrl: ctrl}
+	mock.recorder = &MockProposalRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProposalRepository) EXPECT() *MockProposalRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateProposal mocks base method.
+func (m *MockProposalRepository) CreateProposal(proposal domain.PackSizeProposal) (domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProposal", proposal)
+	ret0, _ := ret[0].(domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProposal indicates an expected call of CreateProposal.
+func (mr *MockProposalRepositoryMockRecorder) CreateProposal(proposal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProposal", reflect.TypeOf((*MockProposalRepository)(nil).CreateProposal), proposal)
+}
+
+// GetProposal mocks base method.
+func (m *MockProposalRepository) GetProposal(id int) (domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProposal", id)
+	ret0, _ := ret[0].(domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProposal indicates an expected call of GetProposal.
+func (mr *MockProposalRepositoryMockRecorder) GetProposal(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProposal", reflect.TypeOf((*MockProposalRepository)(nil).GetProposal), id)
+}
+
+// ListProposals mocks base method.
+func (m *MockProposalRepository) ListProposals() ([]domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProposals")
+	ret0, _ := ret[0].([]domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProposals indicates an expected call of ListProposals.
+func (mr *MockProposalRepositoryMockRecorder) ListProposals() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProposals", reflect.TypeOf((*MockProposalRepository)(nil).ListProposals))
+}
+
+// UpdateProposal mocks base method.
+func (m *MockProposalRepository) UpdateProposal(proposal domain.PackSizeProposal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProposal", proposal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProposal indicates an expected call of UpdateProposal.
+func (mr *MockProposalRepositoryMockRecorder) UpdateProposal(proposal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProposal", reflect.TypeOf((*MockProposalRepository)(nil).UpdateProposal), proposal)
+}
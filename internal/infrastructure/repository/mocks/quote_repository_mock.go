@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/infrastructure/repository/quote_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuoteRepository is a mock of QuoteRepository interface.
+type MockQuoteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteRepositoryMockRecorder
+}
+
+// MockQuoteRepositoryMockRecorder is the mock recorder for MockQuoteRepository.
+type MockQuoteRepositoryMockRecorder struct {
+	mock *MockQuoteRepository
+}
+
+// NewMockQuoteRepository creates a new mock instance.
+func NewMockQuoteRepository(ctrl *gomock.Controller) *MockQuoteRepository {
+	mock := &MockQuoteRepository{ctrl: ctrl}
+	mock.recorder = &MockQuoteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteRepository) EXPECT() *MockQuoteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetQuote mocks base method.
+func (m *MockQuoteRepository) GetQuote(id string) (domain.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuote", id)
+	ret0, _ := ret[0].(domain.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuote indicates an expected call of GetQuote.
+func (mr *MockQuoteRepositoryMockRecorder) GetQuote(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuote", reflect.TypeOf((*MockQuoteRepository)(nil).GetQuote), id)
+}
+
+// ListQuotes mocks base method.
+func (m *MockQuoteRepository) ListQuotes() ([]domain.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuotes")
+	ret0, _ := ret[0].([]domain.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuotes indicates an expected call of ListQuotes.
+func (mr *MockQuoteRepositoryMockRecorder) ListQuotes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuotes", reflect.TypeOf((*MockQuoteRepository)(nil).ListQuotes))
+}
+
+// ReplaceQuotes mocks base method.
+func (m *MockQuoteRepository) ReplaceQuotes(quotes []domain.Quote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceQuotes", quotes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceQuotes indicates an expected call of ReplaceQuotes.
+func (mr *MockQuoteRepositoryMockRecorder) ReplaceQuotes(quotes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceQuotes", reflect.TypeOf((*MockQuoteRepository)(nil).ReplaceQuotes), quotes)
+}
+
+// SaveQuote mocks base method.
+func (m *MockQuoteRepository) SaveQuote(quote domain.Quote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveQuote", quote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveQuote indicates an expected call of SaveQuote.
+func (mr *MockQuoteRepositoryMockRecorder) SaveQuote(quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveQuote", reflect.TypeOf((*MockQuoteRepository)(nil).SaveQuote), quote)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/infrastructure/repository/webhook_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSubscription mocks base method.
+func (m *MockWebhookRepository) CreateSubscription(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", subscription)
+	ret0, _ := ret[0].(domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) CreateSubscription(subscription interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).CreateSubscription), subscription)
+}
+
+// DeleteSubscription mocks base method.
+func (m *MockWebhookRepository) DeleteSubscription(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscription", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscription indicates an expected call of DeleteSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteSubscription(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteSubscription), id)
+}
+
+// GetSubscription mocks base method.
+func (m *MockWebhookRepository) GetSubscription(id int) (domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscription", id)
+	ret0, _ := ret[0].(domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscription indicates an expected call of GetSubscription.
+func (mr *MockWebhookRepositoryMockRecorder) GetSubscription(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscription", reflect.TypeOf((*MockWebhookRepository)(nil).GetSubscription), id)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookRepository) ListDeliveries(subscriptionID int) ([]domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", subscriptionID)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) ListDeliveries(subscriptionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).ListDeliveries), subscriptionID)
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockWebhookRepository) ListSubscriptions() ([]domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions")
+	ret0, _ := ret[0].([]domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockWebhookRepositoryMockRecorder) ListSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockWebhookRepository)(nil).ListSubscriptions))
+}
+
+// SaveDelivery mocks base method.
+func (m *MockWebhookRepository) SaveDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDelivery", delivery)
+	ret0, _ := ret[0].(domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDelivery indicates an expected call of SaveDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) SaveDelivery(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).SaveDelivery), delivery)
+}
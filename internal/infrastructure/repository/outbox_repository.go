@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"sync"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// OutboxRepository stores domain events until they have been published
+type OutboxRepository interface {
+	// AppendEvent stores an event, assigning its ID
+	AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error)
+	// ListPendingEvents returns up to limit unpublished events, oldest first
+	ListPendingEvents(limit int) ([]domain.EventEnvelope, error)
+	// MarkEventPublished removes an event from the pending list
+	MarkEventPublished(id int) error
+}
+
+// InMemoryOutboxRepository keeps pending events in memory
+type InMemoryOutboxRepository struct {
+	mu       sync.Mutex
+	capacity int
+	nextID   int
+	pending  []domain.EventEnvelope
+}
+
+// NewInMemoryOutboxRepository keeps at most capacity pending events, dropping the oldest; zero keeps everything
+func NewInMemoryOutboxRepository(capacity int) *InMemoryOutboxRepository {
+	return &InMemoryOutboxRepository{capacity: capacity, nextID: 1}
+}
+
+func (r *InMemoryOutboxRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	event.ID = r.nextID
+	r.nextID++
+	r.pending = append(r.pending, event)
+	if r.capacity > 0 && len(r.pending) > r.capacity { // An outbox that is never relayed must not grow forever
+		r.pending = append(r.pending[:0], r.pending[len(r.pending)-r.capacity:]...)
+	}
+	return event, nil
+}
+
+func (r *InMemoryOutboxRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit <= 0 || limit > len(r.pending) {
+		limit = len(r.pending)
+	}
+	events := make([]domain.EventEnvelope, limit)
+	copy(events, r.pending[:limit])
+	return events, nil
+}
+
+func (r *InMemoryOutboxRepository) MarkEventPublished(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, event := range r.pending {
+		if event.ID == id {
+			r.pending = append(r.pending[:i], r.pending[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for events
+)
+
+// OutboxRepositoryTestSuite defines the test suite for the outbox repository
+type OutboxRepositoryTestSuite struct {
+	suite.Suite                           // Embed the testify suite
+	repo        *InMemoryOutboxRepository // Repository under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *OutboxRepositoryTestSuite) SetupTest() {
+	s.repo = NewInMemoryOutboxRepository(0)
+}
+
+// TestOutboxRepositoryTestSuite runs the test suite
+func TestOutboxRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxRepositoryTestSuite))
+}
+
+// TestAppendListAndMarkPublished tests the lifecycle of an outbox event
+func (s *OutboxRepositoryTestSuite) TestAppendListAndMarkPublished() {
+	first, err := s.repo.AppendEvent(domain.EventEnvelope{Type: domain.EventPackSizesUpdated})
+	s.Require().NoError(err, "Expected no error")
+	second, err := s.repo.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(1, first.ID, "IDs should start at 1")
+	s.Assert().Equal(2, second.ID, "IDs should be sequential")
+
+	pending, err := s.repo.ListPendingEvents(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]domain.EventEnvelope{first}, pending, "Limit should return the oldest events")
+
+	s.Require().NoError(s.repo.MarkEventPublished(first.ID), "Expected no error")
+	pending, err = s.repo.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]domain.EventEnvelope{second}, pending, "Published events should no longer be pending")
+}
+
+// TestCapacity tests that a full outbox drops its oldest events
+func (s *OutboxRepositoryTestSuite) TestCapacity() {
+	repo := NewInMemoryOutboxRepository(2)
+	for i := 0; i < 3; i++ {
+		_, err := repo.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed})
+		s.Require().NoError(err, "Expected no error")
+	}
+
+	pending, err := repo.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(pending, 2, "Only capacity events should be kept")
+	s.Assert().Equal(2, pending[0].ID, "The oldest event should be dropped")
+	s.Assert().Equal(3, pending[1].ID, "The newest event should be kept")
+}
@@ -1,24 +1,188 @@
 package repository
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// PackRepository stores pack sizes as an append-only list of versions
 type PackRepository interface {
-	GetPackSizes() ([]int, error)
-	UpdatePackSizes(newSizes []int) error
+	// GetActiveVersion returns the version in effect at the given time
+	GetActiveVersion(at time.Time) (domain.PackSizeVersion, error)
+	// SaveVersion appends a new version, assigning its ID and creation time
+	SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error)
+	// SaveVersionIfLatest appends a new version like SaveVersion, but only while latestID is still
+	// the ID of the newest version; otherwise it returns ErrVersionConflict
+	SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error)
+	// LatestVersionID returns the ID of the newest version, which changes with every save
+	LatestVersionID() (int, error)
+	// GetVersion returns the version with the given ID
+	GetVersion(id int) (domain.PackSizeVersion, error)
+	// ListVersions returns all versions, oldest first
+	ListVersions() ([]domain.PackSizeVersion, error)
+	// ReplaceVersions replaces all versions with the given ones, keeping their IDs and creation times,
+	// e.g. to restore a snapshot. The IDs must run from 1 in order; see CheckVersions
+	ReplaceVersions(versions []domain.PackSizeVersion) error
+	// SaveVersionWithEvent appends a new version like SaveVersionIfLatest, or like SaveVersion when latestID
+	// is AnyLatestVersion, and stores the event newEvent builds from the saved version in the same write,
+	// so a version is never stored without its event or the other way round
+	SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error)
+
+	// Every repository is also the outbox of its tenant's events, so they are kept as durably as the versions
+	OutboxRepository
+}
+
+// NewEvent builds the event that reports a saved version
+type NewEvent func(saved domain.PackSizeVersion) (domain.EventEnvelope, error)
+
+// ErrVersionNotFound is returned when a pack-size version does not exist
+var ErrVersionNotFound = errors.New("pack size version not found")
+
+// ErrVersionConflict is returned when a version is saved on top of one that is no longer the newest
+var ErrVersionConflict = errors.New("pack sizes were changed since they were read")
+
+// ErrInvalidVersions is returned when versions to replace the stored ones are not numbered 1..n
+var ErrInvalidVersions = errors.New("invalid pack size versions")
+
+// CheckVersions reports whether versions can replace the versions of a repository: there must be at
+// least one, and their IDs must be their positions plus one, as every store assigns them
+func CheckVersions(versions []domain.PackSizeVersion) error {
+	if len(versions) == 0 {
+		return fmt.Errorf("%w: at least one version is required", ErrInvalidVersions)
+	}
+	for i, version := range versions {
+		if version.ID != i+1 {
+			return fmt.Errorf("%w: version %d has ID %d", ErrInvalidVersions, i+1, version.ID)
+		}
+	}
+	return nil
 }
 
-// In-memory implementation for simplicity
+// AnyLatestVersion makes SaveVersionWithEvent save regardless of the newest version
+const AnyLatestVersion = -1
+
+// InitialVersionAuthor is recorded as the author of the version seeded from configuration
+const InitialVersionAuthor = "config"
+
+// InMemoryPackRepository keeps versions in memory. It is safe for concurrent use: versions are
+// copied on the way in and out, so callers can never change what the repository holds
 type InMemoryPackRepository struct {
-	packSizes []int
+	*InMemoryOutboxRepository // Pending events, kept in memory like the versions
+
+	mu       sync.RWMutex
+	versions []domain.PackSizeVersion
+	feed     changeFeed
 }
 
 func NewInMemoryPackRepository(defaultSizes []int) *InMemoryPackRepository {
-	return &InMemoryPackRepository{packSizes: defaultSizes}
+	r := &InMemoryPackRepository{InMemoryOutboxRepository: NewInMemoryOutboxRepository(0)}
+	r.SaveVersion(domain.PackSizeVersion{PackSizes: defaultSizes, Author: InitialVersionAuthor})
+	return r
+}
+
+func (r *InMemoryPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	version, err := domain.ActiveVersion(r.versions, at)
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return copyVersion(version), nil
 }
 
-func (r *InMemoryPackRepository) GetPackSizes() ([]int, error) {
-	return r.packSizes, nil
+func (r *InMemoryPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.save(version, nil)
 }
 
-func (r *InMemoryPackRepository) UpdatePackSizes(newSizes []int) error {
-	r.packSizes = newSizes
+func (r *InMemoryPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.versions) != latestID {
+		return domain.PackSizeVersion{}, ErrVersionConflict
+	}
+	return r.save(version, nil)
+}
+
+func (r *InMemoryPackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if latestID != AnyLatestVersion && len(r.versions) != latestID {
+		return domain.PackSizeVersion{}, ErrVersionConflict
+	}
+	return r.save(version, newEvent)
+}
+
+func (r *InMemoryPackRepository) LatestVersionID() (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.versions), nil
+}
+
+// save appends a version and, when newEvent is set, its event; the caller holds the write lock.
+// The event is built before anything is stored, so a failure leaves both untouched
+func (r *InMemoryPackRepository) save(version domain.PackSizeVersion, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	version = copyVersion(version)
+	version.ID = len(r.versions) + 1
+	version.CreatedAt = time.Now()
+	if newEvent != nil {
+		event, err := newEvent(copyVersion(version))
+		if err != nil {
+			return domain.PackSizeVersion{}, err
+		}
+		r.AppendEvent(event) // Cannot fail, and readers of the versions wait on the lock held here
+	}
+	r.versions = append(r.versions, version)
+	r.feed.publish(version)
+	return copyVersion(version), nil
+}
+
+func (r *InMemoryPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if id < 1 || id > len(r.versions) {
+		return domain.PackSizeVersion{}, ErrVersionNotFound
+	}
+	return copyVersion(r.versions[id-1]), nil
+}
+
+func (r *InMemoryPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := make([]domain.PackSizeVersion, len(r.versions))
+	for i, version := range r.versions {
+		versions[i] = copyVersion(version)
+	}
+	return versions, nil
+}
+
+func (r *InMemoryPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	if err := CheckVersions(versions); err != nil {
+		return err
+	}
+	replaced := make([]domain.PackSizeVersion, len(versions))
+	for i, version := range versions {
+		replaced[i] = copyVersion(version)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions = replaced
+	r.feed.publish(replaced[len(replaced)-1]) // Watchers reload everything, so announcing the newest is enough
 	return nil
 }
+
+func (r *InMemoryPackRepository) Watch(ctx context.Context) <-chan PackSizesChange {
+	return r.feed.watch(ctx)
+}
+
+// copyVersion returns a version that shares no memory with the given one
+func copyVersion(version domain.PackSizeVersion) domain.PackSizeVersion {
+	version.PackSizes = append([]int(nil), version.PackSizes...)
+	return version
+}
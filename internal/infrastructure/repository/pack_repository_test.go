@@ -1,9 +1,16 @@
 package repository
 
 import (
-	"testing" // Import the testing package for writing unit tests
+	"context"       // Import context for ending watches
+	"encoding/json" // Import encoding/json for event payloads
+	"fmt"           // Import fmt for building event payloads
+	"sync"          // Import sync for concurrent readers and writers
+	"testing"       // Import the testing package for writing unit tests
+	"time"          // Import time for effective dates
 
-	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+	"github.com/stretchr/testify/assert"     // Import assert for its sample error
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
 )
 
 // PackRepositoryTestSuite defines the test suite for the repository package
@@ -26,21 +33,348 @@ func TestPackRepositoryTestSuite(t *testing.T) {
 
 // TestGetPackSizes tests retrieving pack sizes
 func (s *PackRepositoryTestSuite) TestGetPackSizes() {
-	// Get the pack sizes
-	sizes, err := s.repo.GetPackSizes()
+	// Get the pack sizes in effect now
+	version, err := s.repo.GetActiveVersion(time.Now())
 	s.Assert().NoError(err, "Expected no error")
-	s.Assert().Equal([]int{250, 500, 1000}, sizes, "Pack sizes should match initial value")
+	s.Assert().Equal([]int{250, 500, 1000}, version.PackSizes, "Pack sizes should match initial value")
 }
 
-// TestUpdatePackSizes tests updating pack sizes
+// TestUpdatePackSizes tests updating pack sizes by saving a new version
 func (s *PackRepositoryTestSuite) TestUpdatePackSizes() {
 	// Update the pack sizes
 	newSizes := []int{100, 200, 300}
-	err := s.repo.UpdatePackSizes(newSizes)
+	version, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: newSizes, Author: "alice", EffectiveFrom: time.Now()})
 	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(2, version.ID, "New version should follow the seeded one")
+	s.Assert().False(version.CreatedAt.IsZero(), "Creation time should be set")
 
 	// Verify the updated pack sizes
-	sizes, err := s.repo.GetPackSizes()
+	active, err := s.repo.GetActiveVersion(time.Now())
 	s.Assert().NoError(err, "Expected no error")
-	s.Assert().Equal(newSizes, sizes, "Pack sizes should match updated value")
+	s.Assert().Equal(newSizes, active.PackSizes, "Pack sizes should match updated value")
+}
+
+// TestScheduledVersion tests that a future version only applies from its effective date
+func (s *PackRepositoryTestSuite) TestScheduledVersion() {
+	effectiveFrom := time.Now().Add(time.Hour)
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}, EffectiveFrom: effectiveFrom})
+	s.Require().NoError(err, "Expected no error")
+
+	// Before the effective date the seeded version still applies
+	active, err := s.repo.GetActiveVersion(time.Now())
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(1, active.ID, "Scheduled version should not apply yet")
+
+	// From the effective date the scheduled version applies
+	active, err = s.repo.GetActiveVersion(effectiveFrom)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(2, active.ID, "Scheduled version should apply from its effective date")
+}
+
+// TestVersionHistory tests that earlier versions stay available after an update
+func (s *PackRepositoryTestSuite) TestVersionHistory() {
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100, 200}, Author: "alice"})
+	s.Require().NoError(err, "Expected no error")
+
+	// The seeded version is still retrievable
+	initial, err := s.repo.GetVersion(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500, 1000}, initial.PackSizes, "Initial version should be unchanged")
+	s.Assert().Equal(InitialVersionAuthor, initial.Author, "Initial version should be attributed to the config")
+
+	// Both versions are listed oldest first
+	versions, err := s.repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(versions, 2, "Expected two versions")
+	s.Assert().Equal("alice", versions[1].Author, "Author should be recorded")
+
+	// Unknown versions are reported as not found
+	_, err = s.repo.GetVersion(3)
+	s.Assert().Equal(ErrVersionNotFound, err, "Expected version not found")
+}
+
+// TestDefensiveCopies tests that callers cannot change stored pack sizes through slices they passed in or got back
+func (s *PackRepositoryTestSuite) TestDefensiveCopies() {
+	sizes := []int{100, 200}
+	saved, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: sizes})
+	s.Require().NoError(err, "Expected no error")
+	sizes[0] = 999           // Change the slice that was saved
+	saved.PackSizes[1] = 999 // Change the slice that was returned
+
+	active, err := s.repo.GetActiveVersion(time.Now())
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, active.PackSizes, "Saved sizes should be unaffected")
+	active.PackSizes[0] = 999
+
+	version, err := s.repo.GetVersion(2)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, version.PackSizes, "Active sizes should be a copy")
+
+	versions, err := s.repo.ListVersions()
+	s.Require().NoError(err, "Expected no error")
+	versions[0].PackSizes[0] = 999
+
+	initial, err := s.repo.GetVersion(1)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500, 1000}, initial.PackSizes, "Listed versions should be copies")
+}
+
+// TestConcurrentAccess tests many concurrent readers and writers; run with -race to detect data races
+func (s *PackRepositoryTestSuite) TestConcurrentAccess() {
+	const writers, readers, rounds = 8, 32, 50
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{w + 1, i + 1}})
+				s.Assert().NoError(err, "Expected no error")
+			}
+		}(w)
+	}
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				active, err := s.repo.GetActiveVersion(time.Now())
+				s.Assert().NoError(err, "Expected no error")
+				if len(active.PackSizes) > 0 {
+					active.PackSizes[0] = -1 // Writing to a copy must not race with other readers
+				}
+				versions, err := s.repo.ListVersions()
+				s.Assert().NoError(err, "Expected no error")
+				s.Assert().NotEmpty(versions, "Expected the seeded version at least")
+			}
+		}()
+	}
+	wg.Wait()
+
+	versions, err := s.repo.ListVersions()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 1+writers*rounds, "Every write should be kept")
+	for i, version := range versions {
+		s.Assert().Equal(i+1, version.ID, "IDs should be unique and sequential")
+		s.Assert().NotContains(version.PackSizes, -1, "Readers should not change stored sizes")
+	}
+}
+
+// TestSaveVersionIfLatest tests saving only on top of the newest version
+func (s *PackRepositoryTestSuite) TestSaveVersionIfLatest() {
+	testSaveVersionIfLatest(&s.Suite, s.repo)
+}
+
+// testSaveVersionIfLatest checks the optimistic concurrency of a repository seeded with one version
+func testSaveVersionIfLatest(s *suite.Suite, repo PackRepository) {
+	latest, err := repo.LatestVersionID()
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Equal(1, latest, "Seeded version should be the newest")
+
+	saved, err := repo.SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{100}, Author: "alice"}, 1)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, saved.ID, "New version should follow the one it was based on")
+
+	// A second writer that read the same version must not overwrite the first one's change
+	_, err = repo.SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{200}, Author: "bob"}, 1)
+	s.Assert().ErrorIs(err, ErrVersionConflict, "Expected a conflict")
+
+	latest, err = repo.LatestVersionID()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(2, latest, "Conflicting save should not add a version")
+	active, err := repo.GetActiveVersion(time.Now())
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100}, active.PackSizes, "First writer's sizes should stay in effect")
+
+	// Of writers racing on the same version, exactly one wins
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	saves := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{i + 1}}, 2)
+			if err == nil {
+				mu.Lock()
+				saves++
+				mu.Unlock()
+			} else {
+				s.Assert().ErrorIs(err, ErrVersionConflict, "Losing writers should get a conflict")
+			}
+		}(i)
+	}
+	wg.Wait()
+	s.Assert().Equal(1, saves, "Exactly one racing writer should save")
+}
+
+// TestOutbox tests saving versions together with their events and relaying the events
+func (s *PackRepositoryTestSuite) TestOutbox() {
+	testOutbox(&s.Suite, s.repo)
+}
+
+// testOutbox checks the outbox of a repository seeded with one version and no events
+func testOutbox(s *suite.Suite, repo PackRepository) {
+	occurredAt := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC) // Whole microseconds survive every store
+	newEvent := func(saved domain.PackSizeVersion) (domain.EventEnvelope, error) {
+		payload := fmt.Sprintf(`{"version":{"id":%d}}`, saved.ID)
+		return domain.EventEnvelope{Type: domain.EventPackSizesUpdated, OccurredAt: occurredAt, Payload: json.RawMessage(payload)}, nil
+	}
+
+	saved, err := repo.SaveVersionWithEvent(domain.PackSizeVersion{PackSizes: []int{100}}, AnyLatestVersion, newEvent)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, saved.ID, "New version should follow the seeded one")
+
+	// Neither a conflicting save nor one whose event cannot be built stores anything
+	_, err = repo.SaveVersionWithEvent(domain.PackSizeVersion{PackSizes: []int{200}}, 1, newEvent)
+	s.Assert().ErrorIs(err, ErrVersionConflict, "Expected a conflict")
+	_, err = repo.SaveVersionWithEvent(domain.PackSizeVersion{PackSizes: []int{300}}, 2, func(domain.PackSizeVersion) (domain.EventEnvelope, error) {
+		return domain.EventEnvelope{}, assert.AnError
+	})
+	s.Assert().ErrorIs(err, assert.AnError, "Expected the event error")
+	latest, err := repo.LatestVersionID()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(2, latest, "Failed saves should not add a version")
+
+	appended, err := repo.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed, OccurredAt: occurredAt, Payload: json.RawMessage(`{"orderAmount":1}`)})
+	s.Require().NoError(err, "Expected no error")
+
+	pending, err := repo.ListPendingEvents(0)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(pending, 2, "Only the saved version's event and the appended one should be pending")
+	s.Assert().Equal(domain.EventPackSizesUpdated, pending[0].Type, "Events should be listed oldest first")
+	s.Assert().JSONEq(`{"version":{"id":2}}`, string(pending[0].Payload), "Event should name the saved version")
+	s.Assert().True(occurredAt.Equal(pending[0].OccurredAt), "Event time should be stored")
+	s.Assert().Equal(appended.ID, pending[1].ID, "Appended event should keep its ID")
+	s.Assert().Less(pending[0].ID, pending[1].ID, "IDs should grow")
+
+	first, err := repo.ListPendingEvents(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(first, 1, "Limit should be applied")
+
+	s.Require().NoError(repo.MarkEventPublished(pending[0].ID), "Expected no error")
+	s.Require().NoError(repo.MarkEventPublished(pending[1].ID), "Expected no error")
+	remaining, err := repo.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(remaining, "Published events should no longer be pending")
+
+	next, err := repo.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed, OccurredAt: occurredAt, Payload: json.RawMessage(`{}`)})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Greater(next.ID, appended.ID, "IDs of published events should not be reused")
+	s.Assert().NoError(repo.MarkEventPublished(next.ID), "Expected no error") // Leave the outbox empty
+}
+
+// TestWatch tests that saved versions are reported to watchers
+func (s *PackRepositoryTestSuite) TestWatch() {
+	testWatch(&s.Suite, s.repo)
+}
+
+// TestWatchOverflow tests that a watcher that falls behind keeps the newest changes
+func (s *PackRepositoryTestSuite) TestWatchOverflow() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := s.repo.Watch(ctx)
+
+	for i := 0; i < watchBuffer+5; i++ {
+		s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{i + 1}})
+	}
+	s.Require().Len(changes, watchBuffer, "Unread changes should be capped")
+	first := <-changes
+	s.Assert().Equal(7, first.Version.ID, "Oldest changes should be dropped")
+	for len(changes) > 1 {
+		<-changes
+	}
+	last := <-changes
+	s.Assert().Equal(watchBuffer+6, last.Version.ID, "Newest change should be kept")
+}
+
+// TestWatcherOf tests finding the watcher behind decorators
+func (s *PackRepositoryTestSuite) TestWatcherOf() {
+	decorated := NewCachingPackRepository(NewRetryingPackRepository(NewMeteredPackRepository(s.repo, NewRepositoryMetrics(), "default.pack_sizes"), 3, time.Millisecond), time.Minute)
+	watcher, ok := WatcherOf(decorated)
+	s.Assert().True(ok, "Watcher should be found through the decorators")
+	s.Assert().Same(s.repo, watcher, "Innermost repository should be returned")
+
+	_, ok = WatcherOf(NewCachingPackRepository(newStubPackRepository(), time.Minute))
+	s.Assert().False(ok, "Repositories that cannot be watched should be reported")
+}
+
+// testWatch checks the change reports of a repository seeded with one version
+func testWatch(s *suite.Suite, repo interface {
+	PackRepository
+	PackWatcher
+}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	first := repo.Watch(ctx)
+	otherCtx, cancelOther := context.WithCancel(context.Background())
+	defer cancelOther()
+	second := repo.Watch(otherCtx)
+
+	saved, err := repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}, Author: "alice"})
+	s.Require().NoError(err, "Expected no error")
+	_, err = repo.SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{200}}, 1) // Conflicts, so nothing changes
+	s.Require().ErrorIs(err, ErrVersionConflict, "Expected a conflict")
+
+	for _, changes := range []<-chan PackSizesChange{first, second} {
+		select {
+		case change := <-changes:
+			s.Assert().Equal(saved, change.Version, "Every watcher should receive the saved version")
+		case <-time.After(time.Second):
+			s.Fail("Expected a change")
+		}
+		s.Assert().Empty(changes, "Conflicting save should not be reported")
+	}
+
+	// Ending a watch closes its channel and leaves the others running
+	cancel()
+	for range first {
+	}
+	_, err = repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{300}})
+	s.Require().NoError(err, "Expected no error")
+	select {
+	case change := <-second:
+		s.Assert().Equal([]int{300}, change.Version.PackSizes, "Remaining watcher should keep receiving changes")
+	case <-time.After(time.Second):
+		s.Fail("Expected a change")
+	}
+}
+
+// TestReplaceVersions tests replacing the versions and announcing the change
+func (s *PackRepositoryTestSuite) TestReplaceVersions() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := s.repo.Watch(ctx)
+
+	testReplaceVersions(&s.Suite, s.repo)
+	change := <-changes
+	s.Assert().Equal(3, change.Version.ID, "Newest restored version should be announced")
+}
+
+// testReplaceVersions checks replacing the versions of a repository seeded with one version
+func testReplaceVersions(s *suite.Suite, repo PackRepository) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC) // Whole microseconds survive every store
+	versions := []domain.PackSizeVersion{
+		{ID: 1, PackSizes: []int{10, 20}, Author: InitialVersionAuthor, CreatedAt: created},
+		{ID: 2, PackSizes: []int{30}, Author: "alice", ApprovedBy: "bob", CreatedAt: created.Add(time.Hour), EffectiveFrom: created.Add(2 * time.Hour)},
+		{ID: 3, PackSizes: []int{10, 20}, Author: "carol", CreatedAt: created.Add(3 * time.Hour), EffectiveFrom: created.Add(3 * time.Hour), RollbackOf: 1},
+	}
+	s.Require().NoError(repo.ReplaceVersions(versions), "Expected no error")
+
+	stored, err := repo.ListVersions()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(versions, stored, "Versions should be stored with their IDs and times")
+	active, err := repo.GetActiveVersion(time.Now())
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(3, active.ID, "Newest restored version should be in effect")
+	saved, err := repo.SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{40}}, 3)
+	s.Require().NoError(err, "Saves should continue from the restored versions")
+	s.Assert().Equal(4, saved.ID, "Next ID should follow the restored ones")
+
+	// Versions that are missing or not numbered from 1 are rejected and change nothing
+	s.Assert().ErrorIs(repo.ReplaceVersions(versions[1:]), ErrInvalidVersions, "Gaps should be rejected")
+	s.Assert().ErrorIs(repo.ReplaceVersions(nil), ErrInvalidVersions, "Empty history should be rejected")
+	latest, err := repo.LatestVersionID()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(4, latest, "Rejected replacements should change nothing")
 }
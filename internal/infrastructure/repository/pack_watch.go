@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// PackSizesChange reports a version saved to a PackRepository
+type PackSizesChange struct {
+	Version domain.PackSizeVersion
+}
+
+// PackWatcher is an optional capability of a PackRepository: it reports every version saved to it
+type PackWatcher interface {
+	// Watch returns a channel that receives the versions saved after the call, in order, until ctx is
+	// done, when the channel is closed. A watcher that falls behind loses its oldest unread changes,
+	// never the newest one, so it is always told that something changed
+	Watch(ctx context.Context) <-chan PackSizesChange
+}
+
+// WatcherOf returns the PackWatcher of repo, looking through decorators that expose the repository
+// they wrap with Unwrap; it reports false when no repository in the chain can be watched
+func WatcherOf(repo PackRepository) (PackWatcher, bool) {
+	for repo != nil {
+		if watcher, ok := repo.(PackWatcher); ok {
+			return watcher, true
+		}
+		unwrapper, ok := repo.(interface{ Unwrap() PackRepository })
+		if !ok {
+			return nil, false
+		}
+		repo = unwrapper.Unwrap()
+	}
+	return nil, false
+}
+
+// watchBuffer is the number of unread changes a watcher may fall behind before the oldest are dropped
+const watchBuffer = 16
+
+// changeFeed fans changes out to the watchers of a repository. The zero value is ready to use
+type changeFeed struct {
+	mu       sync.Mutex
+	watchers map[chan PackSizesChange]struct{}
+}
+
+// watch registers a watcher until ctx is done
+func (f *changeFeed) watch(ctx context.Context) <-chan PackSizesChange {
+	ch := make(chan PackSizesChange, watchBuffer)
+	f.mu.Lock()
+	if f.watchers == nil {
+		f.watchers = map[chan PackSizesChange]struct{}{}
+	}
+	f.watchers[ch] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.watchers, ch)
+		close(ch) // Under the lock, so publish never sends on a closed channel
+	}()
+	return ch
+}
+
+// publish sends a change to every watcher without blocking; a full watcher drops its oldest change
+func (f *changeFeed) publish(version domain.PackSizeVersion) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.watchers {
+		change := PackSizesChange{Version: copyVersion(version)} // Each watcher gets its own copy
+		select {
+		case ch <- change:
+		default:
+			select {
+			case <-ch: // Make room; only publish sends, and it holds the lock
+			default:
+			}
+			ch <- change
+		}
+	}
+}
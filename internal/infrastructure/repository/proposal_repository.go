@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// ProposalRepository stores proposed pack-size changes
+type ProposalRepository interface {
+	// CreateProposal stores a new proposal, assigning its ID
+	CreateProposal(proposal domain.PackSizeProposal) (domain.PackSizeProposal, error)
+	// UpdateProposal replaces an existing proposal
+	UpdateProposal(proposal domain.PackSizeProposal) error
+	// GetProposal returns the proposal with the given ID
+	GetProposal(id int) (domain.PackSizeProposal, error)
+	// ListProposals returns all proposals, oldest first
+	ListProposals() ([]domain.PackSizeProposal, error)
+}
+
+// ErrProposalNotFound is returned when a pack-size proposal does not exist
+var ErrProposalNotFound = errors.New("pack size proposal not found")
+
+// InMemoryProposalRepository keeps proposals in memory
+type InMemoryProposalRepository struct {
+	mu        sync.RWMutex
+	proposals []domain.PackSizeProposal
+}
+
+func NewInMemoryProposalRepository() *InMemoryProposalRepository {
+	return &InMemoryProposalRepository{}
+}
+
+func (r *InMemoryProposalRepository) CreateProposal(proposal domain.PackSizeProposal) (domain.PackSizeProposal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	proposal.ID = len(r.proposals) + 1
+	r.proposals = append(r.proposals, proposal)
+	return proposal, nil
+}
+
+func (r *InMemoryProposalRepository) UpdateProposal(proposal domain.PackSizeProposal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if proposal.ID < 1 || proposal.ID > len(r.proposals) {
+		return ErrProposalNotFound
+	}
+	r.proposals[proposal.ID-1] = proposal
+	return nil
+}
+
+func (r *InMemoryProposalRepository) GetProposal(id int) (domain.PackSizeProposal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if id < 1 || id > len(r.proposals) {
+		return domain.PackSizeProposal{}, ErrProposalNotFound
+	}
+	return r.proposals[id-1], nil
+}
+
+func (r *InMemoryProposalRepository) ListProposals() ([]domain.PackSizeProposal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	proposals := make([]domain.PackSizeProposal, len(r.proposals))
+	copy(proposals, r.proposals)
+	return proposals, nil
+}
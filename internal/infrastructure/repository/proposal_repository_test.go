@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for proposals
+)
+
+// ProposalRepositoryTestSuite defines the test suite for the proposal repository
+type ProposalRepositoryTestSuite struct {
+	suite.Suite                             // Embed the testify suite
+	repo        *InMemoryProposalRepository // Repository under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *ProposalRepositoryTestSuite) SetupTest() {
+	s.repo = NewInMemoryProposalRepository()
+}
+
+// TestProposalRepositoryTestSuite runs the test suite
+func TestProposalRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProposalRepositoryTestSuite))
+}
+
+// TestCreateAndUpdateProposal tests storing and changing a proposal
+func (s *ProposalRepositoryTestSuite) TestCreateAndUpdateProposal() {
+	proposal, err := s.repo.CreateProposal(domain.PackSizeProposal{PackSizes: []int{100}, Status: domain.ProposalDraft})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(1, proposal.ID, "First proposal should get ID 1")
+
+	proposal.Status = domain.ProposalInReview
+	s.Require().NoError(s.repo.UpdateProposal(proposal), "Expected no error")
+
+	stored, err := s.repo.GetProposal(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(domain.ProposalInReview, stored.Status, "Update should be stored")
+
+	proposals, err := s.repo.ListProposals()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(proposals, 1, "Expected one proposal")
+}
+
+// TestProposalNotFound tests access to proposals that do not exist
+func (s *ProposalRepositoryTestSuite) TestProposalNotFound() {
+	_, err := s.repo.GetProposal(1)
+	s.Assert().Equal(ErrProposalNotFound, err, "Expected proposal not found")
+
+	err = s.repo.UpdateProposal(domain.PackSizeProposal{ID: 1})
+	s.Assert().Equal(ErrProposalNotFound, err, "Expected proposal not found")
+}
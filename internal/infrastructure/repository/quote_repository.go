@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// QuoteRepository stores customer quotes
+type QuoteRepository interface {
+	// SaveQuote stores a quote under its ID
+	SaveQuote(quote domain.Quote) error
+	// GetQuote returns the quote with the given ID
+	GetQuote(id string) (domain.Quote, error)
+	// ListQuotes returns all quotes, including expired ones, ordered by creation time
+	ListQuotes() ([]domain.Quote, error)
+	// ReplaceQuotes replaces all quotes with the given ones, e.g. to restore a snapshot
+	ReplaceQuotes(quotes []domain.Quote) error
+}
+
+// ErrQuoteNotFound is returned when a quote does not exist
+var ErrQuoteNotFound = errors.New("quote not found")
+
+// InMemoryQuoteRepository keeps quotes in memory
+type InMemoryQuoteRepository struct {
+	mu     sync.RWMutex
+	quotes map[string]domain.Quote
+}
+
+func NewInMemoryQuoteRepository() *InMemoryQuoteRepository {
+	return &InMemoryQuoteRepository{quotes: make(map[string]domain.Quote)}
+}
+
+func (r *InMemoryQuoteRepository) SaveQuote(quote domain.Quote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quotes[quote.ID] = quote
+	return nil
+}
+
+func (r *InMemoryQuoteRepository) GetQuote(id string) (domain.Quote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	quote, ok := r.quotes[id]
+	if !ok {
+		return domain.Quote{}, ErrQuoteNotFound
+	}
+	return quote, nil
+}
+
+func (r *InMemoryQuoteRepository) ListQuotes() ([]domain.Quote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	quotes := make([]domain.Quote, 0, len(r.quotes))
+	for _, quote := range r.quotes {
+		quotes = append(quotes, quote)
+	}
+	sort.Slice(quotes, func(i, j int) bool {
+		if !quotes[i].CreatedAt.Equal(quotes[j].CreatedAt) {
+			return quotes[i].CreatedAt.Before(quotes[j].CreatedAt)
+		}
+		return quotes[i].ID < quotes[j].ID // Quotes created together still list in a stable order
+	})
+	return quotes, nil
+}
+
+func (r *InMemoryQuoteRepository) ReplaceQuotes(quotes []domain.Quote) error {
+	replaced := make(map[string]domain.Quote, len(quotes))
+	for _, quote := range quotes {
+		replaced[quote.ID] = quote
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quotes = replaced
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for creation times
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for quotes
+)
+
+// QuoteRepositoryTestSuite defines the test suite for the quote repository
+type QuoteRepositoryTestSuite struct {
+	suite.Suite                          // Embed the testify suite
+	repo        *InMemoryQuoteRepository // Repository under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *QuoteRepositoryTestSuite) SetupTest() {
+	s.repo = NewInMemoryQuoteRepository()
+}
+
+// TestQuoteRepositoryTestSuite runs the test suite
+func TestQuoteRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(QuoteRepositoryTestSuite))
+}
+
+// TestSaveAndGetQuote tests storing and retrieving a quote
+func (s *QuoteRepositoryTestSuite) TestSaveAndGetQuote() {
+	quote := domain.Quote{ID: "abc", OrderAmount: 263, Packs: map[int]int{500: 1}, TotalItems: 500}
+	s.Require().NoError(s.repo.SaveQuote(quote), "Expected no error")
+
+	stored, err := s.repo.GetQuote("abc")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(quote, stored, "Stored quote should match")
+
+	_, err = s.repo.GetQuote("missing")
+	s.Assert().Equal(ErrQuoteNotFound, err, "Expected quote not found")
+}
+
+// TestListAndReplaceQuotes tests listing quotes by creation time and replacing them all
+func (s *QuoteRepositoryTestSuite) TestListAndReplaceQuotes() {
+	now := time.Now()
+	s.Require().NoError(s.repo.SaveQuote(domain.Quote{ID: "b", CreatedAt: now}), "Expected no error")
+	s.Require().NoError(s.repo.SaveQuote(domain.Quote{ID: "a", CreatedAt: now.Add(time.Minute)}), "Expected no error")
+	s.Require().NoError(s.repo.SaveQuote(domain.Quote{ID: "c", CreatedAt: now}), "Expected no error")
+
+	quotes, err := s.repo.ListQuotes()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]domain.Quote{{ID: "b", CreatedAt: now}, {ID: "c", CreatedAt: now}, {ID: "a", CreatedAt: now.Add(time.Minute)}}, quotes, "Quotes should be listed oldest first")
+
+	s.Require().NoError(s.repo.ReplaceQuotes([]domain.Quote{{ID: "d"}}), "Expected no error")
+	_, err = s.repo.GetQuote("a")
+	s.Assert().Equal(ErrQuoteNotFound, err, "Replaced quotes should be gone")
+	quote, err := s.repo.GetQuote("d")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal("d", quote.ID, "Restored quote should be found")
+}
@@ -0,0 +1,428 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// redisInvalidationChannel carries the tenant whose pack sizes changed, so other replicas drop their cache
+const redisInvalidationChannel = "pack_size_versions:changed"
+
+// redisVersionsKey is the list holding a tenant's versions, oldest first; a version's ID is its position plus one
+func redisVersionsKey(tenant string) string {
+	return "pack_size_versions:" + tenant
+}
+
+// redisEventsKey is the hash holding a tenant's pending events by ID, and redisLastEventKey the counter
+// their IDs are taken from, which never goes back, so IDs are not reused once events are published
+func redisEventsKey(tenant string) string {
+	return "pack_size_events:" + tenant
+}
+
+func redisLastEventKey(tenant string) string {
+	return "pack_size_events:" + tenant + ":last"
+}
+
+// redisRelayKey names the replica whose relay currently delivers a tenant's events; it expires after
+// redisRelayLease unless that replica keeps claiming the events
+func redisRelayKey(tenant string) string {
+	return "pack_size_events:" + tenant + ":relay"
+}
+
+// redisRelayLease is how long a replica keeps delivering a tenant's events after it last claimed them,
+// and so how long the others wait before taking over when it stops
+const redisRelayLease = 30 * time.Second
+
+// redisSeedScript appends the first version only while the tenant's list is empty, so replicas
+// starting together seed a tenant once
+var redisSeedScript = redis.NewScript(`
+if redis.call('LLEN', KEYS[1]) == 0 then
+	return redis.call('RPUSH', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// redisSaveScript appends a version and returns the new length, or -1 when ARGV[2] is set and is no
+// longer the length of the list. A non-empty ARGV[3] is stored as the version's event in the same script
+var redisSaveScript = redis.NewScript(`
+local latest = tonumber(ARGV[2])
+if latest >= 0 and redis.call('LLEN', KEYS[1]) ~= latest then
+	return -1
+end
+local length = redis.call('RPUSH', KEYS[1], ARGV[1])
+if ARGV[3] ~= '' then
+	redis.call('HSET', KEYS[2], redis.call('INCR', KEYS[3]), ARGV[3])
+end
+return length
+`)
+
+// redisAppendEventScript stores an event under the next ID and returns the ID
+var redisAppendEventScript = redis.NewScript(`
+local id = redis.call('INCR', KEYS[2])
+redis.call('HSET', KEYS[1], id, ARGV[1])
+return id
+`)
+
+// redisClaimEventsScript returns every pending event while no other replica holds the relay lease, and
+// takes or renews the lease for ARGV[1]; otherwise it returns nothing
+var redisClaimEventsScript = redis.NewScript(`
+local owner = redis.call('GET', KEYS[2])
+if owner and owner ~= ARGV[1] then
+	return {}
+end
+redis.call('SET', KEYS[2], ARGV[1], 'PX', ARGV[2])
+return redis.call('HGETALL', KEYS[1])
+`)
+
+// redisReplaceScript replaces the whole list with ARGV, so no reader sees it half written
+var redisReplaceScript = redis.NewScript(`
+redis.call('DEL', KEYS[1])
+return redis.call('RPUSH', KEYS[1], unpack(ARGV))
+`)
+
+// OpenRedis connects to the Redis server at url, e.g. redis://:password@localhost:6379/0
+func OpenRedis(url string, pool PoolOptions) (*redis.Client, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	options.PoolSize = pool.MaxOpenConns
+	options.MaxIdleConns = pool.MaxIdleConns
+	options.ConnMaxLifetime = pool.ConnMaxLifetime
+	client := redis.NewClient(options)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// RedisPackStore connects the pack repositories of one replica to a Redis server shared with the
+// other replicas. Each repository caches its tenant's versions; a save publishes the tenant on
+// redisInvalidationChannel and every store listening drops that tenant's cache
+type RedisPackStore struct {
+	client  *redis.Client
+	pubsub  *redis.PubSub
+	done    chan struct{}
+	replica string // Random name of this replica, held in the relay lease
+
+	mu    sync.Mutex
+	repos map[string]*RedisPackRepository
+}
+
+// NewRedisPackStore subscribes to the invalidation channel before returning, so no change published
+// after it returns is missed. Close stops listening; the client stays open
+func NewRedisPackStore(client *redis.Client) (*RedisPackStore, error) {
+	pubsub := client.Subscribe(context.Background(), redisInvalidationChannel)
+	if _, err := pubsub.Receive(context.Background()); err != nil { // Wait for the subscription to be confirmed
+		pubsub.Close()
+		return nil, err
+	}
+	name := make([]byte, 8)
+	if _, err := rand.Read(name); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	s := &RedisPackStore{client: client, pubsub: pubsub, done: make(chan struct{}), replica: hex.EncodeToString(name), repos: map[string]*RedisPackRepository{}}
+	go s.listen()
+	return s, nil
+}
+
+// Repository returns the repository of a tenant, saving a first version with the default sizes when
+// the tenant has none yet
+func (s *RedisPackStore) Repository(tenant string, defaultSizes []int) (*RedisPackRepository, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo, ok := s.repos[tenant]; ok {
+		return repo, nil
+	}
+
+	first, err := json.Marshal(domain.PackSizeVersion{PackSizes: defaultSizes, Author: InitialVersionAuthor, CreatedAt: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	if err := redisSeedScript.Run(context.Background(), s.client, []string{redisVersionsKey(tenant)}, first).Err(); err != nil {
+		return nil, err
+	}
+	repo := &RedisPackRepository{client: s.client, tenant: tenant, replica: s.replica}
+	s.repos[tenant] = repo
+	return repo, nil
+}
+
+// Close stops listening for changes
+func (s *RedisPackStore) Close() error {
+	err := s.pubsub.Close()
+	<-s.done
+	return err
+}
+
+// listen drops the cache of each tenant announced on the channel until the subscription is closed
+func (s *RedisPackStore) listen() {
+	defer close(s.done)
+	for message := range s.pubsub.ChannelWithSubscriptions() {
+		switch m := message.(type) {
+		case *redis.Message:
+			s.invalidate(m.Payload)
+		case *redis.Subscription:
+			// The client resubscribed after losing its connection, so changes may have been missed
+			s.invalidate("")
+		}
+	}
+}
+
+// invalidate drops the cache of a tenant, or of every tenant when tenant is empty
+func (s *RedisPackStore) invalidate(tenant string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, repo := range s.repos {
+		if tenant == "" || id == tenant {
+			repo.invalidate()
+		}
+	}
+}
+
+// RedisPackRepository stores the pack-size versions and pending events of one tenant in Redis. Reads are served from a
+// cache that the store drops whenever any replica saves a version for the tenant
+type RedisPackRepository struct {
+	client  *redis.Client
+	tenant  string
+	replica string
+
+	mu         sync.RWMutex
+	versions   []domain.PackSizeVersion // Cached versions; nil when they must be loaded again
+	generation int                      // Bumped on every invalidation, so a load racing one is not cached
+}
+
+func (r *RedisPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	versions, err := r.load()
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	version, err := domain.ActiveVersion(versions, at)
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return copyVersion(version), nil
+}
+
+func (r *RedisPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	return r.save(version, AnyLatestVersion, nil)
+}
+
+func (r *RedisPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	return r.save(version, latestID, nil)
+}
+
+func (r *RedisPackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	return r.save(version, latestID, newEvent)
+}
+
+// LatestVersionID is read from the cache, so it always matches the versions this replica serves
+func (r *RedisPackRepository) LatestVersionID() (int, error) {
+	versions, err := r.load()
+	return len(versions), err
+}
+
+// save appends a version and announces it, unless latestID is set and no longer the newest ID. When
+// newEvent is set, its event is stored by the same script
+func (r *RedisPackRepository) save(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	version = copyVersion(version)
+	version.ID = 0 // IDs follow from the list position
+	version.CreatedAt = time.Now()
+	data, err := json.Marshal(version)
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+
+	ctx := context.Background()
+	keys := []string{redisVersionsKey(r.tenant), redisEventsKey(r.tenant), redisLastEventKey(r.tenant)}
+	var length int
+	for {
+		// The event names the version, whose ID is only known once the newest one is, so an unconditional
+		// save with an event is made conditional on the length read here and repeated if another replica
+		// saves in between
+		expected := latestID
+		if newEvent != nil && latestID == AnyLatestVersion {
+			n, err := r.client.LLen(ctx, keys[0]).Result()
+			if err != nil {
+				return domain.PackSizeVersion{}, err
+			}
+			expected = int(n)
+		}
+		event := []byte{}
+		if newEvent != nil {
+			saved := copyVersion(version)
+			saved.ID = expected + 1
+			envelope, err := newEvent(saved)
+			if err != nil {
+				return domain.PackSizeVersion{}, err
+			}
+			if event, err = json.Marshal(envelope); err != nil {
+				return domain.PackSizeVersion{}, err
+			}
+		}
+
+		length, err = redisSaveScript.Run(ctx, r.client, keys, data, expected, event).Int()
+		if err != nil {
+			return domain.PackSizeVersion{}, err
+		}
+		if length >= 0 || expected == latestID {
+			break
+		}
+	}
+	if length < 0 {
+		return domain.PackSizeVersion{}, ErrVersionConflict
+	}
+	version.ID = length // Scripts run atomically, so replicas saving together get consecutive IDs
+	r.invalidate()
+	if err := r.client.Publish(ctx, redisInvalidationChannel, r.tenant).Err(); err != nil {
+		return domain.PackSizeVersion{}, fmt.Errorf("version %d saved but other replicas were not notified: %w", version.ID, err)
+	}
+	return version, nil
+}
+
+func (r *RedisPackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	id, err := redisAppendEventScript.Run(context.Background(), r.client, []string{redisEventsKey(r.tenant), redisLastEventKey(r.tenant)}, data).Int()
+	if err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	event.ID = id
+	return event, nil
+}
+
+// ListPendingEvents claims the tenant's events for this replica's relay: while another replica holds
+// the relay lease it returns none, so every event is delivered by one replica, in order, whichever
+// replica saved it. A replica that stops claiming loses the lease after redisRelayLease. Every pending
+// event is read to order them, which is cheap while the relay keeps up
+func (r *RedisPackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	keys := []string{redisEventsKey(r.tenant), redisRelayKey(r.tenant)}
+	reply, err := redisClaimEventsScript.Run(context.Background(), r.client, keys, r.replica, redisRelayLease.Milliseconds()).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string]string, len(reply)/2)
+	for i := 0; i+1 < len(reply); i += 2 { // HGETALL replies alternate fields and values
+		items[reply[i]] = reply[i+1]
+	}
+	events := make([]domain.EventEnvelope, 0, len(items))
+	for field, item := range items {
+		var event domain.EventEnvelope
+		if err := json.Unmarshal([]byte(item), &event); err != nil {
+			return nil, fmt.Errorf("event %s of tenant %q: %w", field, r.tenant, err)
+		}
+		if event.ID, err = strconv.Atoi(field); err != nil {
+			return nil, fmt.Errorf("event %s of tenant %q: %w", field, r.tenant, err)
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	if limit > 0 && limit < len(events) {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+func (r *RedisPackRepository) MarkEventPublished(id int) error {
+	return r.client.HDel(context.Background(), redisEventsKey(r.tenant), strconv.Itoa(id)).Err()
+}
+
+func (r *RedisPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	versions, err := r.load()
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	if id < 1 || id > len(versions) {
+		return domain.PackSizeVersion{}, ErrVersionNotFound
+	}
+	return copyVersion(versions[id-1]), nil
+}
+
+func (r *RedisPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	cached, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]domain.PackSizeVersion, len(cached))
+	for i, version := range cached {
+		versions[i] = copyVersion(version)
+	}
+	return versions, nil
+}
+
+func (r *RedisPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	if err := CheckVersions(versions); err != nil {
+		return err
+	}
+	items := make([]interface{}, len(versions))
+	for i, version := range versions {
+		version.ID = 0 // IDs follow from the list position
+		data, err := json.Marshal(version)
+		if err != nil {
+			return err
+		}
+		items[i] = data
+	}
+
+	ctx := context.Background()
+	if err := redisReplaceScript.Run(ctx, r.client, []string{redisVersionsKey(r.tenant)}, items...).Err(); err != nil {
+		return err
+	}
+	r.invalidate()
+	if err := r.client.Publish(ctx, redisInvalidationChannel, r.tenant).Err(); err != nil {
+		return fmt.Errorf("versions replaced but other replicas were not notified: %w", err)
+	}
+	return nil
+}
+
+// load returns the cached versions, reading them from Redis when the cache was dropped.
+// The returned slice is shared and must not be modified
+func (r *RedisPackRepository) load() ([]domain.PackSizeVersion, error) {
+	r.mu.RLock()
+	versions, generation := r.versions, r.generation
+	r.mu.RUnlock()
+	if versions != nil {
+		return versions, nil
+	}
+
+	items, err := r.client.LRange(context.Background(), redisVersionsKey(r.tenant), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	versions = make([]domain.PackSizeVersion, len(items))
+	for i, item := range items {
+		if err := json.Unmarshal([]byte(item), &versions[i]); err != nil {
+			return nil, fmt.Errorf("pack size version %d of tenant %q: %w", i+1, r.tenant, err)
+		}
+		versions[i].ID = i + 1
+	}
+
+	r.mu.Lock()
+	if r.generation == generation { // Only cache what was read if no change was announced meanwhile
+		r.versions = versions
+	}
+	r.mu.Unlock()
+	return versions, nil
+}
+
+// invalidate drops the cache so the next read goes to Redis
+func (r *RedisPackRepository) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.versions = nil
+	r.generation++
+}
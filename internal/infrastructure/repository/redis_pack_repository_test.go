@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"sync"    // Import sync for concurrent writers
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for effective dates and waiting on invalidations
+
+	"github.com/alicebob/miniredis/v2"       // Import miniredis as a local Redis stand-in
+	"github.com/redis/go-redis/v9"           // Import go-redis for the replica clients
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// RedisPackRepositoryTestSuite defines the test suite for the Redis pack repository
+type RedisPackRepositoryTestSuite struct {
+	suite.Suite                      // Embed the testify suite
+	server      *miniredis.Miniredis // Redis server shared by the replicas
+	clients     []*redis.Client      // Clients opened by the test
+	stores      []*RedisPackStore    // Stores opened by the test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *RedisPackRepositoryTestSuite) SetupTest() {
+	s.server = miniredis.RunT(s.T())
+	s.clients = nil
+	s.stores = nil
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *RedisPackRepositoryTestSuite) TearDownTest() {
+	for _, store := range s.stores {
+		store.Close()
+	}
+	for _, client := range s.clients {
+		client.Close()
+	}
+}
+
+// TestRedisPackRepositoryTestSuite runs the test suite
+func TestRedisPackRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RedisPackRepositoryTestSuite))
+}
+
+// replica connects a new store to the shared server, as another replica would
+func (s *RedisPackRepositoryTestSuite) replica() *RedisPackStore {
+	client, err := OpenRedis("redis://"+s.server.Addr(), PoolOptions{MaxOpenConns: 5, MaxIdleConns: 2, ConnMaxLifetime: time.Minute})
+	s.Require().NoError(err, "Expected no error")
+	s.clients = append(s.clients, client)
+	store, err := NewRedisPackStore(client)
+	s.Require().NoError(err, "Expected no error")
+	s.stores = append(s.stores, store)
+	return store
+}
+
+// repository returns the default tenant's repository of a store
+func (s *RedisPackRepositoryTestSuite) repository(store *RedisPackStore) *RedisPackRepository {
+	repo, err := store.Repository("default", []int{250, 500, 1000})
+	s.Require().NoError(err, "Expected no error")
+	return repo
+}
+
+// activeSizes returns the sizes a repository currently serves
+func (s *RedisPackRepositoryTestSuite) activeSizes(repo *RedisPackRepository) []int {
+	version, err := repo.GetActiveVersion(time.Now())
+	s.Require().NoError(err, "Expected no error")
+	return version.PackSizes
+}
+
+// TestVersions tests saving, looking up and listing versions
+func (s *RedisPackRepositoryTestSuite) TestVersions() {
+	repo := s.repository(s.replica())
+
+	initial, err := repo.GetVersion(1)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500, 1000}, initial.PackSizes, "Seeded version should hold the defaults")
+	s.Assert().Equal(InitialVersionAuthor, initial.Author, "Seeded version should be attributed to the config")
+
+	saved, err := repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100, 200}, Author: "alice", ApprovedBy: "bob", RollbackOf: 1})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, saved.ID, "New version should follow the seeded one")
+
+	stored, err := repo.GetVersion(2)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, stored.PackSizes, "Sizes should be stored")
+	s.Assert().Equal("bob", stored.ApprovedBy, "Approver should be stored")
+	s.Assert().Equal(1, stored.RollbackOf, "Rollback source should be stored")
+	s.Assert().True(saved.CreatedAt.Equal(stored.CreatedAt), "Creation time should be stored")
+
+	versions, err := repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "Versions should be listed oldest first")
+
+	_, err = repo.GetVersion(3)
+	s.Assert().Equal(ErrVersionNotFound, err, "Expected version not found")
+}
+
+// TestActiveVersion tests that scheduled versions only apply from their effective date
+func (s *RedisPackRepositoryTestSuite) TestActiveVersion() {
+	repo := s.repository(s.replica())
+	now := time.Now()
+	_, err := repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}, EffectiveFrom: now.Add(time.Hour)})
+	s.Require().NoError(err, "Expected no error")
+
+	s.Assert().Equal([]int{250, 500, 1000}, s.activeSizes(repo), "Scheduled version should not apply yet")
+	later, err := repo.GetActiveVersion(now.Add(2 * time.Hour))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100}, later.PackSizes, "Scheduled version should apply from its effective date")
+}
+
+// TestReplicasConverge tests that a change saved on one replica is served by the others
+func (s *RedisPackRepositoryTestSuite) TestReplicasConverge() {
+	first := s.repository(s.replica())
+	second := s.repository(s.replica())
+	s.Require().Equal([]int{250, 500, 1000}, s.activeSizes(second), "Both replicas should start from the defaults")
+
+	_, err := first.SaveVersion(domain.PackSizeVersion{PackSizes: []int{23, 31, 53}, Author: "alice"})
+	s.Require().NoError(err, "Expected no error")
+
+	s.Assert().Equal([]int{23, 31, 53}, s.activeSizes(first), "Saving replica should serve the change right away")
+	s.Assert().Eventually(func() bool {
+		version, err := second.GetActiveVersion(time.Now())
+		return err == nil && version.ID == 2
+	}, time.Second, 10*time.Millisecond, "Other replica should serve the change once notified")
+}
+
+// TestCacheInvalidation tests that reads come from the cache until a change is announced
+func (s *RedisPackRepositoryTestSuite) TestCacheInvalidation() {
+	repo := s.repository(s.replica())
+	s.Require().Equal([]int{250, 500, 1000}, s.activeSizes(repo), "Expected the defaults")
+
+	// A write that bypasses the repository is not announced, so the cache keeps serving the old sizes
+	s.server.RPush(redisVersionsKey("default"), `{"packSizes":[7],"author":"ops"}`)
+	s.Assert().Equal([]int{250, 500, 1000}, s.activeSizes(repo), "Cached sizes should be served")
+
+	s.server.Publish(redisInvalidationChannel, "other")
+	time.Sleep(50 * time.Millisecond)
+	s.Assert().Equal([]int{250, 500, 1000}, s.activeSizes(repo), "Another tenant's change should keep the cache")
+
+	s.server.Publish(redisInvalidationChannel, "default")
+	s.Assert().Eventually(func() bool {
+		version, err := repo.GetActiveVersion(time.Now())
+		return err == nil && version.ID == 2
+	}, time.Second, 10*time.Millisecond, "Announced change should be read from Redis")
+}
+
+// TestSeedOnce tests that replicas starting together seed a tenant once and tenants stay separate
+func (s *RedisPackRepositoryTestSuite) TestSeedOnce() {
+	first := s.repository(s.replica())
+	_, err := first.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}})
+	s.Require().NoError(err, "Expected no error")
+
+	second, err := s.replica().Repository("default", []int{1, 2, 3})
+	s.Require().NoError(err, "Expected no error")
+	versions, err := second.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "Existing tenant should not be seeded again")
+
+	other, err := s.replica().Repository("acme", []int{5})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{5}, s.activeSizes(other), "New tenant should be seeded with its own defaults")
+}
+
+// TestReplaceVersions tests that replaced versions reach the other replicas too
+func (s *RedisPackRepositoryTestSuite) TestReplaceVersions() {
+	first, second := s.repository(s.replica()), s.repository(s.replica())
+	s.Require().Equal([]int{250, 500, 1000}, s.activeSizes(second), "Expected the seeded sizes")
+
+	testReplaceVersions(&s.Suite, first)
+	s.Assert().Eventually(func() bool {
+		latest, err := second.LatestVersionID()
+		return err == nil && latest == 4
+	}, time.Second, 10*time.Millisecond, "Other replica should drop its cache")
+}
+
+// TestOutbox tests saving versions together with their events
+func (s *RedisPackRepositoryTestSuite) TestOutbox() {
+	testOutbox(&s.Suite, s.repository(s.replica()))
+}
+
+// TestOutboxRelayLease tests that only one replica at a time is handed the events, and another takes
+// over once it stops claiming them
+func (s *RedisPackRepositoryTestSuite) TestOutboxRelayLease() {
+	first, second := s.repository(s.replica()), s.repository(s.replica())
+	saved, err := second.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed, Payload: []byte(`{}`)})
+	s.Require().NoError(err, "Expected no error")
+
+	pending, err := first.ListPendingEvents(0)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(pending, 1, "The first replica to claim should get the events of every replica")
+	s.Assert().Equal(saved.ID, pending[0].ID, "Event should keep its ID")
+	pending, err = second.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(pending, "Other replicas should get nothing while the lease is held")
+	pending, err = first.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(pending, 1, "The holder should keep getting its undelivered events")
+
+	s.server.FastForward(redisRelayLease + time.Second) // The first replica stops claiming
+	pending, err = second.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(pending, 1, "Another replica should take over once the lease has expired")
+	pending, err = first.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(pending, "The old holder should get nothing once it lost the lease")
+}
+
+// TestSaveVersionIfLatest tests saving only on top of the newest version
+func (s *RedisPackRepositoryTestSuite) TestSaveVersionIfLatest() {
+	testSaveVersionIfLatest(&s.Suite, s.repository(s.replica()))
+}
+
+// TestConcurrentSaves tests that replicas saving at the same time get distinct, consecutive IDs
+func (s *RedisPackRepositoryTestSuite) TestConcurrentSaves() {
+	repos := []*RedisPackRepository{s.repository(s.replica()), s.repository(s.replica())}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repos[i%2].SaveVersion(domain.PackSizeVersion{PackSizes: []int{i + 1}})
+			s.Assert().NoError(err, "Expected no error")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, repo := range repos {
+		versions, err := repo.ListVersions()
+		s.Assert().NoError(err, "Expected no error")
+		s.Require().Len(versions, 21, "Every save should be kept")
+		for i, version := range versions {
+			s.Assert().Equal(i+1, version.ID, "IDs should be consecutive")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// redisWebhooksKey is the hash holding a tenant's subscriptions by ID, and redisLastWebhookKey the
+// counter their IDs are taken from
+func redisWebhooksKey(tenant string) string {
+	return "webhooks:" + tenant
+}
+
+func redisLastWebhookKey(tenant string) string {
+	return "webhooks:" + tenant + ":last"
+}
+
+// redisDeliveriesKey is the list holding the delivery log of a subscription, oldest first, and
+// redisLastDeliveryKey the counter delivery IDs are taken from
+func redisDeliveriesKey(tenant string, subscriptionID int) string {
+	return "webhook_deliveries:" + tenant + ":" + strconv.Itoa(subscriptionID)
+}
+
+func redisLastDeliveryKey(tenant string) string {
+	return "webhook_deliveries:" + tenant + ":last"
+}
+
+// RedisWebhookRepository stores the webhook subscriptions and delivery log of one tenant in Redis, so
+// every replica delivers events to the subscriptions registered on any of them
+type RedisWebhookRepository struct {
+	client *redis.Client
+	tenant string
+}
+
+func NewRedisWebhookRepository(client *redis.Client, tenant string) *RedisWebhookRepository {
+	return &RedisWebhookRepository{client: client, tenant: tenant}
+}
+
+func (r *RedisWebhookRepository) CreateSubscription(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	ctx := context.Background()
+	id, err := r.client.Incr(ctx, redisLastWebhookKey(r.tenant)).Result()
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	subscription.ID = int(id)
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	if err := r.client.HSet(ctx, redisWebhooksKey(r.tenant), subscription.ID, data).Err(); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return subscription, nil
+}
+
+func (r *RedisWebhookRepository) DeleteSubscription(id int) error {
+	deleted, err := r.client.HDel(context.Background(), redisWebhooksKey(r.tenant), strconv.Itoa(id)).Result()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *RedisWebhookRepository) GetSubscription(id int) (domain.WebhookSubscription, error) {
+	data, err := r.client.HGet(context.Background(), redisWebhooksKey(r.tenant), strconv.Itoa(id)).Result()
+	if errors.Is(err, redis.Nil) {
+		return domain.WebhookSubscription{}, ErrWebhookNotFound
+	}
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	var subscription domain.WebhookSubscription
+	if err := json.Unmarshal([]byte(data), &subscription); err != nil {
+		return domain.WebhookSubscription{}, fmt.Errorf("webhook %d of tenant %q: %w", id, r.tenant, err)
+	}
+	return subscription, nil
+}
+
+func (r *RedisWebhookRepository) ListSubscriptions() ([]domain.WebhookSubscription, error) {
+	items, err := r.client.HGetAll(context.Background(), redisWebhooksKey(r.tenant)).Result()
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := make([]domain.WebhookSubscription, 0, len(items))
+	for field, item := range items {
+		var subscription domain.WebhookSubscription
+		if err := json.Unmarshal([]byte(item), &subscription); err != nil {
+			return nil, fmt.Errorf("webhook %s of tenant %q: %w", field, r.tenant, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool { return subscriptions[i].ID < subscriptions[j].ID })
+	return subscriptions, nil
+}
+
+func (r *RedisWebhookRepository) SaveDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	ctx := context.Background()
+	id, err := r.client.Incr(ctx, redisLastDeliveryKey(r.tenant)).Result()
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	delivery.ID = int(id)
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if err := r.client.RPush(ctx, redisDeliveriesKey(r.tenant, delivery.SubscriptionID), data).Err(); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+func (r *RedisWebhookRepository) ListDeliveries(subscriptionID int) ([]domain.WebhookDelivery, error) {
+	items, err := r.client.LRange(context.Background(), redisDeliveriesKey(r.tenant, subscriptionID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	deliveries := make([]domain.WebhookDelivery, 0, len(items))
+	for _, item := range items {
+		var delivery domain.WebhookDelivery
+		if err := json.Unmarshal([]byte(item), &delivery); err != nil {
+			return nil, fmt.Errorf("delivery of webhook %d of tenant %q: %w", subscriptionID, r.tenant, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
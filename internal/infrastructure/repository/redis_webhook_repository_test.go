@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for the pool settings
+
+	"github.com/alicebob/miniredis/v2"       // Import miniredis as a local Redis stand-in
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for subscriptions
+)
+
+// RedisWebhookRepositoryTestSuite defines the test suite for the Redis webhook repository
+type RedisWebhookRepositoryTestSuite struct {
+	suite.Suite                      // Embed the testify suite
+	server      *miniredis.Miniredis // Redis server shared by the replicas
+}
+
+// SetupTest sets up the test environment before each test
+func (s *RedisWebhookRepositoryTestSuite) SetupTest() {
+	s.server = miniredis.RunT(s.T())
+}
+
+// TestRedisWebhookRepositoryTestSuite runs the test suite
+func TestRedisWebhookRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RedisWebhookRepositoryTestSuite))
+}
+
+// replica connects a new repository of the given tenant to the shared server, as another replica would
+func (s *RedisWebhookRepositoryTestSuite) replica(tenant string) *RedisWebhookRepository {
+	client, err := OpenRedis("redis://"+s.server.Addr(), PoolOptions{MaxOpenConns: 2, MaxIdleConns: 1, ConnMaxLifetime: time.Minute})
+	s.Require().NoError(err, "Expected no error")
+	s.T().Cleanup(func() { client.Close() })
+	return NewRedisWebhookRepository(client, tenant)
+}
+
+// TestSubscriptions tests that subscriptions created on one replica are seen and deleted on another
+func (s *RedisWebhookRepositoryTestSuite) TestSubscriptions() {
+	first, second := s.replica("default"), s.replica("default")
+
+	created, err := first.CreateSubscription(domain.WebhookSubscription{URL: "http://example.com/hook", Secret: "s3cret"})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(1, created.ID, "IDs should start at 1")
+
+	stored, err := second.GetSubscription(created.ID)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(created, stored, "Other replicas should see the subscription, secret included")
+	listed, err := second.ListSubscriptions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]domain.WebhookSubscription{created}, listed, "Other replicas should list the subscription")
+	others, err := s.replica("acme").ListSubscriptions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(others, "Other tenants should not see the subscription")
+
+	s.Require().NoError(second.DeleteSubscription(created.ID), "Expected no error")
+	_, err = first.GetSubscription(created.ID)
+	s.Assert().Equal(ErrWebhookNotFound, err, "Deleted subscription should be gone")
+	s.Assert().Equal(ErrWebhookNotFound, first.DeleteSubscription(created.ID), "Deleting twice should fail")
+
+	again, err := first.CreateSubscription(domain.WebhookSubscription{URL: "http://example.com/hook"})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, again.ID, "IDs should not be reused")
+}
+
+// TestDeliveries tests that the delivery log is shared and filtered by subscription
+func (s *RedisWebhookRepositoryTestSuite) TestDeliveries() {
+	first, second := s.replica("default"), s.replica("default")
+	_, err := first.SaveDelivery(domain.WebhookDelivery{SubscriptionID: 1, EventID: 1})
+	s.Require().NoError(err, "Expected no error")
+	_, err = second.SaveDelivery(domain.WebhookDelivery{SubscriptionID: 2, EventID: 1})
+	s.Require().NoError(err, "Expected no error")
+	_, err = second.SaveDelivery(domain.WebhookDelivery{SubscriptionID: 1, EventID: 2})
+	s.Require().NoError(err, "Expected no error")
+
+	deliveries, err := first.ListDeliveries(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(deliveries, 2, "Only deliveries for the subscription should be listed")
+	s.Assert().Equal(1, deliveries[0].EventID, "Deliveries should be listed oldest first")
+	s.Assert().Equal(3, deliveries[1].ID, "Delivery IDs should be unique across replicas")
+
+	none, err := first.ListDeliveries(9)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Empty(none, "A subscription without deliveries should have an empty log")
+}
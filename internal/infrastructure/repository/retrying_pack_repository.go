@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// IsTransient reports whether err may go away when the call is repeated: dropped or refused
+// connections, timeouts, a busy SQLite database, and Postgres connection, serialization and
+// deadlock failures. Errors such as ErrVersionNotFound or ErrVersionConflict never are
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	var sqliteErr *sqlite.Error
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.As(err, &netErr):
+		return true
+	case errors.As(err, &sqliteErr):
+		code := sqliteErr.Code() & 0xff // Extended codes keep the primary code in the low byte
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	case errors.As(err, &pgErr):
+		// Class 08 is connection exceptions; 40001 and 40P01 are serialization failures and deadlocks;
+		// 57P01 to 57P03 are the server shutting down or not accepting connections yet
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "40001" || pgErr.Code == "40P01" ||
+			pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+	return false
+}
+
+// RetryingPackRepository repeats reads from another PackRepository that fail with a transient error,
+// waiting backoff before the first retry and doubling the wait for every further one. Saves are not
+// repeated: a save whose reply was lost may have succeeded, and repeating it would add a second version
+type RetryingPackRepository struct {
+	next     PackRepository
+	attempts int
+	backoff  time.Duration
+	sleep    func(time.Duration)
+}
+
+// NewRetryingPackRepository makes at most attempts calls per read; one attempt disables retries
+func NewRetryingPackRepository(next PackRepository, attempts int, backoff time.Duration) *RetryingPackRepository {
+	return &RetryingPackRepository{next: next, attempts: attempts, backoff: backoff, sleep: time.Sleep}
+}
+
+func (r *RetryingPackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	var version domain.PackSizeVersion
+	err := r.retry(func() (err error) {
+		version, err = r.next.GetActiveVersion(at)
+		return err
+	})
+	return version, err
+}
+
+func (r *RetryingPackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	return r.next.SaveVersion(version)
+}
+
+func (r *RetryingPackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	return r.next.SaveVersionIfLatest(version, latestID)
+}
+
+func (r *RetryingPackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	return r.next.SaveVersionWithEvent(version, latestID, newEvent)
+}
+
+func (r *RetryingPackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	return r.next.ReplaceVersions(versions)
+}
+
+func (r *RetryingPackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	var version domain.PackSizeVersion
+	err := r.retry(func() (err error) {
+		version, err = r.next.GetVersion(id)
+		return err
+	})
+	return version, err
+}
+
+func (r *RetryingPackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	var versions []domain.PackSizeVersion
+	err := r.retry(func() (err error) {
+		versions, err = r.next.ListVersions()
+		return err
+	})
+	return versions, err
+}
+
+func (r *RetryingPackRepository) LatestVersionID() (int, error) {
+	var latestID int
+	err := r.retry(func() (err error) {
+		latestID, err = r.next.LatestVersionID()
+		return err
+	})
+	return latestID, err
+}
+
+func (r *RetryingPackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	return r.next.AppendEvent(event)
+}
+
+func (r *RetryingPackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	var events []domain.EventEnvelope
+	err := r.retry(func() (err error) {
+		events, err = r.next.ListPendingEvents(limit)
+		return err
+	})
+	return events, err
+}
+
+// MarkEventPublished is repeated although it writes: marking an event twice does no harm, while giving
+// up would have the relay deliver the event again
+func (r *RetryingPackRepository) MarkEventPublished(id int) error {
+	return r.retry(func() error {
+		return r.next.MarkEventPublished(id)
+	})
+}
+
+// retry calls fn until it succeeds, fails with an error that is not transient, or runs out of attempts
+func (r *RetryingPackRepository) retry(fn func() error) error {
+	wait := r.backoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.attempts || !IsTransient(err) {
+			return err
+		}
+		r.sleep(wait)
+		wait *= 2
+	}
+}
+
+// Unwrap returns the repository whose reads are retried
+func (r *RetryingPackRepository) Unwrap() PackRepository {
+	return r.next
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql/driver" // Import driver for its transient error
+	"fmt"                 // Import fmt for wrapping errors
+	"net"                 // Import net for network errors
+	"testing"             // Import the testing package for writing unit tests
+	"time"                // Import time for the backoff
+
+	"github.com/jackc/pgx/v5/pgconn"         // Import pgconn for Postgres errors
+	"github.com/stretchr/testify/assert"     // Import assert for its sample error
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// RetryingPackRepositoryTestSuite defines the test suite for the retry decorator
+type RetryingPackRepositoryTestSuite struct {
+	suite.Suite                         // Embed the testify suite
+	next        *stubPackRepository     // Wrapped repository
+	repo        *RetryingPackRepository // Decorator under test
+	waits       []time.Duration         // Waits between attempts
+}
+
+// SetupTest sets up the test environment before each test
+func (s *RetryingPackRepositoryTestSuite) SetupTest() {
+	s.next = newStubPackRepository()
+	s.repo = NewRetryingPackRepository(s.next, 3, 10*time.Millisecond)
+	s.waits = nil
+	s.repo.sleep = func(d time.Duration) { s.waits = append(s.waits, d) }
+}
+
+// TestRetryingPackRepositoryTestSuite runs the test suite
+func TestRetryingPackRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RetryingPackRepositoryTestSuite))
+}
+
+// TestRetriesTransientErrors tests that transient failures are retried with a doubling backoff
+func (s *RetryingPackRepositoryTestSuite) TestRetriesTransientErrors() {
+	s.next.errors["ListVersions"] = []error{driver.ErrBadConn, fmt.Errorf("read: %w", &net.OpError{Op: "read", Err: assert.AnError})}
+
+	versions, err := s.repo.ListVersions()
+	s.Assert().NoError(err, "Expected the third attempt to succeed")
+	s.Assert().Len(versions, 1, "Versions should be returned")
+	s.Assert().Equal(3, s.next.calls["ListVersions"], "Expected three attempts")
+	s.Assert().Equal([]time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, s.waits, "Backoff should double")
+}
+
+// TestGivesUp tests that the last error is returned once the attempts run out
+func (s *RetryingPackRepositoryTestSuite) TestGivesUp() {
+	s.next.errors["LatestVersionID"] = []error{driver.ErrBadConn, driver.ErrBadConn, driver.ErrBadConn, driver.ErrBadConn}
+
+	_, err := s.repo.LatestVersionID()
+	s.Assert().Equal(driver.ErrBadConn, err, "Expected the last error")
+	s.Assert().Equal(3, s.next.calls["LatestVersionID"], "Expected three attempts")
+	s.Assert().Len(s.waits, 2, "Expected a wait between each attempt")
+}
+
+// TestPermanentErrors tests that errors that will not go away are returned at once
+func (s *RetryingPackRepositoryTestSuite) TestPermanentErrors() {
+	s.next.errors["GetActiveVersion"] = []error{domain.ErrNoActiveVersion}
+
+	_, err := s.repo.GetVersion(9)
+	s.Assert().Equal(ErrVersionNotFound, err, "Expected not found")
+	_, err = s.repo.GetActiveVersion(time.Time{})
+	s.Assert().Equal(domain.ErrNoActiveVersion, err, "Expected no active version")
+	s.Assert().Equal(1, s.next.calls["GetVersion"], "Lookup should be attempted once")
+	s.Assert().Equal(1, s.next.calls["GetActiveVersion"], "Read should be attempted once")
+	s.Assert().Empty(s.waits, "Permanent errors should not be retried")
+}
+
+// TestSavesAreNotRetried tests that saves are attempted once, since a lost reply may hide a success
+func (s *RetryingPackRepositoryTestSuite) TestSavesAreNotRetried() {
+	s.next.errors["SaveVersion"] = []error{driver.ErrBadConn}
+	s.next.errors["SaveVersionIfLatest"] = []error{driver.ErrBadConn}
+
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{})
+	s.Assert().Equal(driver.ErrBadConn, err, "Expected the save error")
+	_, err = s.repo.SaveVersionIfLatest(domain.PackSizeVersion{}, 1)
+	s.Assert().Equal(driver.ErrBadConn, err, "Expected the save error")
+	s.Assert().Equal(map[string]int{"SaveVersion": 1, "SaveVersionIfLatest": 1}, s.next.calls, "Saves should be attempted once")
+}
+
+// TestIsTransient tests which errors are considered transient
+func (s *RetryingPackRepositoryTestSuite) TestIsTransient() {
+	for _, tc := range []struct {
+		err       error
+		transient bool
+	}{
+		{err: nil, transient: false},
+		{err: assert.AnError, transient: false},
+		{err: ErrVersionConflict, transient: false},
+		{err: fmt.Errorf("query: %w", driver.ErrBadConn), transient: true},
+		{err: &net.OpError{Op: "dial", Err: assert.AnError}, transient: true},
+		{err: &pgconn.PgError{Code: "40001"}, transient: true},
+		{err: &pgconn.PgError{Code: "08006"}, transient: true},
+		{err: &pgconn.PgError{Code: "23505"}, transient: false},
+	} {
+		s.Assert().Equal(tc.transient, IsTransient(tc.err), "Unexpected result for %v", tc.err)
+	}
+}
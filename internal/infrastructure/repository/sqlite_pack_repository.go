@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure-Go SQLite driver, so the binary still builds with CGO_ENABLED=0
+
+	"order-packs-calculator/internal/domain"
+)
+
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// SQLiteMigrations returns the schema migrations of the SQLite repositories
+func SQLiteMigrations() ([]Migration, error) {
+	sub, err := fs.Sub(sqliteMigrations, "migrations/sqlite")
+	if err != nil {
+		return nil, err
+	}
+	return LoadMigrations(sub)
+}
+
+// OpenSQLite opens (creating if needed) the SQLite database at path. SQLite allows one writer
+// at a time, so the pool is limited to a single connection and writers queue instead of failing
+func OpenSQLite(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(FULL)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// SQLitePackRepository stores the pack-size versions and pending events of one tenant in a SQLite
+// database shared by all tenants
+type SQLitePackRepository struct {
+	db     *sql.DB
+	tenant string
+}
+
+// NewSQLitePackRepository returns the repository of a tenant in a migrated database, saving a first
+// version with the default sizes when the tenant has none yet
+func NewSQLitePackRepository(db *sql.DB, tenant string, defaultSizes []int) (*SQLitePackRepository, error) {
+	r := &SQLitePackRepository{db: db, tenant: tenant}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM pack_size_versions WHERE tenant = ?`, tenant).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		if _, err := r.SaveVersion(domain.PackSizeVersion{PackSizes: defaultSizes, Author: InitialVersionAuthor}); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+const packSizeVersionColumns = `id, pack_sizes, author, approved_by, created_at, effective_from, rollback_of`
+
+func (r *SQLitePackRepository) GetActiveVersion(at time.Time) (domain.PackSizeVersion, error) {
+	row := r.db.QueryRow(`SELECT `+packSizeVersionColumns+` FROM pack_size_versions
+		WHERE tenant = ? AND effective_from <= ?
+		ORDER BY effective_from DESC, id DESC LIMIT 1`, r.tenant, toUnixNano(at))
+	version, err := scanPackSizeVersion(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PackSizeVersion{}, domain.ErrNoActiveVersion
+	}
+	return version, err
+}
+
+func (r *SQLitePackRepository) SaveVersion(version domain.PackSizeVersion) (domain.PackSizeVersion, error) {
+	return r.insert(version, AnyLatestVersion, nil)
+}
+
+func (r *SQLitePackRepository) SaveVersionIfLatest(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	return r.insert(version, latestID, nil)
+}
+
+func (r *SQLitePackRepository) SaveVersionWithEvent(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	return r.insert(version, latestID, newEvent)
+}
+
+func (r *SQLitePackRepository) LatestVersionID() (int, error) {
+	var id int
+	err := r.db.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM pack_size_versions WHERE tenant = ?`, r.tenant).Scan(&id)
+	return id, err
+}
+
+// insert saves a version with the tenant's next ID, unless latestID is set and no longer the newest ID.
+// When newEvent is set, its event is inserted in the same transaction
+func (r *SQLitePackRepository) insert(version domain.PackSizeVersion, latestID int, newEvent NewEvent) (domain.PackSizeVersion, error) {
+	sizes, err := json.Marshal(version.PackSizes)
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	version.PackSizes = append([]int(nil), version.PackSizes...)
+	version.CreatedAt = time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	defer tx.Rollback() // No-op once committed
+
+	// The next ID is taken and the newest one checked in the insert itself, so concurrent saves
+	// can never reuse an ID or both pass the check
+	err = tx.QueryRow(`INSERT INTO pack_size_versions
+		(tenant, id, pack_sizes, author, approved_by, created_at, effective_from, rollback_of)
+		SELECT ?, COALESCE(MAX(id), 0) + 1, ?, ?, ?, ?, ?, ? FROM pack_size_versions WHERE tenant = ?
+		HAVING ? < 0 OR COALESCE(MAX(id), 0) = ?
+		RETURNING id`,
+		r.tenant, string(sizes), version.Author, version.ApprovedBy, toUnixNano(version.CreatedAt),
+		toUnixNano(version.EffectiveFrom), version.RollbackOf, r.tenant, latestID, latestID).Scan(&version.ID)
+	if errors.Is(err, sql.ErrNoRows) { // The HAVING clause filtered the row out
+		return domain.PackSizeVersion{}, ErrVersionConflict
+	}
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	if newEvent != nil {
+		event, err := newEvent(copyVersion(version))
+		if err != nil {
+			return domain.PackSizeVersion{}, err
+		}
+		if _, err := r.insertEvent(tx, event); err != nil {
+			return domain.PackSizeVersion{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return version, nil
+}
+
+func (r *SQLitePackRepository) AppendEvent(event domain.EventEnvelope) (domain.EventEnvelope, error) {
+	id, err := r.insertEvent(r.db, event)
+	if err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	event.ID = id
+	return event, nil
+}
+
+func (r *SQLitePackRepository) ListPendingEvents(limit int) ([]domain.EventEnvelope, error) {
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+	rows, err := r.db.Query(`SELECT id, type, occurred_at, payload FROM outbox_events WHERE tenant = ? ORDER BY id LIMIT ?`, r.tenant, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []domain.EventEnvelope{}
+	for rows.Next() {
+		event := domain.EventEnvelope{Tenant: r.tenant}
+		var occurredAt int64
+		var payload string
+		if err := rows.Scan(&event.ID, &event.Type, &occurredAt, &payload); err != nil {
+			return nil, err
+		}
+		event.OccurredAt = fromUnixNano(occurredAt)
+		event.Payload = json.RawMessage(payload)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r *SQLitePackRepository) MarkEventPublished(id int) error {
+	_, err := r.db.Exec(`DELETE FROM outbox_events WHERE tenant = ? AND id = ?`, r.tenant, id)
+	return err
+}
+
+// insertEvent stores an event of the tenant and returns its ID
+func (r *SQLitePackRepository) insertEvent(db rowQuerier, event domain.EventEnvelope) (int, error) {
+	var id int
+	err := db.QueryRow(`INSERT INTO outbox_events (tenant, type, occurred_at, payload) VALUES (?, ?, ?, ?) RETURNING id`,
+		r.tenant, string(event.Type), toUnixNano(event.OccurredAt), string(event.Payload)).Scan(&id)
+	return id, err
+}
+
+func (r *SQLitePackRepository) GetVersion(id int) (domain.PackSizeVersion, error) {
+	row := r.db.QueryRow(`SELECT `+packSizeVersionColumns+` FROM pack_size_versions WHERE tenant = ? AND id = ?`, r.tenant, id)
+	version, err := scanPackSizeVersion(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.PackSizeVersion{}, ErrVersionNotFound
+	}
+	return version, err
+}
+
+func (r *SQLitePackRepository) ListVersions() ([]domain.PackSizeVersion, error) {
+	rows, err := r.db.Query(`SELECT `+packSizeVersionColumns+` FROM pack_size_versions WHERE tenant = ? ORDER BY id`, r.tenant)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []domain.PackSizeVersion{}
+	for rows.Next() {
+		version, err := scanPackSizeVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// ReplaceVersions swaps the tenant's rows in one transaction, so readers see either all old or all new versions
+func (r *SQLitePackRepository) ReplaceVersions(versions []domain.PackSizeVersion) error {
+	if err := CheckVersions(versions); err != nil {
+		return err
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // No-op once committed
+
+	if _, err := tx.Exec(`DELETE FROM pack_size_versions WHERE tenant = ?`, r.tenant); err != nil {
+		return err
+	}
+	for _, version := range versions {
+		sizes, err := json.Marshal(version.PackSizes)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO pack_size_versions
+			(tenant, id, pack_sizes, author, approved_by, created_at, effective_from, rollback_of)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			r.tenant, version.ID, string(sizes), version.Author, version.ApprovedBy, toUnixNano(version.CreatedAt),
+			toUnixNano(version.EffectiveFrom), version.RollbackOf); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// rowQuerier runs single-row queries, in a transaction or not
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanPackSizeVersion reads a version selected with packSizeVersionColumns
+func scanPackSizeVersion(row interface{ Scan(...interface{}) error }) (domain.PackSizeVersion, error) {
+	var version domain.PackSizeVersion
+	var sizes string
+	var createdAt, effectiveFrom int64
+	if err := row.Scan(&version.ID, &sizes, &version.Author, &version.ApprovedBy, &createdAt, &effectiveFrom, &version.RollbackOf); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	if err := json.Unmarshal([]byte(sizes), &version.PackSizes); err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	version.CreatedAt = fromUnixNano(createdAt)
+	version.EffectiveFrom = fromUnixNano(effectiveFrom)
+	return version, nil
+}
+
+// toUnixNano stores a time as Unix nanoseconds, keeping the zero time as 0 so it sorts first
+func toUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromUnixNano is the inverse of toUnixNano; times are returned in UTC
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"  // Import database/sql for the test database
+	"path/filepath" // Import filepath for the database path
+	"sync"          // Import sync for concurrent writers
+	"testing"       // Import the testing package for writing unit tests
+	"time"          // Import time for effective dates
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for versions
+)
+
+// SQLitePackRepositoryTestSuite defines the test suite for the SQLite pack repository
+type SQLitePackRepositoryTestSuite struct {
+	suite.Suite                       // Embed the testify suite
+	path        string                // Path of the database file
+	db          *sql.DB               // Migrated database
+	repo        *SQLitePackRepository // Repository of the default tenant
+}
+
+// SetupTest sets up the test environment before each test
+func (s *SQLitePackRepositoryTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "packs.db")
+	s.open()
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *SQLitePackRepositoryTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+// TestSQLitePackRepositoryTestSuite runs the test suite
+func TestSQLitePackRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SQLitePackRepositoryTestSuite))
+}
+
+// open opens and migrates the database and creates the default tenant's repository
+func (s *SQLitePackRepositoryTestSuite) open() {
+	db, err := OpenSQLite(s.path)
+	s.Require().NoError(err, "Expected no error")
+	migrations, err := SQLiteMigrations()
+	s.Require().NoError(err, "Expected no error")
+	_, err = NewMigrator(db, SQLiteDialect, migrations).Migrate()
+	s.Require().NoError(err, "Expected no error")
+
+	s.db = db
+	s.repo, err = NewSQLitePackRepository(db, "default", []int{250, 500, 1000})
+	s.Require().NoError(err, "Expected no error")
+}
+
+// TestVersions tests saving, looking up and listing versions
+func (s *SQLitePackRepositoryTestSuite) TestVersions() {
+	initial, err := s.repo.GetVersion(1)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500, 1000}, initial.PackSizes, "Seeded version should hold the defaults")
+	s.Assert().Equal(InitialVersionAuthor, initial.Author, "Seeded version should be attributed to the config")
+
+	saved, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100, 200}, Author: "alice", ApprovedBy: "bob", RollbackOf: 1})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, saved.ID, "New version should follow the seeded one")
+
+	stored, err := s.repo.GetVersion(2)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, stored.PackSizes, "Sizes should be stored")
+	s.Assert().Equal("bob", stored.ApprovedBy, "Approver should be stored")
+	s.Assert().Equal(1, stored.RollbackOf, "Rollback source should be stored")
+	s.Assert().True(saved.CreatedAt.Equal(stored.CreatedAt), "Creation time should be stored")
+
+	versions, err := s.repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "Versions should be listed oldest first")
+
+	_, err = s.repo.GetVersion(3)
+	s.Assert().Equal(ErrVersionNotFound, err, "Expected version not found")
+}
+
+// TestActiveVersion tests that scheduled versions only apply from their effective date
+func (s *SQLitePackRepositoryTestSuite) TestActiveVersion() {
+	now := time.Now()
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}, EffectiveFrom: now})
+	s.Require().NoError(err, "Expected no error")
+	_, err = s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{200}, EffectiveFrom: now.Add(time.Hour)})
+	s.Require().NoError(err, "Expected no error")
+
+	active, err := s.repo.GetActiveVersion(now.Add(-time.Minute))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(1, active.ID, "Only the seeded version applies before the first change")
+
+	active, err = s.repo.GetActiveVersion(now)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(2, active.ID, "Immediate change should apply")
+
+	active, err = s.repo.GetActiveVersion(now.Add(time.Hour))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(3, active.ID, "Scheduled change should apply from its effective date")
+}
+
+// TestPersistenceAndTenants tests that versions survive reopening and tenants do not see each other's versions
+func (s *SQLitePackRepositoryTestSuite) TestPersistenceAndTenants() {
+	_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}})
+	s.Require().NoError(err, "Expected no error")
+	s.db.Close()
+	s.open() // Reopening must not seed the defaults again
+
+	versions, err := s.repo.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "Versions should survive reopening")
+
+	other, err := NewSQLitePackRepository(s.db, "wholesale", []int{5000})
+	s.Require().NoError(err, "Expected no error")
+	versions, err = other.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(versions, 1, "New tenant should only see its own seeded version")
+	s.Assert().Equal([]int{5000}, versions[0].PackSizes, "New tenant should start with its defaults")
+}
+
+// TestSaveVersionIfLatest tests saving only on top of the newest version
+func (s *SQLitePackRepositoryTestSuite) TestSaveVersionIfLatest() {
+	testSaveVersionIfLatest(&s.Suite, s.repo)
+}
+
+// TestOutbox tests saving versions together with their events, without touching other tenants' events
+func (s *SQLitePackRepositoryTestSuite) TestOutbox() {
+	other, err := NewSQLitePackRepository(s.db, "other", []int{42})
+	s.Require().NoError(err, "Expected no error")
+	_, err = other.AppendEvent(domain.EventEnvelope{Type: domain.EventCalculationPerformed, Payload: []byte(`{}`)})
+	s.Require().NoError(err, "Expected no error")
+
+	testOutbox(&s.Suite, s.repo)
+	pending, err := other.ListPendingEvents(0)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(pending, 1, "Other tenants should keep their events")
+}
+
+// TestReplaceVersions tests replacing one tenant's versions without touching the others
+func (s *SQLitePackRepositoryTestSuite) TestReplaceVersions() {
+	other, err := NewSQLitePackRepository(s.db, "other", []int{42})
+	s.Require().NoError(err, "Expected no error")
+
+	testReplaceVersions(&s.Suite, s.repo)
+	versions, err := other.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 1, "Other tenants should keep their versions")
+}
+
+// TestConcurrentSaves tests that concurrent writers get unique, sequential IDs
+func (s *SQLitePackRepositoryTestSuite) TestConcurrentSaves() {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.repo.SaveVersion(domain.PackSizeVersion{PackSizes: []int{i + 1}})
+			s.Assert().NoError(err, "Expected no error")
+		}(i)
+	}
+	wg.Wait()
+
+	versions, err := s.repo.ListVersions()
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(versions, 21, "Every save should be kept")
+	for i, version := range versions {
+		s.Assert().Equal(i+1, version.ID, "IDs should be unique and sequential")
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"order-packs-calculator/internal/domain"
+)
+
+// WebhookRepository stores webhook subscriptions and their delivery log
+type WebhookRepository interface {
+	// CreateSubscription stores a new subscription, assigning its ID
+	CreateSubscription(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	// DeleteSubscription removes a subscription; its delivery log is kept
+	DeleteSubscription(id int) error
+	// GetSubscription returns the subscription with the given ID
+	GetSubscription(id int) (domain.WebhookSubscription, error)
+	// ListSubscriptions returns every subscription, oldest first
+	ListSubscriptions() ([]domain.WebhookSubscription, error)
+	// SaveDelivery appends a delivery attempt to the log, assigning its ID
+	SaveDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error)
+	// ListDeliveries returns the delivery attempts for a subscription, oldest first
+	ListDeliveries(subscriptionID int) ([]domain.WebhookDelivery, error)
+}
+
+// ErrWebhookNotFound is returned when a webhook subscription does not exist
+var ErrWebhookNotFound = errors.New("webhook subscription not found")
+
+// InMemoryWebhookRepository keeps subscriptions and deliveries in memory
+type InMemoryWebhookRepository struct {
+	mu            sync.RWMutex
+	nextID        int
+	subscriptions []domain.WebhookSubscription
+	deliveries    []domain.WebhookDelivery
+}
+
+func NewInMemoryWebhookRepository() *InMemoryWebhookRepository {
+	return &InMemoryWebhookRepository{nextID: 1}
+}
+
+func (r *InMemoryWebhookRepository) CreateSubscription(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subscription.ID = r.nextID
+	r.nextID++
+	r.subscriptions = append(r.subscriptions, subscription)
+	return subscription, nil
+}
+
+func (r *InMemoryWebhookRepository) DeleteSubscription(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, subscription := range r.subscriptions {
+		if subscription.ID == id {
+			r.subscriptions = append(r.subscriptions[:i], r.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return ErrWebhookNotFound
+}
+
+func (r *InMemoryWebhookRepository) GetSubscription(id int) (domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, subscription := range r.subscriptions {
+		if subscription.ID == id {
+			return subscription, nil
+		}
+	}
+	return domain.WebhookSubscription{}, ErrWebhookNotFound
+}
+
+func (r *InMemoryWebhookRepository) ListSubscriptions() ([]domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	subscriptions := make([]domain.WebhookSubscription, len(r.subscriptions))
+	copy(subscriptions, r.subscriptions)
+	return subscriptions, nil
+}
+
+func (r *InMemoryWebhookRepository) SaveDelivery(delivery domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.ID = len(r.deliveries) + 1
+	r.deliveries = append(r.deliveries, delivery)
+	return delivery, nil
+}
+
+func (r *InMemoryWebhookRepository) ListDeliveries(subscriptionID int) ([]domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	deliveries := []domain.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
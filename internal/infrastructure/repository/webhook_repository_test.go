@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"testing" // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite"      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain" // Import the domain package for subscriptions
+)
+
+// WebhookRepositoryTestSuite defines the test suite for the webhook repository
+type WebhookRepositoryTestSuite struct {
+	suite.Suite                            // Embed the testify suite
+	repo        *InMemoryWebhookRepository // Repository under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *WebhookRepositoryTestSuite) SetupTest() {
+	s.repo = NewInMemoryWebhookRepository()
+}
+
+// TestWebhookRepositoryTestSuite runs the test suite
+func TestWebhookRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
+
+// TestSubscriptions tests creating, reading and deleting subscriptions
+func (s *WebhookRepositoryTestSuite) TestSubscriptions() {
+	created, err := s.repo.CreateSubscription(domain.WebhookSubscription{URL: "http://example.com/hook"})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(1, created.ID, "IDs should start at 1")
+
+	stored, err := s.repo.GetSubscription(created.ID)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(created, stored, "Stored subscription should match")
+
+	s.Require().NoError(s.repo.DeleteSubscription(created.ID), "Expected no error")
+	_, err = s.repo.GetSubscription(created.ID)
+	s.Assert().Equal(ErrWebhookNotFound, err, "Deleted subscription should be gone")
+
+	again, err := s.repo.CreateSubscription(domain.WebhookSubscription{URL: "http://example.com/hook"})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, again.ID, "IDs should not be reused")
+}
+
+// TestDeliveries tests that the delivery log is filtered by subscription
+func (s *WebhookRepositoryTestSuite) TestDeliveries() {
+	_, err := s.repo.SaveDelivery(domain.WebhookDelivery{SubscriptionID: 1, EventID: 1})
+	s.Require().NoError(err, "Expected no error")
+	_, err = s.repo.SaveDelivery(domain.WebhookDelivery{SubscriptionID: 2, EventID: 1})
+	s.Require().NoError(err, "Expected no error")
+
+	deliveries, err := s.repo.ListDeliveries(1)
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(deliveries, 1, "Only deliveries for the subscription should be listed")
+	s.Assert().Equal(1, deliveries[0].ID, "Delivery ID should be assigned")
+}
@@ -0,0 +1,36 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"crypto/subtle" // Import subtle for comparing keys in constant time
+
+	"github.com/gofiber/fiber/v2"                            // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging" // Import the logging package for logging
+)
+
+// AdminKeyHeader is the request header carrying the admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// Admin is a middleware that lets only requests with the admin API key through
+type Admin struct {
+	key    string          // Key requests must present; never empty
+	logger *logging.Logger // Logger instance for logging rejected requests
+}
+
+// NewAdmin creates a new instance of Admin; the admin routes should not be registered at all without a key
+func NewAdmin(key string, logger *logging.Logger) *Admin {
+	return &Admin{
+		key:    key,    // Initialize the admin key
+		logger: logger, // Initialize the logger
+	}
+}
+
+// Handle rejects requests whose X-Admin-Key header does not match the admin key
+func (a *Admin) Handle(ctx *fiber.Ctx) error {
+	key := ctx.Get(AdminKeyHeader)
+	// Compare in constant time so the key cannot be guessed from response times
+	if a.key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(a.key)) != 1 {
+		a.logger.Info("Rejected admin request without a valid key") // Log the rejected request
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "valid " + AdminKeyHeader + " header is required"})
+	}
+	return ctx.Next()
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                            // Import Fiber for creating a test app
+	"github.com/stretchr/testify/suite"                      // Import testify/suite for test suites
+	"order-packs-calculator/internal/infrastructure/logging" // Import logging package
+)
+
+// AdminTestSuite defines the test suite for the admin middleware
+type AdminTestSuite struct {
+	suite.Suite            // Embed the testify suite
+	app         *fiber.App // Fiber app for testing
+}
+
+// SetupTest sets up the test environment before each test
+func (s *AdminTestSuite) SetupTest() {
+	s.app = fiber.New()
+	s.app.Get("/admin/ping", NewAdmin("s3cret", logging.NewLogger()).Handle, func(ctx *fiber.Ctx) error {
+		return ctx.SendString("pong")
+	})
+}
+
+// TestAdminTestSuite runs the test suite
+func TestAdminTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminTestSuite))
+}
+
+// TestAdminKey tests that only requests with the admin key are let through
+func (s *AdminTestSuite) TestAdminKey() {
+	for key, status := range map[string]int{
+		"":        fiber.StatusUnauthorized,
+		"wrong":   fiber.StatusUnauthorized,
+		"s3cre":   fiber.StatusUnauthorized,
+		"s3cret":  fiber.StatusOK,
+		"s3cret2": fiber.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest("GET", "/admin/ping", nil)
+		if key != "" {
+			req.Header.Set(AdminKeyHeader, key)
+		}
+		resp, err := s.app.Test(req)
+		s.Require().NoError(err, "Expected no error")
+		s.Assert().Equal(status, resp.StatusCode, "Unexpected status for key %q", key)
+	}
+}
@@ -0,0 +1,93 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"encoding/json" // Import json for checking captured values
+
+	"github.com/gofiber/fiber/v2"                            // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/domain"                 // Import the domain package for audit entries
+	"order-packs-calculator/internal/infrastructure/logging" // Import the logging package for logging
+	"order-packs-calculator/internal/service"                // Import the service package for the audit trail
+)
+
+// Snapshot captures the state a request is about to change, recorded as the audit entry's old value
+type Snapshot func(ctx *fiber.Ctx) (interface{}, error)
+
+// Audit is a middleware that records who called an endpoint, from where, and what changed
+type Audit struct {
+	audit  service.AuditService // Service storing the audit trail
+	logger *logging.Logger      // Logger instance for logging recording failures
+}
+
+// NewAudit creates a new instance of Audit
+func NewAudit(audit service.AuditService, logger *logging.Logger) *Audit {
+	return &Audit{
+		audit:  audit,  // Initialize the audit service
+		logger: logger, // Initialize the logger
+	}
+}
+
+// Track returns a middleware recording the request under the given action. The snapshot, if any,
+// is taken before the handler runs; successful responses are recorded as the new value.
+func (a *Audit) Track(action string, snapshot Snapshot) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var oldValue json.RawMessage
+		if snapshot != nil {
+			if value, err := snapshot(ctx); err != nil { // A missing old value must not block the request
+				a.logger.Error("Failed to capture audit snapshot", err)
+			} else if encoded, err := json.Marshal(value); err == nil {
+				oldValue = encoded
+			}
+		}
+
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+
+		entry := domain.AuditEntry{
+			Tenant:     tenant(ctx),
+			Action:     action,
+			Actor:      actor(ctx),
+			SourceIP:   ctx.IP(),
+			RequestID:  ctx.GetRespHeader(fiber.HeaderXRequestID),
+			Method:     ctx.Method(),
+			Path:       ctx.Path(),
+			StatusCode: ctx.Response().StatusCode(),
+			OldValue:   oldValue,
+		}
+		if body := ctx.Response().Body(); entry.StatusCode < 400 && json.Valid(body) { // Only record changes that happened
+			entry.NewValue = redact(append(json.RawMessage(nil), body...)) // Copy, since Fiber reuses the buffer
+		}
+
+		if err := a.audit.Record(entry); err != nil { // The response has been produced; log rather than fail it
+			a.logger.Error("Failed to record audit entry", err)
+		}
+		return nil
+	}
+}
+
+// redactedFields are top-level response fields that must never reach the audit trail
+var redactedFields = []string{"secret"}
+
+// redact removes secrets such as webhook signing keys from a recorded JSON object
+func redact(value json.RawMessage) json.RawMessage {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(value, &object); err != nil { // Arrays and scalars carry no secrets
+		return value
+	}
+
+	redacted := false
+	for _, field := range redactedFields {
+		if _, ok := object[field]; ok {
+			delete(object, field)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return value
+	}
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
@@ -0,0 +1,58 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"time" // Import time for parsing the time filters
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for audit filters
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
+)
+
+// AuditController handles HTTP requests for the audit trail
+type AuditController struct {
+	audit  service.AuditService // Service querying the audit trail
+	logger *logging.Logger      // Logger instance for logging requests and errors
+}
+
+// NewAuditController creates a new instance of AuditController
+func NewAuditController(audit service.AuditService, logger *logging.Logger) *AuditController {
+	return &AuditController{
+		audit:  audit,  // Initialize the service
+		logger: logger, // Initialize the logger
+	}
+}
+
+// ListAudit handles the GET /api/audit endpoint; it supports the actor, action, from, to,
+// offset and limit query parameters and only returns entries of the caller's tenant
+func (c *AuditController) ListAudit(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to list audit entries") // Log the incoming request
+
+	filter := repository.AuditFilter{
+		Tenant: tenant(ctx), // Tenants only see their own trail
+		Actor:  ctx.Query("actor"),
+		Action: ctx.Query("action"),
+		Offset: ctx.QueryInt("offset"),
+		Limit:  ctx.QueryInt("limit"),
+	}
+	for param, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := ctx.Query(param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil { // Reject malformed times instead of silently ignoring the filter
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid " + param + " time, expected RFC 3339"})
+		}
+		*target = parsed
+	}
+
+	page, err := c.audit.ListAudit(filter)
+	if err != nil {
+		c.logger.Error("Failed to list audit entries", err) // Log the error
+		return ctx.Status(statusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully listed audit entries") // Log the successful retrieval
+	return ctx.JSON(page)
+}
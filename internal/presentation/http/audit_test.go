@@ -0,0 +1,124 @@
+package http
+
+import (
+	"bytes"             // Import bytes for creating request bodies
+	"errors"            // Import errors for simulated snapshot failures
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/gofiber/fiber/v2/middleware/requestid"          // Import requestid to assign request IDs
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for audit entries
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for audit storage
+	"order-packs-calculator/internal/service"                   // Import the service package for the audit trail
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// AuditTestSuite defines the test suite for the audit middleware and controller
+type AuditTestSuite struct {
+	suite.Suite
+	app         *fiber.App                          // Fiber app for testing
+	store       *repository.InMemoryAuditRepository // Store the middleware records to
+	ctrl        *gomock.Controller                  // Gomock controller for managing mocks
+	mockService *mocks.MockAuditService             // Mock service behind the controller
+}
+
+// SetupTest sets up the test environment before each test
+func (s *AuditTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockAuditService(s.ctrl)
+	s.store = repository.NewInMemoryAuditRepository()
+	audit := NewAudit(service.NewAuditUseCase(s.store), logging.NewLogger())
+
+	s.app = fiber.New()
+	s.app.Use(requestid.New())
+	s.app.Post("/things", audit.Track("thing.update", func(*fiber.Ctx) (interface{}, error) {
+		return fiber.Map{"size": 1}, nil
+	}), func(ctx *fiber.Ctx) error {
+		return ctx.JSON(fiber.Map{"size": 2, "secret": "s3cret"})
+	})
+	s.app.Post("/broken", audit.Track("thing.break", func(*fiber.Ctx) (interface{}, error) {
+		return nil, errors.New("snapshot failed")
+	}), func(ctx *fiber.Ctx) error {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "Validation failed"})
+	})
+	s.app.Get("/api/audit", NewAuditController(s.mockService, logging.NewLogger()).ListAudit)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *AuditTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestAuditTestSuite runs the test suite
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
+
+// lastEntry returns the most recent audit entry
+func (s *AuditTestSuite) lastEntry() domain.AuditEntry {
+	entries, _, err := s.store.ListAudit(repository.AuditFilter{Limit: 1})
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(entries, 1, "An entry should be recorded")
+	return entries[0]
+}
+
+// TestTrackRecordsChange tests that a successful change is recorded with its context and values
+func (s *AuditTestSuite) TestTrackRecordsChange() {
+	req := httptest.NewRequest("POST", "/things", bytes.NewBufferString(`{"size":2}`))
+	req.Header.Set(ActorHeader, "alice")
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	entry := s.lastEntry()
+	s.Assert().Equal("thing.update", entry.Action, "Action should match")
+	s.Assert().Equal("alice", entry.Actor, "Actor should match")
+	s.Assert().Equal("0.0.0.0", entry.SourceIP, "Source IP should be recorded")
+	s.Assert().Equal("req-1", entry.RequestID, "Request ID should be recorded")
+	s.Assert().Equal("POST", entry.Method, "Method should match")
+	s.Assert().Equal("/things", entry.Path, "Path should match")
+	s.Assert().Equal(fiber.StatusOK, entry.StatusCode, "Status should match")
+	s.Assert().JSONEq(`{"size":1}`, string(entry.OldValue), "Old value should come from the snapshot")
+	s.Assert().JSONEq(`{"size":2}`, string(entry.NewValue), "New value should be the response without secrets")
+	s.Assert().False(entry.At.IsZero(), "Entry should be timestamped")
+}
+
+// TestTrackRecordsFailure tests that failed calls are recorded without a new value
+func (s *AuditTestSuite) TestTrackRecordsFailure() {
+	resp, err := s.app.Test(httptest.NewRequest("POST", "/broken", nil))
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Equal(fiber.StatusUnprocessableEntity, resp.StatusCode, "Snapshot failures should not block the request")
+
+	entry := s.lastEntry()
+	s.Assert().Equal("anonymous", entry.Actor, "Anonymous callers should be recorded")
+	s.Assert().NotEmpty(entry.RequestID, "A request ID should be generated")
+	s.Assert().Equal(fiber.StatusUnprocessableEntity, entry.StatusCode, "Status should match")
+	s.Assert().Nil(entry.OldValue, "Old value should be missing when the snapshot failed")
+	s.Assert().Nil(entry.NewValue, "Failed calls should not record a new value")
+}
+
+// TestListAudit tests that query parameters become filters
+func (s *AuditTestSuite) TestListAudit() {
+	s.mockService.EXPECT().ListAudit(gomock.Any()).DoAndReturn(func(filter repository.AuditFilter) (service.AuditPage, error) {
+		s.Assert().Equal("alice", filter.Actor, "Actor filter should match")
+		s.Assert().Equal("quote.create", filter.Action, "Action filter should match")
+		s.Assert().Equal(2025, filter.From.Year(), "From filter should be parsed")
+		s.Assert().True(filter.To.IsZero(), "Missing filters should stay empty")
+		s.Assert().Equal(20, filter.Offset, "Offset should match")
+		s.Assert().Equal(10, filter.Limit, "Limit should match")
+		return service.AuditPage{Entries: []domain.AuditEntry{}, Total: 0, Offset: 20, Limit: 10}, nil
+	})
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/audit?actor=alice&action=quote.create&from=2025-06-01T00:00:00Z&offset=20&limit=10", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/api/audit?to=yesterday", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Malformed times should be rejected")
+}
@@ -0,0 +1,115 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"errors" // Import errors for matching service errors
+	"time"   // Import time for parsing the time filters
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for filters and errors
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
+)
+
+// CalculationController handles HTTP requests for the calculation history
+type CalculationController struct {
+	calculations service.CalculationHistoryService // Service querying and replaying past calculations
+	logger       *logging.Logger                   // Logger instance for logging requests and errors
+}
+
+// NewCalculationController creates a new instance of CalculationController
+func NewCalculationController(calculations service.CalculationHistoryService, logger *logging.Logger) *CalculationController {
+	return &CalculationController{
+		calculations: calculations, // Initialize the service
+		logger:       logger,       // Initialize the logger
+	}
+}
+
+// ListCalculations handles the GET /api/calculations endpoint; it supports the orderAmount,
+// packSizeVersion, from, to, offset and limit query parameters
+func (c *CalculationController) ListCalculations(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to list calculations") // Log the incoming request
+
+	filter := repository.CalculationFilter{
+		OrderAmount:     ctx.QueryInt("orderAmount"),
+		PackSizeVersion: ctx.QueryInt("packSizeVersion"),
+		Offset:          ctx.QueryInt("offset"),
+		Limit:           ctx.QueryInt("limit"),
+	}
+	for param, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := ctx.Query(param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil { // Reject malformed times instead of silently ignoring the filter
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid " + param + " time, expected RFC 3339"})
+		}
+		*target = parsed
+	}
+
+	page, err := c.calculationsFor(ctx).ListCalculations(filter)
+	if err != nil {
+		c.logger.Error("Failed to list calculations", err) // Log the error
+		return ctx.Status(calculationStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully listed calculations") // Log the successful retrieval
+	return ctx.JSON(page)
+}
+
+// GetCalculation handles the GET /api/calculations/:id endpoint to retrieve one calculation
+func (c *CalculationController) GetCalculation(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get calculation") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the calculation ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid calculation ID"})
+	}
+
+	record, err := c.calculationsFor(ctx).GetCalculation(id)
+	if err != nil {
+		c.logger.Error("Failed to get calculation", err) // Log the error
+		return ctx.Status(calculationStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully retrieved calculation") // Log the successful retrieval
+	return ctx.JSON(record)
+}
+
+// ReplayCalculation handles the POST /api/calculations/:id/replay endpoint to recalculate
+// a past order against the current pack sizes
+func (c *CalculationController) ReplayCalculation(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to replay calculation") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the calculation ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid calculation ID"})
+	}
+
+	replay, err := c.calculationsFor(ctx).ReplayCalculation(id)
+	if err != nil {
+		c.logger.Error("Failed to replay calculation", err) // Log the error
+		return ctx.Status(calculationStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully replayed calculation") // Log the successful replay
+	return ctx.JSON(replay)
+}
+
+// calculationsFor returns the calculation history service of the request's tenant, falling back to the controller's own
+func (c *CalculationController) calculationsFor(ctx *fiber.Ctx) service.CalculationHistoryService {
+	if services, ok := tenantServices(ctx); ok { // Tenancy middleware selected the tenant's services
+		return services.Calculations
+	}
+	return c.calculations
+}
+
+// calculationStatusForError maps calculation history errors to HTTP status codes
+func calculationStatusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrCalculationNotFound):
+		return fiber.StatusNotFound
+	default:
+		return statusForError(err)
+	}
+}
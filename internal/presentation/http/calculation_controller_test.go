@@ -0,0 +1,110 @@
+package http
+
+import (
+	"encoding/json"     // Import json for encoding/decoding
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+	"time"              // Import time for the time filters
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for calculation records
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for filters and errors
+	"order-packs-calculator/internal/service"                   // Import the service package for pages and replays
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// CalculationControllerTestSuite defines the test suite for the calculation history handlers
+type CalculationControllerTestSuite struct {
+	suite.Suite                                      // Embed the testify suite
+	app         *fiber.App                           // Fiber app for testing
+	mockService *mocks.MockCalculationHistoryService // Use gomock-generated mock type
+	ctrl        *gomock.Controller                   // Gomock controller for managing mocks
+}
+
+// SetupTest sets up the test environment before each test
+func (s *CalculationControllerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockCalculationHistoryService(s.ctrl)
+	controller := NewCalculationController(s.mockService, logging.NewLogger())
+
+	s.app = fiber.New()
+	api := s.app.Group("/api")
+	api.Get("/calculations", controller.ListCalculations)
+	api.Get("/calculations/:id", controller.GetCalculation)
+	api.Post("/calculations/:id/replay", controller.ReplayCalculation)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *CalculationControllerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestCalculationControllerTestSuite runs the test suite
+func TestCalculationControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(CalculationControllerTestSuite))
+}
+
+// TestListCalculations tests that query parameters become the filter and malformed times are rejected
+func (s *CalculationControllerTestSuite) TestListCalculations() {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	s.mockService.EXPECT().ListCalculations(repository.CalculationFilter{
+		OrderAmount: 263, PackSizeVersion: 2, From: from, Offset: 10, Limit: 5,
+	}).Return(service.CalculationPage{
+		Calculations: []domain.CalculationRecord{{ID: 11, OrderAmount: 263}}, Total: 11, Offset: 10, Limit: 5,
+	}, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET",
+		"/api/calculations?orderAmount=263&packSizeVersion=2&from=2025-06-01T00:00:00Z&offset=10&limit=5", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var page service.CalculationPage
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&page), "Expected no error decoding response")
+	s.Assert().Equal(11, page.Total, "Total should be returned")
+	s.Assert().Len(page.Calculations, 1, "Calculations should be returned")
+
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/api/calculations?to=yesterday", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status BadRequest")
+}
+
+// TestGetCalculation tests retrieving one calculation and unknown IDs
+func (s *CalculationControllerTestSuite) TestGetCalculation() {
+	s.mockService.EXPECT().GetCalculation(1).Return(domain.CalculationRecord{ID: 1, OrderAmount: 263}, nil)
+	s.mockService.EXPECT().GetCalculation(2).Return(domain.CalculationRecord{}, repository.ErrCalculationNotFound)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/calculations/1", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/api/calculations/2", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusNotFound, resp.StatusCode, "Expected status NotFound")
+
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/api/calculations/abc", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status BadRequest")
+}
+
+// TestReplayCalculation tests replaying a past calculation
+func (s *CalculationControllerTestSuite) TestReplayCalculation() {
+	s.mockService.EXPECT().ReplayCalculation(1).Return(service.CalculationReplay{
+		Original:         domain.CalculationRecord{ID: 1, Packs: map[int]int{500: 1}, TotalItems: 500},
+		Replayed:         domain.CalculationRecord{Packs: map[int]int{300: 1}, TotalItems: 300},
+		Changed:          true,
+		PackChanges:      map[int]int{300: 1, 500: -1},
+		TotalItemsChange: -200,
+	}, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("POST", "/api/calculations/1/replay", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var replay service.CalculationReplay
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&replay), "Expected no error decoding response")
+	s.Assert().True(replay.Changed, "Change flag should be returned")
+	s.Assert().Equal(-200, replay.TotalItemsChange, "Total change should be returned")
+}
@@ -0,0 +1,222 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"bytes"         // Import bytes for reading the uploaded file
+	"encoding/csv"  // Import csv for the spreadsheet format
+	"encoding/json" // Import json for decoding imported pack sizes one by one
+	"errors"        // Import errors for matching import errors
+	"io"            // Import io for detecting the end of the CSV file
+	"strconv"       // Import strconv for writing pack sizes
+	"strings"       // Import strings for matching formats and headers
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
+)
+
+const (
+	// catalogueFormatJSON and catalogueFormatCSV are the formats the catalogue is exported and imported in
+	catalogueFormatJSON = "json"
+	catalogueFormatCSV  = "csv"
+
+	// catalogueCSVColumn is the CSV column holding the pack sizes; other columns are ignored
+	catalogueCSVColumn = "pack_size"
+)
+
+// CatalogueController handles HTTP requests for importing and exporting the pack-size catalogue
+type CatalogueController struct {
+	catalogue service.CatalogueService // Service exporting and importing the catalogue
+	logger    *logging.Logger          // Logger instance for logging requests and errors
+}
+
+// NewCatalogueController creates a new instance of CatalogueController
+func NewCatalogueController(catalogue service.CatalogueService, logger *logging.Logger) *CatalogueController {
+	return &CatalogueController{
+		catalogue: catalogue, // Initialize the service
+		logger:    logger,    // Initialize the logger
+	}
+}
+
+// ExportCatalogue handles the GET /api/catalogue/export endpoint; format is json (default) or csv.
+// The ETag is the version an import of the file must send in If-Match
+func (c *CatalogueController) ExportCatalogue(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to export the catalogue") // Log the incoming request
+
+	format := strings.ToLower(ctx.Query("format", catalogueFormatJSON))
+	if format != catalogueFormatJSON && format != catalogueFormatCSV {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid format, expected json or csv"})
+	}
+
+	catalogue, err := c.catalogueFor(ctx).ExportCatalogue()
+	if err != nil {
+		c.logger.Error("Failed to export the catalogue", err) // Log the error
+		return ctx.Status(statusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully exported the catalogue") // Log the successful export
+	ctx.Set(fiber.HeaderETag, packSizesETag(catalogue.Version))
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="catalogue.`+format+`"`) // Let browsers save it as a file
+	if format == catalogueFormatJSON {
+		return ctx.JSON(catalogue)
+	}
+
+	// The CSV holds the pack sizes only; the version travels in the ETag and the constraints are read-only
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{catalogueCSVColumn})
+	for _, size := range catalogue.PackSizes {
+		writer.Write([]string{strconv.Itoa(size)})
+	}
+	writer.Flush()
+	ctx.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	return ctx.Send(buf.Bytes())
+}
+
+// ImportCatalogue handles the POST /api/catalogue/import endpoint. The body is a CSV file with a
+// pack_size column or a JSON document with packSizes, as named by the format query parameter or the
+// Content-Type. With dryRun=true it only reports what would change and which rows are invalid;
+// otherwise it requires If-Match like POST /api/pack-sizes and saves the imported pack sizes
+func (c *CatalogueController) ImportCatalogue(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to import the catalogue") // Log the incoming request
+
+	dryRun := ctx.QueryBool("dryRun")
+	latestID := service.AnyPackSizeVersion
+	if !dryRun { // Imports replace the pack sizes, so they must name the version they were based on
+		ifMatch := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+		if ifMatch == "" {
+			return ctx.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{"error": "If-Match header with the ETag of the export is required"})
+		}
+		if ifMatch != "*" {
+			var ok bool
+			if latestID, ok = parsePackSizesETag(ifMatch); !ok { // An unknown or weak tag can never match the current version
+				return c.preconditionFailed(ctx, repository.ErrVersionConflict)
+			}
+		}
+	}
+
+	rows, err := parseCatalogue(ctx)
+	if err != nil {
+		c.logger.Error("Failed to parse the imported catalogue", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	report, err := c.catalogueFor(ctx).ImportCatalogue(rows, actor(ctx), dryRun, latestID)
+	var importErr *service.CatalogueImportError
+	switch {
+	case errors.As(err, &importErr):
+		c.logger.Error("Rejected invalid catalogue", err) // Log the rejected rows
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(importErr.Report)
+	case errors.Is(err, repository.ErrVersionConflict):
+		c.logger.Error("Pack sizes changed since the catalogue was exported", err) // Log the conflict
+		return c.preconditionFailed(ctx, err)
+	case err != nil:
+		c.logger.Error("Failed to import the catalogue", err) // Log the error
+		return ctx.Status(statusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully imported the catalogue") // Log the successful import
+	// A saved version is now the newest; dry runs and unchanged imports leave the ETag as it was
+	if report.Version != nil {
+		ctx.Set(fiber.HeaderETag, packSizesETag(report.Version.ID))
+	}
+	return ctx.JSON(report)
+}
+
+// preconditionFailed answers an import based on an outdated export with 412 and the current ETag
+func (c *CatalogueController) preconditionFailed(ctx *fiber.Ctx, err error) error {
+	if catalogue, exportErr := c.catalogueFor(ctx).ExportCatalogue(); exportErr == nil {
+		ctx.Set(fiber.HeaderETag, packSizesETag(catalogue.Version))
+	}
+	return ctx.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{"error": err.Error()})
+}
+
+// parseCatalogue reads the imported rows in the format named by the format query parameter or, without
+// one, the Content-Type; JSON is assumed when neither names CSV
+func parseCatalogue(ctx *fiber.Ctx) ([]service.CatalogueRow, error) {
+	format := strings.ToLower(ctx.Query("format"))
+	if format == "" && strings.Contains(strings.ToLower(ctx.Get(fiber.HeaderContentType)), catalogueFormatCSV) {
+		format = catalogueFormatCSV
+	}
+	switch format {
+	case "", catalogueFormatJSON:
+		return parseCatalogueJSON(ctx.Body())
+	case catalogueFormatCSV:
+		return parseCatalogueCSV(ctx.Body())
+	default:
+		return nil, errors.New("invalid format, expected json or csv")
+	}
+}
+
+// parseCatalogueJSON reads the packSizes array of a JSON document; row n is the nth entry. Entries
+// are kept as written, so a bad entry is reported as a row instead of failing the whole document
+func parseCatalogueJSON(body []byte) ([]service.CatalogueRow, error) {
+	var document struct {
+		PackSizes []json.RawMessage `json:"packSizes"` // Version and constraints of an export are ignored
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, errors.New("invalid JSON document")
+	}
+
+	rows := make([]service.CatalogueRow, len(document.PackSizes))
+	for i, raw := range document.PackSizes {
+		value := string(raw)
+		var text string
+		if json.Unmarshal(raw, &text) == nil { // Spreadsheet tools often quote numbers
+			value = text
+		}
+		rows[i] = service.CatalogueRow{Row: i + 1, PackSize: value}
+	}
+	return rows, nil
+}
+
+// parseCatalogueCSV reads the pack_size column of a CSV file whose first row is a header; rows are
+// numbered by their line in the file, as spreadsheets number them
+func parseCatalogueCSV(body []byte) ([]service.CatalogueRow, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1 // Rows may leave trailing columns out
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, errors.New("invalid CSV file: " + err.Error())
+	}
+	column := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")), catalogueCSVColumn) { // Excel starts UTF-8 files with a byte order mark
+			column = i
+			break
+		}
+	}
+	if column < 0 {
+		return nil, errors.New("CSV header has no " + catalogueCSVColumn + " column")
+	}
+
+	var rows []service.CatalogueRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, errors.New("invalid CSV file: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		row := service.CatalogueRow{Row: line}
+		if column < len(record) {
+			row.PackSize = record[column]
+		}
+		rows = append(rows, row)
+	}
+}
+
+// catalogueFor returns the catalogue service of the request's tenant, falling back to the controller's own
+func (c *CatalogueController) catalogueFor(ctx *fiber.Ctx) service.CatalogueService {
+	if services, ok := tenantServices(ctx); ok { // Tenancy middleware selected the tenant's services
+		return services.Catalogue
+	}
+	return c.catalogue
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"     // Import json for encoding/decoding
+	"io"                // Import io for reading CSV responses
+	"net/http/httptest" // Import httptest for HTTP testing
+	"strings"           // Import strings for request bodies
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for versions
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for catalogue types
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// CatalogueControllerTestSuite defines the test suite for the catalogue handlers
+type CatalogueControllerTestSuite struct {
+	suite.Suite                             // Embed the testify suite
+	app         *fiber.App                  // Fiber app for testing
+	mockService *mocks.MockCatalogueService // Use gomock-generated mock type
+	ctrl        *gomock.Controller          // Gomock controller for managing mocks
+}
+
+// SetupTest sets up the test environment before each test
+func (s *CatalogueControllerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockCatalogueService(s.ctrl)
+	controller := NewCatalogueController(s.mockService, logging.NewLogger())
+
+	s.app = fiber.New()
+	api := s.app.Group("/api")
+	api.Get("/catalogue/export", controller.ExportCatalogue)
+	api.Post("/catalogue/import", controller.ImportCatalogue)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *CatalogueControllerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestCatalogueControllerTestSuite runs the test suite
+func TestCatalogueControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogueControllerTestSuite))
+}
+
+// TestExportCatalogue tests exporting in JSON and CSV with the version as ETag
+func (s *CatalogueControllerTestSuite) TestExportCatalogue() {
+	catalogue := service.Catalogue{Version: 3, PackSizes: []int{250, 500}, Constraints: service.CatalogueConstraints{MaxPackSizes: 20, MaxPackSize: 1000}}
+	s.mockService.EXPECT().ExportCatalogue().Return(catalogue, nil).Times(2)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/catalogue/export", nil))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	s.Assert().Equal(`"3"`, resp.Header.Get(fiber.HeaderETag), "ETag should name the version")
+	var exported service.Catalogue
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&exported), "Expected no error decoding response")
+	s.Assert().Equal(catalogue, exported, "JSON should hold the whole catalogue")
+
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/api/catalogue/export?format=CSV", nil))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	s.Assert().Equal("text/csv; charset=utf-8", resp.Header.Get(fiber.HeaderContentType), "Expected CSV")
+	s.Assert().Contains(resp.Header.Get(fiber.HeaderContentDisposition), `filename="catalogue.csv"`, "Expected a file name")
+	body, _ := io.ReadAll(resp.Body)
+	s.Assert().Equal("pack_size\n250\n500\n", string(body), "CSV should list the pack sizes")
+
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/api/catalogue/export?format=xml", nil))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Unknown formats should be rejected")
+}
+
+// TestImportCSV tests that CSV rows are numbered by line and extra columns are ignored
+func (s *CatalogueControllerTestSuite) TestImportCSV() {
+	s.mockService.EXPECT().ImportCatalogue([]service.CatalogueRow{
+		{Row: 2, PackSize: "250"}, {Row: 4, PackSize: "x"}, {Row: 5, PackSize: ""},
+	}, "alice", true, service.AnyPackSizeVersion).Return(service.CatalogueImport{DryRun: true, Errors: []service.RowError{{Row: 4, Message: "bad"}}}, nil)
+
+	req := httptest.NewRequest("POST", "/api/catalogue/import?dryRun=true", strings.NewReader("\ufeffnote,Pack_Size\nsmall,250\n\nbad,x\nmissing\n"))
+	req.Header.Set(fiber.HeaderContentType, "text/csv")
+	req.Header.Set(ActorHeader, "alice")
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Dry runs should report errors with status OK")
+	var report service.CatalogueImport
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&report), "Expected no error decoding response")
+	s.Assert().Equal([]service.RowError{{Row: 4, Message: "bad"}}, report.Errors, "Row errors should be returned")
+
+	// Files without the pack_size column cannot be read
+	req = httptest.NewRequest("POST", "/api/catalogue/import?dryRun=true&format=csv", strings.NewReader("size\n250\n"))
+	resp, err = s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status Bad Request")
+}
+
+// TestImportJSON tests that JSON entries are kept as written and the import is saved on top of If-Match
+func (s *CatalogueControllerTestSuite) TestImportJSON() {
+	s.mockService.EXPECT().ImportCatalogue([]service.CatalogueRow{
+		{Row: 1, PackSize: "100"}, {Row: 2, PackSize: "200"}, {Row: 3, PackSize: "1.5"},
+	}, anonymousActor, false, 3).Return(service.CatalogueImport{Valid: true, Changed: true, Version: &domain.PackSizeVersion{ID: 4}}, nil)
+
+	req := httptest.NewRequest("POST", "/api/catalogue/import", strings.NewReader(`{"version":3,"packSizes":[100,"200",1.5]}`))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderIfMatch, `"3"`)
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	s.Assert().Equal(`"4"`, resp.Header.Get(fiber.HeaderETag), "ETag should name the saved version")
+
+	req = httptest.NewRequest("POST", "/api/catalogue/import", strings.NewReader(`{"packSizes":`))
+	req.Header.Set(fiber.HeaderIfMatch, "*")
+	resp, err = s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Malformed JSON should be rejected")
+}
+
+// TestImportPreconditions tests that imports must name the version they are based on
+func (s *CatalogueControllerTestSuite) TestImportPreconditions() {
+	body := `{"packSizes":[100]}`
+
+	resp, err := s.app.Test(httptest.NewRequest("POST", "/api/catalogue/import", strings.NewReader(body)))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusPreconditionRequired, resp.StatusCode, "If-Match should be required")
+
+	s.mockService.EXPECT().ImportCatalogue(gomock.Any(), anonymousActor, false, 1).Return(service.CatalogueImport{}, repository.ErrVersionConflict)
+	s.mockService.EXPECT().ExportCatalogue().Return(service.Catalogue{Version: 2}, nil)
+	req := httptest.NewRequest("POST", "/api/catalogue/import", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+	resp, err = s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusPreconditionFailed, resp.StatusCode, "Outdated exports should conflict")
+	s.Assert().Equal(`"2"`, resp.Header.Get(fiber.HeaderETag), "Current ETag should be returned")
+}
+
+// TestImportInvalid tests that an import with invalid rows is rejected with the report
+func (s *CatalogueControllerTestSuite) TestImportInvalid() {
+	report := service.CatalogueImport{PackSizes: []int{}, Added: []int{}, Removed: []int{}, Errors: []service.RowError{{Row: 1, Message: "pack size must be positive"}}}
+	s.mockService.EXPECT().ImportCatalogue(gomock.Any(), anonymousActor, false, service.AnyPackSizeVersion).Return(service.CatalogueImport{}, &service.CatalogueImportError{Report: report})
+
+	req := httptest.NewRequest("POST", "/api/catalogue/import", strings.NewReader(`{"packSizes":[-1]}`))
+	req.Header.Set(fiber.HeaderIfMatch, "*")
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusUnprocessableEntity, resp.StatusCode, "Expected status Unprocessable Entity")
+	var body service.CatalogueImport
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&body), "Expected no error decoding response")
+	s.Assert().Equal(report, body, "Report should be returned")
+}
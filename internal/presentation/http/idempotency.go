@@ -0,0 +1,123 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"crypto/sha256" // Import sha256 for request fingerprints
+	"encoding/hex"  // Import hex for encoding fingerprints
+	"errors"        // Import errors for matching repository errors
+	"net/textproto" // Import textproto for canonical header names
+	"time"          // Import time for the response TTL
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for stored responses
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses that were replayed from the store
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// unreplayedHeaders are response headers that describe one exchange rather than the result, so a
+// replay sends its own instead of the stored ones
+var unreplayedHeaders = map[string]bool{
+	fiber.HeaderContentLength:    true,
+	fiber.HeaderDate:             true,
+	fiber.HeaderServer:           true,
+	fiber.HeaderConnection:       true,
+	fiber.HeaderTransferEncoding: true,
+	textproto.CanonicalMIMEHeaderKey(fiber.HeaderXRequestID): true, // Fiber spells it X-Request-ID
+}
+
+// Idempotency makes retried mutating requests return the original result instead of re-executing
+type Idempotency struct {
+	store  repository.IdempotencyRepository // Store of responses by idempotency key
+	ttl    time.Duration                    // How long a response is replayed for
+	logger *logging.Logger                  // Logger instance for logging replays and errors
+}
+
+// NewIdempotency creates a new instance of Idempotency
+func NewIdempotency(store repository.IdempotencyRepository, ttl time.Duration, logger *logging.Logger) *Idempotency {
+	return &Idempotency{
+		store:  store,  // Initialize the response store
+		ttl:    ttl,    // Initialize the replay window
+		logger: logger, // Initialize the logger
+	}
+}
+
+// Handle is a route middleware: requests without an Idempotency-Key header pass straight through,
+// the first request with a key is executed and its response stored, and retries get that response back
+func (i *Idempotency) Handle(ctx *fiber.Ctx) error {
+	key := ctx.Get(IdempotencyKeyHeader)
+	if key == "" { // Nothing to deduplicate without a key
+		return ctx.Next()
+	}
+
+	// Scope keys to the route, tenant and user so that unrelated requests cannot collide
+	scopedKey := ctx.Method() + " " + ctx.Path() + " " + tenant(ctx) + " " + actor(ctx) + " " + key
+	// The fingerprint covers everything the handler reads besides the scope: the query string (dryRun, format),
+	// the If-Match precondition and the body, so a key cannot replay a dry run for the real import
+	hash := sha256.New()
+	hash.Write([]byte(ctx.OriginalURL() + "\n" + ctx.Get(fiber.HeaderIfMatch) + "\n"))
+	hash.Write(ctx.Body())
+	fingerprint := hash.Sum(nil)
+
+	stored, err := i.store.Begin(scopedKey, hex.EncodeToString(fingerprint))
+	switch {
+	case errors.Is(err, repository.ErrIdempotencyKeyInFlight):
+		return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrIdempotencyKeyReused):
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		i.logger.Error("Failed to look up idempotency key", err) // Log the error
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	case stored != nil: // The request already ran: replay its response
+		i.logger.Info("Replaying response for idempotency key")
+		for name, values := range stored.Header { // Restore headers such as ETag along with the body
+			for _, value := range values {
+				ctx.Response().Header.Add(name, value)
+			}
+		}
+		ctx.Set(IdempotentReplayedHeader, "true")
+		return ctx.Status(stored.StatusCode).Send(stored.Body)
+	}
+
+	defer func() {
+		if r := recover(); r != nil { // The handler panicked; free the key so retries are not refused forever
+			i.store.Release(scopedKey)
+			panic(r) // Leave the panic to the recover middleware, if any
+		}
+	}()
+	if err := ctx.Next(); err != nil { // The handler failed outside of a response; allow a retry
+		i.store.Release(scopedKey)
+		return err
+	}
+
+	status := ctx.Response().StatusCode()
+	if status >= fiber.StatusInternalServerError { // Server errors are worth retrying, so they are not stored
+		err = i.store.Release(scopedKey)
+	} else {
+		err = i.store.Complete(scopedKey, repository.StoredResponse{
+			StatusCode: status,
+			Header:     responseHeader(ctx),
+			Body:       append([]byte(nil), ctx.Response().Body()...), // Copy: Fiber reuses the buffer
+		}, i.ttl)
+	}
+	if err != nil { // The response is already written; a storage failure must not replace it
+		i.logger.Error("Failed to store idempotent response", err)
+	}
+	return nil
+}
+
+// responseHeader copies the headers of the response that are worth replaying
+func responseHeader(ctx *fiber.Ctx) map[string][]string {
+	header := map[string][]string{}
+	ctx.Response().Header.VisitAll(func(key, value []byte) {
+		name := textproto.CanonicalMIMEHeaderKey(string(key))
+		if !unreplayedHeaders[name] {
+			header[name] = append(header[name], string(value)) // Copy: Fiber reuses the buffers
+		}
+	})
+	return header
+}
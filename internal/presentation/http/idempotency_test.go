@@ -0,0 +1,208 @@
+package http
+
+import (
+	"bytes"             // Import bytes for creating request bodies
+	"io"                // Import io for reading response bodies
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+	"time"              // Import time for the TTL
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/gofiber/fiber/v2/middleware/recover"            // Import recover for handlers that panic
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/assert"                        // Import assert for error values
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for versions
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for the store
+	"order-packs-calculator/internal/service"                   // Import the service package for catalogue rows
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// IdempotencyTestSuite defines the test suite for the idempotency middleware
+type IdempotencyTestSuite struct {
+	suite.Suite                                  // Embed the testify suite
+	app         *fiber.App                       // Fiber app for testing
+	mockService *mocks.MockCalculatePacksService // Use gomock-generated mock type
+	ctrl        *gomock.Controller               // Gomock controller for managing mocks
+}
+
+// SetupTest sets up the test environment before each test
+func (s *IdempotencyTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockCalculatePacksService(s.ctrl)
+	logger := logging.NewLogger()
+	controller := NewPackController(s.mockService, logger)
+	idempotency := NewIdempotency(repository.NewInMemoryIdempotencyRepository(), time.Hour, logger)
+
+	s.app = fiber.New()
+	s.app.Post("/api/pack-sizes", idempotency.Handle, controller.UpdatePackSizes)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *IdempotencyTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestIdempotencyTestSuite runs the test suite
+func TestIdempotencyTestSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyTestSuite))
+}
+
+// send posts new pack sizes with an optional idempotency key
+func (s *IdempotencyTestSuite) send(body string, key string) (int, string, string) {
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderIfMatch, "*")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	payload, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err, "Expected no error reading response")
+	return resp.StatusCode, string(payload), resp.Header.Get(IdempotentReplayedHeader)
+}
+
+// TestRetryReplaysOriginalResponse tests that a retried request is executed only once
+func (s *IdempotencyTestSuite) TestRetryReplaysOriginalResponse() {
+	s.mockService.EXPECT().UpdatePackSizes([]int{100}, "anonymous").
+		Return(domain.PackSizeVersion{ID: 2, PackSizes: []int{100}}, nil).Times(1)
+
+	status, first, replayed := s.send(`{"packSizes":[100]}`, "retry-1")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+	s.Assert().Empty(replayed, "First response should not be a replay")
+
+	status, second, replayed := s.send(`{"packSizes":[100]}`, "retry-1")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+	s.Assert().Equal(first, second, "Retry should get the original response")
+	s.Assert().Equal("true", replayed, "Retry should be marked as replayed")
+}
+
+// TestReplayRestoresHeaders tests that a replay carries the original headers, such as the ETag
+func (s *IdempotencyTestSuite) TestReplayRestoresHeaders() {
+	s.mockService.EXPECT().UpdatePackSizes([]int{100}, "anonymous").Return(domain.PackSizeVersion{ID: 7}, nil).Times(1)
+
+	post := func() (string, string, string) {
+		req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(fiber.HeaderIfMatch, "*")
+		req.Header.Set(IdempotencyKeyHeader, "retry-headers")
+		resp, err := s.app.Test(req)
+		s.Require().NoError(err, "Expected no error")
+		return resp.Header.Get(fiber.HeaderETag), resp.Header.Get(fiber.HeaderContentType), resp.Header.Get(IdempotentReplayedHeader)
+	}
+
+	etag, contentType, _ := post()
+	s.Require().Equal(`"7"`, etag, "First response should carry the version")
+	replayedETag, replayedContentType, replayed := post()
+	s.Assert().Equal("true", replayed, "Retry should be marked as replayed")
+	s.Assert().Equal(etag, replayedETag, "Replay should carry the original ETag")
+	s.Assert().Equal(contentType, replayedContentType, "Replay should carry the original content type")
+}
+
+// TestPanicReleasesKey tests that a key is released when the handler panics, so a retry runs again
+func (s *IdempotencyTestSuite) TestPanicReleasesKey() {
+	idempotency := NewIdempotency(repository.NewInMemoryIdempotencyRepository(), time.Hour, logging.NewLogger())
+	calls := 0
+	app := fiber.New()
+	app.Post("/panic", recover.New(), idempotency.Handle, func(ctx *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		return ctx.SendString("ok")
+	})
+
+	for _, expected := range []int{fiber.StatusInternalServerError, fiber.StatusOK} {
+		req := httptest.NewRequest("POST", "/panic", nil)
+		req.Header.Set(IdempotencyKeyHeader, "retry-panic")
+		resp, err := app.Test(req)
+		s.Require().NoError(err, "Expected no error")
+		s.Assert().Equal(expected, resp.StatusCode, "Retry after a panic should execute again")
+	}
+	s.Assert().Equal(2, calls, "Handler should run again after the panic")
+}
+
+// TestKeyReusedWithDifferentBody tests that a key cannot be reused for another request
+func (s *IdempotencyTestSuite) TestKeyReusedWithDifferentBody() {
+	s.mockService.EXPECT().UpdatePackSizes([]int{100}, "anonymous").Return(domain.PackSizeVersion{ID: 2}, nil)
+
+	status, _, _ := s.send(`{"packSizes":[100]}`, "retry-2")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+
+	status, _, _ = s.send(`{"packSizes":[200]}`, "retry-2")
+	s.Assert().Equal(fiber.StatusUnprocessableEntity, status, "Expected status UnprocessableEntity")
+}
+
+// TestServerErrorsAreRetried tests that failed requests are executed again on retry
+func (s *IdempotencyTestSuite) TestServerErrorsAreRetried() {
+	gomock.InOrder(
+		s.mockService.EXPECT().UpdatePackSizes([]int{100}, "anonymous").Return(domain.PackSizeVersion{}, assert.AnError),
+		s.mockService.EXPECT().UpdatePackSizes([]int{100}, "anonymous").Return(domain.PackSizeVersion{ID: 2}, nil),
+	)
+
+	status, _, _ := s.send(`{"packSizes":[100]}`, "retry-3")
+	s.Assert().Equal(fiber.StatusInternalServerError, status, "Expected status InternalServerError")
+
+	status, _, replayed := s.send(`{"packSizes":[100]}`, "retry-3")
+	s.Assert().Equal(fiber.StatusOK, status, "Retry after a server error should execute again")
+	s.Assert().Empty(replayed, "Retry after a server error should not be a replay")
+}
+
+// TestWithoutKey tests that requests without a key are always executed
+func (s *IdempotencyTestSuite) TestWithoutKey() {
+	s.mockService.EXPECT().UpdatePackSizes([]int{100}, "anonymous").Return(domain.PackSizeVersion{ID: 2}, nil).Times(2)
+
+	s.send(`{"packSizes":[100]}`, "")
+	s.send(`{"packSizes":[100]}`, "")
+}
+
+// TestDryRunThenImport tests that a key used for a dry run is not replayed for the real import
+func (s *IdempotencyTestSuite) TestDryRunThenImport() {
+	catalogue := mocks.NewMockCatalogueService(s.ctrl)
+	rows := []service.CatalogueRow{{Row: 2, PackSize: "250"}}
+	gomock.InOrder(
+		catalogue.EXPECT().ImportCatalogue(rows, "anonymous", true, service.AnyPackSizeVersion).Return(service.CatalogueImport{DryRun: true}, nil),
+		catalogue.EXPECT().ImportCatalogue(rows, "anonymous", false, service.AnyPackSizeVersion).Return(service.CatalogueImport{}, nil),
+	)
+	idempotency := NewIdempotency(repository.NewInMemoryIdempotencyRepository(), time.Hour, logging.NewLogger())
+	app := fiber.New()
+	app.Post("/api/catalogue/import", idempotency.Handle, NewCatalogueController(catalogue, logging.NewLogger()).ImportCatalogue)
+
+	post := func(target string, key string) (int, string) {
+		req := httptest.NewRequest("POST", target, bytes.NewBufferString("pack_size\n250\n"))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set(fiber.HeaderIfMatch, "*")
+		req.Header.Set(IdempotencyKeyHeader, key)
+		resp, err := app.Test(req)
+		s.Require().NoError(err, "Expected no error")
+		return resp.StatusCode, resp.Header.Get(IdempotentReplayedHeader)
+	}
+
+	status, _ := post("/api/catalogue/import?dryRun=true", "import-1")
+	s.Assert().Equal(fiber.StatusOK, status, "Dry run should succeed")
+	status, replayed := post("/api/catalogue/import", "import-1")
+	s.Assert().Equal(fiber.StatusUnprocessableEntity, status, "The dry run's key should not be reused for the import")
+	s.Assert().Empty(replayed, "The dry run should not be replayed for the import")
+	status, replayed = post("/api/catalogue/import", "import-2")
+	s.Assert().Equal(fiber.StatusOK, status, "The import should run with its own key")
+	s.Assert().Empty(replayed, "The import should not be a replay")
+}
+
+// TestKeyReusedWithDifferentPrecondition tests that a key cannot be reused with another If-Match
+func (s *IdempotencyTestSuite) TestKeyReusedWithDifferentPrecondition() {
+	s.mockService.EXPECT().UpdatePackSizes([]int{100}, "anonymous").Return(domain.PackSizeVersion{ID: 2}, nil)
+
+	status, _, _ := s.send(`{"packSizes":[100]}`, "retry-4")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderIfMatch, `"2"`)
+	req.Header.Set(IdempotencyKeyHeader, "retry-4")
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusUnprocessableEntity, resp.StatusCode, "Expected status UnprocessableEntity")
+}
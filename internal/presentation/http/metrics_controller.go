@@ -0,0 +1,61 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"time" // Import time for converting durations to milliseconds
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for the call statistics
+)
+
+// MetricsController handles HTTP requests for the repository call metrics
+type MetricsController struct {
+	metrics *repository.RepositoryMetrics // Statistics recorded by the metered repositories
+	logger  *logging.Logger               // Logger instance for logging requests and errors
+}
+
+// NewMetricsController creates a new instance of MetricsController
+func NewMetricsController(metrics *repository.RepositoryMetrics, logger *logging.Logger) *MetricsController {
+	return &MetricsController{
+		metrics: metrics, // Initialize the metrics
+		logger:  logger,  // Initialize the logger
+	}
+}
+
+// CallMetrics is the JSON form of the statistics of one repository call; times are in milliseconds
+type CallMetrics struct {
+	Calls   int64   `json:"calls"`
+	Errors  int64   `json:"errors"`
+	TotalMs float64 `json:"totalMs"`
+	MaxMs   float64 `json:"maxMs"`
+	AvgMs   float64 `json:"avgMs"`
+}
+
+// GetRepositoryMetrics handles the GET /metrics/repository endpoint; it returns the statistics of every
+// repository call made since startup, keyed by "<tenant>.<repository>.<method>"
+func (c *MetricsController) GetRepositoryMetrics(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get repository metrics") // Log the incoming request
+
+	snapshot := c.metrics.Snapshot()
+	response := make(map[string]CallMetrics, len(snapshot))
+	for name, stats := range snapshot {
+		metrics := CallMetrics{
+			Calls:   stats.Calls,
+			Errors:  stats.Errors,
+			TotalMs: milliseconds(stats.Total),
+			MaxMs:   milliseconds(stats.Max),
+		}
+		if stats.Calls > 0 { // Avoid dividing by zero for names without calls
+			metrics.AvgMs = milliseconds(stats.Total / time.Duration(stats.Calls))
+		}
+		response[name] = metrics
+	}
+
+	c.logger.Info("Successfully retrieved repository metrics") // Log the successful retrieval
+	return ctx.JSON(response)
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"     // Import json for encoding/decoding
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+	"time"              // Import time for durations
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/stretchr/testify/assert"                        // Import assert for its sample error
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for the metrics
+)
+
+// MetricsControllerTestSuite defines the test suite for the metrics handler
+type MetricsControllerTestSuite struct {
+	suite.Suite                               // Embed the testify suite
+	app         *fiber.App                    // Fiber app for testing
+	metrics     *repository.RepositoryMetrics // Metrics the handler reports
+}
+
+// SetupTest sets up the test environment before each test
+func (s *MetricsControllerTestSuite) SetupTest() {
+	s.metrics = repository.NewRepositoryMetrics()
+	controller := NewMetricsController(s.metrics, logging.NewLogger())
+
+	s.app = fiber.New()
+	s.app.Get("/metrics/repository", controller.GetRepositoryMetrics)
+}
+
+// TestMetricsControllerTestSuite runs the test suite
+func TestMetricsControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsControllerTestSuite))
+}
+
+// TestGetRepositoryMetrics tests that the recorded calls are returned in milliseconds
+func (s *MetricsControllerTestSuite) TestGetRepositoryMetrics() {
+	s.metrics.Observe("default.pack_sizes.GetActiveVersion", 2*time.Millisecond, nil)
+	s.metrics.Observe("default.pack_sizes.GetActiveVersion", 4*time.Millisecond, assert.AnError)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/metrics/repository", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var metrics map[string]CallMetrics
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&metrics), "Expected no error decoding response")
+	s.Assert().Equal(map[string]CallMetrics{
+		"default.pack_sizes.GetActiveVersion": {Calls: 2, Errors: 1, TotalMs: 6, MaxMs: 4, AvgMs: 3},
+	}, metrics, "Statistics should be returned by name")
+}
+
+// TestGetRepositoryMetricsEmpty tests that no calls give an empty object
+func (s *MetricsControllerTestSuite) TestGetRepositoryMetricsEmpty() {
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/metrics/repository", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var metrics map[string]CallMetrics
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&metrics), "Expected no error decoding response")
+	s.Assert().Empty(metrics, "Expected no statistics")
+}
@@ -1,11 +1,24 @@
 package http // Define the package name as "presentation" for HTTP handlers
 
 import (
-	"github.com/gofiber/fiber/v2"                            // Import the Fiber framework for handling HTTP requests
-	"order-packs-calculator/internal/infrastructure/logging" // Import the logging package for logging
-	"order-packs-calculator/internal/service"                // Import the service package for business logic
+	"errors"  // Import errors for matching repository errors
+	"strconv" // Import strconv for entity tags
+	"strings" // Import strings for parsing If-Match
+	"time"    // Import time for effective and back-dated timestamps
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/domain"                    // Import the domain package for its errors
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
 )
 
+// ActorHeader is the request header identifying the user behind a change
+const ActorHeader = "X-User"
+
+// anonymousActor is recorded when a request does not identify its user
+const anonymousActor = "anonymous"
+
 // PackController handles HTTP requests for pack calculations
 type PackController struct {
 	calculatePacks service.CalculatePacksService // Changed to interface for testability
@@ -25,36 +38,64 @@ func (c *PackController) CalculatePacks(ctx *fiber.Ctx) error {
 	c.logger.Info("Received request to calculate packs") // Log the incoming request
 
 	var request struct { // Define a struct to parse the JSON request body
-		OrderAmount int `json:"orderAmount"` // Field to hold the order amount from the request
+		OrderAmount     int        `json:"orderAmount"`     // Field to hold the order amount from the request
+		PackSizeVersion int        `json:"packSizeVersion"` // Optional pack-size version to calculate against
+		At              *time.Time `json:"at"`              // Optional time whose pack sizes to use, for back-dated quotes
 	}
 	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
 		c.logger.Error("Failed to parse request body", err) // Log the error
 		// Return a 400 Bad Request response if parsing fails
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
 	}
+	if request.PackSizeVersion != 0 && request.At != nil { // A version and a time would contradict each other
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Specify either packSizeVersion or at, not both"})
+	}
 
 	// Call the service to calculate packs for the given order amount
-	result, totalItems, err := c.calculatePacks.Execute(request.OrderAmount)
+	var (
+		result     map[int]int
+		totalItems int
+		err        error
+	)
+	if request.PackSizeVersion != 0 { // Calculate against the pinned version if one was requested
+		result, totalItems, err = c.packsFor(ctx).ExecuteVersion(request.OrderAmount, request.PackSizeVersion)
+	} else if request.At != nil { // Calculate against the pack sizes in effect at the requested time
+		result, totalItems, err = c.packsFor(ctx).ExecuteAt(request.OrderAmount, *request.At)
+	} else {
+		result, totalItems, err = c.packsFor(ctx).Execute(request.OrderAmount)
+	}
 	if err != nil { // Check if there was an error during calculation
 		c.logger.Error("Failed to calculate packs", err) // Log the error
-		// Return a 500 Internal Server Error response if calculation fails
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		// Return an error response matching the failure
+		return ctx.Status(statusForError(err)).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	c.logger.Info("Successfully calculated packs") // Log the successful calculation
 	// Return a 200 OK response with the calculation result and total items
-	return ctx.JSON(fiber.Map{
+	response := fiber.Map{
 		"packs":      result,     // Include the pack size -> quantity map
 		"totalItems": totalItems, // Include the total items fulfilled
-	})
+	}
+	if request.PackSizeVersion != 0 {
+		response["packSizeVersion"] = request.PackSizeVersion // Echo the pinned version
+	}
+	return ctx.JSON(response)
 }
 
-// UpdatePackSizes handles the POST /api/pack-sizes endpoint to update pack sizes
+// UpdatePackSizes handles the POST /api/pack-sizes endpoint to update pack sizes.
+// The request must carry the ETag of GET /api/pack-sizes in If-Match ("*" to change whatever is current)
 func (c *PackController) UpdatePackSizes(ctx *fiber.Ctx) error {
 	c.logger.Info("Received request to update pack sizes") // Log the incoming request
 
+	// Changes must name the version they were based on, so two editors cannot silently overwrite each other
+	latestID, ok, err := c.ifMatch(ctx)
+	if !ok {
+		return err
+	}
+
 	var request struct { // Define a struct to parse the JSON request body
-		PackSizes []int `json:"packSizes"` // Field to hold the new pack sizes from the request
+		PackSizes     []int      `json:"packSizes"`     // Field to hold the new pack sizes from the request
+		EffectiveFrom *time.Time `json:"effectiveFrom"` // Optional time from which the new pack sizes apply
 	}
 	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
 		c.logger.Error("Failed to parse request body", err) // Log the error
@@ -62,31 +103,233 @@ func (c *PackController) UpdatePackSizes(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
 	}
 
-	// Call the service to update the pack sizes in the repository
-	if err := c.calculatePacks.UpdatePackSizes(request.PackSizes); err != nil {
+	// Call the service to store the pack sizes as a new version, scheduled if requested
+	var version domain.PackSizeVersion
+	if latestID != repository.AnyLatestVersion { // Only save on top of the version the caller has seen
+		var effectiveFrom time.Time // Zero applies the change immediately
+		if request.EffectiveFrom != nil {
+			effectiveFrom = *request.EffectiveFrom
+		}
+		version, err = c.packsFor(ctx).SchedulePackSizesIfLatest(request.PackSizes, actor(ctx), effectiveFrom, latestID)
+	} else if request.EffectiveFrom != nil {
+		version, err = c.packsFor(ctx).SchedulePackSizes(request.PackSizes, actor(ctx), *request.EffectiveFrom)
+	} else {
+		version, err = c.packsFor(ctx).UpdatePackSizes(request.PackSizes, actor(ctx))
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		c.logger.Error("Pack sizes changed since the caller read them", err) // Log the conflict
+		return c.preconditionFailed(ctx, err)
+	}
+	if err != nil {
 		c.logger.Error("Failed to update pack sizes", err) // Log the error
-		// Return a 500 Internal Server Error response if updating fails
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		// Return an error response matching the failure, with field errors for invalid sets
+		return ctx.Status(statusForError(err)).JSON(errorBody(err))
 	}
 
 	c.logger.Info("Successfully updated pack sizes") // Log the successful update
-	// Return a 200 OK response with a success message
-	return ctx.JSON(fiber.Map{"message": "Pack sizes updated successfully"})
+	// Return a 200 OK response with a success message and the new version, which is now the newest
+	ctx.Set(fiber.HeaderETag, packSizesETag(version.ID))
+	return ctx.JSON(fiber.Map{"message": "Pack sizes updated successfully", "version": version})
 }
 
 // GetPackSizes handles the GET /api/pack-sizes endpoint to retrieve current pack sizes
 func (c *PackController) GetPackSizes(ctx *fiber.Ctx) error {
 	c.logger.Info("Received request to get pack sizes") // Log the incoming request
 
+	// Read the version before the sizes: a change in between leaves the ETag behind the sizes,
+	// which makes the caller's next update conflict instead of overwriting sizes it never saw
+	latestID, err := c.packsFor(ctx).LatestPackSizeVersion()
+	if err != nil {
+		c.logger.Error("Failed to get pack size version", err) // Log the error
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	// Fetch the current pack sizes from the service layer (which delegates to the repository)
-	packSizes, err := c.calculatePacks.GetPackSizes() // Call the service method instead of accessing repo directly
-	if err != nil {                                   // Check if there was an error fetching pack sizes
+	packSizes, err := c.packsFor(ctx).GetPackSizes() // Call the service method instead of accessing repo directly
+	if err != nil {                                  // Check if there was an error fetching pack sizes
 		c.logger.Error("Failed to get pack sizes", err) // Log the error
 		// Return a 500 Internal Server Error response if fetching fails
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	c.logger.Info("Successfully retrieved pack sizes") // Log the successful retrieval
-	// Return a 200 OK response with the current pack sizes
+	// Return a 200 OK response with the current pack sizes, tagged with the version they belong to
+	ctx.Set(fiber.HeaderETag, packSizesETag(latestID))
 	return ctx.JSON(fiber.Map{"packSizes": packSizes})
 }
+
+// GetPackSizeHistory handles the GET /api/pack-sizes/history endpoint to list all pack-size versions
+func (c *PackController) GetPackSizeHistory(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get pack size history") // Log the incoming request
+
+	versions, err := c.packsFor(ctx).ListPackSizeVersions() // Fetch every version from the service
+	if err != nil {                                         // Check if there was an error fetching the history
+		c.logger.Error("Failed to get pack size history", err) // Log the error
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully retrieved pack size history") // Log the successful retrieval
+	return ctx.JSON(fiber.Map{"versions": versions})
+}
+
+// GetScheduledPackSizes handles the GET /api/pack-sizes/scheduled endpoint to list upcoming changes
+func (c *PackController) GetScheduledPackSizes(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get scheduled pack sizes") // Log the incoming request
+
+	versions, err := c.packsFor(ctx).ListScheduledPackSizes() // Fetch upcoming versions from the service
+	if err != nil {                                           // Check if there was an error fetching them
+		c.logger.Error("Failed to get scheduled pack sizes", err) // Log the error
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully retrieved scheduled pack sizes") // Log the successful retrieval
+	return ctx.JSON(fiber.Map{"versions": versions})
+}
+
+// GetPackSizeVersion handles the GET /api/pack-sizes/versions/:id endpoint to retrieve one version
+func (c *PackController) GetPackSizeVersion(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get pack size version") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the version ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid version ID"})
+	}
+
+	version, err := c.packsFor(ctx).GetPackSizeVersion(id) // Fetch the version from the service
+	if err != nil {                                        // Check if there was an error fetching the version
+		c.logger.Error("Failed to get pack size version", err) // Log the error
+		return ctx.Status(statusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully retrieved pack size version") // Log the successful retrieval
+	return ctx.JSON(version)
+}
+
+// RollbackPackSizes handles the POST /api/pack-sizes/versions/:id/rollback endpoint to restore a version.
+// Like an update, the request must carry the ETag of GET /api/pack-sizes in If-Match
+func (c *PackController) RollbackPackSizes(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to roll back pack sizes") // Log the incoming request
+
+	latestID, ok, err := c.ifMatch(ctx) // A rollback overwrites the current pack sizes just like an update
+	if !ok {
+		return err
+	}
+
+	id, err := ctx.ParamsInt("id") // Parse the version ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid version ID"})
+	}
+
+	var version domain.PackSizeVersion
+	if latestID != repository.AnyLatestVersion { // Only restore on top of the version the caller has seen
+		version, err = c.packsFor(ctx).RollbackPackSizesIfLatest(id, actor(ctx), latestID)
+	} else {
+		version, err = c.packsFor(ctx).RollbackPackSizes(id, actor(ctx))
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		c.logger.Error("Pack sizes changed since the caller read them", err) // Log the conflict
+		return c.preconditionFailed(ctx, err)
+	}
+	if err != nil { // Check if there was an error rolling back
+		c.logger.Error("Failed to roll back pack sizes", err) // Log the error
+		return ctx.Status(statusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully rolled back pack sizes") // Log the successful rollback
+	ctx.Set(fiber.HeaderETag, packSizesETag(version.ID))
+	return ctx.JSON(fiber.Map{"message": "Pack sizes rolled back successfully", "version": version})
+}
+
+// ifMatch reads the version a change is based on from If-Match, repository.AnyLatestVersion for "*".
+// When ok is false the response has already been written and err is what the handler should return
+func (c *PackController) ifMatch(ctx *fiber.Ctx) (latestID int, ok bool, err error) {
+	tag := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if tag == "" {
+		return 0, false, ctx.Status(fiber.StatusPreconditionRequired).JSON(fiber.Map{"error": "If-Match header with the ETag of the pack sizes is required"})
+	}
+	if tag == "*" {
+		return repository.AnyLatestVersion, true, nil
+	}
+	latestID, ok = parsePackSizesETag(tag)
+	if !ok { // An unknown or weak tag can never match the current version
+		return 0, false, c.preconditionFailed(ctx, repository.ErrVersionConflict)
+	}
+	return latestID, true, nil
+}
+
+// preconditionFailed answers a change based on an outdated version with 412 and, when it can be read,
+// the ETag of the current version so the caller knows what to reload
+func (c *PackController) preconditionFailed(ctx *fiber.Ctx, err error) error {
+	if latestID, latestErr := c.packsFor(ctx).LatestPackSizeVersion(); latestErr == nil {
+		ctx.Set(fiber.HeaderETag, packSizesETag(latestID))
+	}
+	return ctx.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{"error": err.Error()})
+}
+
+// packSizesETag formats the newest pack-size version as a strong entity tag
+func packSizesETag(latestID int) string {
+	return `"` + strconv.Itoa(latestID) + `"`
+}
+
+// parsePackSizesETag returns the version in an entity tag made by packSizesETag; weak tags are
+// rejected because If-Match compares tags strongly
+func parsePackSizesETag(tag string) (int, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	latestID, err := strconv.Atoi(tag[1 : len(tag)-1])
+	return latestID, err == nil && latestID > 0
+}
+
+// actor returns the user identified by the request, or a placeholder for anonymous callers
+func actor(ctx *fiber.Ctx) string {
+	if user := ctx.Get(ActorHeader); user != "" {
+		return user
+	}
+	return anonymousActor
+}
+
+// errorBody builds the JSON body for an error response; validation errors list the invalid fields
+func errorBody(err error) fiber.Map {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return fiber.Map{"error": "Validation failed", "fields": validationErr.Fields}
+	}
+	return fiber.Map{"error": err.Error()}
+}
+
+// statusForError maps service and repository errors to HTTP status codes
+func statusForError(err error) int {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return fiber.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrVersionNotFound), errors.Is(err, domain.ErrNoActiveVersion):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrEffectiveFromInPast):
+		return fiber.StatusBadRequest
+	case errors.Is(err, service.ErrApprovalRequired):
+		return fiber.StatusForbidden
+	case errors.Is(err, repository.ErrVersionConflict):
+		return fiber.StatusPreconditionFailed
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// packsFor returns the pack service of the request's tenant, falling back to the controller's own
+func (c *PackController) packsFor(ctx *fiber.Ctx) service.CalculatePacksService {
+	if services, ok := tenantServices(ctx); ok { // Tenancy middleware selected the tenant's services
+		return services.Packs
+	}
+	return c.calculatePacks
+}
+
+// PackSizesSnapshot returns the pack sizes currently in effect, for auditing changes to them
+func (c *PackController) PackSizesSnapshot(ctx *fiber.Ctx) (interface{}, error) {
+	packSizes, err := c.packsFor(ctx).GetPackSizes()
+	if err != nil {
+		return nil, err
+	}
+	return fiber.Map{"packSizes": packSizes}, nil
+}
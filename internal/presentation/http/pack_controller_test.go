@@ -5,12 +5,16 @@ import (
 	"encoding/json"     // Import json for encoding/decoding
 	"net/http/httptest" // Import httptest for HTTP testing
 	"testing"           // Import the testing package for writing unit tests
-
-	"github.com/gofiber/fiber/v2"                            // Import Fiber for creating a test app
-	"github.com/golang/mock/gomock"                          // Import gomock for mocking
-	"github.com/stretchr/testify/suite"                      // Import testify/suite for test suites
-	"order-packs-calculator/internal/infrastructure/logging" // Import logging package
-	"order-packs-calculator/internal/service/mocks"          // Import mocks for the service
+	"time"              // Import time for effective dates
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for versions
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for its errors
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
 )
 
 // PackControllerTestSuite defines the test suite for the presentation layer
@@ -45,6 +49,10 @@ func (s *PackControllerTestSuite) SetupTest() {
 	api.Post("/calculate", s.controller.CalculatePacks)
 	api.Post("/pack-sizes", s.controller.UpdatePackSizes)
 	api.Get("/pack-sizes", s.controller.GetPackSizes)
+	api.Get("/pack-sizes/history", s.controller.GetPackSizeHistory)
+	api.Get("/pack-sizes/scheduled", s.controller.GetScheduledPackSizes)
+	api.Get("/pack-sizes/versions/:id", s.controller.GetPackSizeVersion)
+	api.Post("/pack-sizes/versions/:id/rollback", s.controller.RollbackPackSizes)
 }
 
 // TearDownTest cleans up the test environment after each test
@@ -113,6 +121,7 @@ func (s *PackControllerTestSuite) TestCalculatePacks_InvalidRequest() {
 // TestGetPackSizes_Success tests a successful GetPackSizes request
 func (s *PackControllerTestSuite) TestGetPackSizes_Success() {
 	// Set up the mock expectation using gomock API
+	s.mockService.EXPECT().LatestPackSizeVersion().Return(3, nil)
 	s.mockService.EXPECT().GetPackSizes().Return([]int{10, 20, 50}, nil)
 
 	// Create a new HTTP request
@@ -124,6 +133,7 @@ func (s *PackControllerTestSuite) TestGetPackSizes_Success() {
 
 	// Check the response
 	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	s.Assert().Equal(`"3"`, resp.Header.Get(fiber.HeaderETag), "ETag should carry the newest version")
 
 	// Decode the response body
 	var response map[string]interface{}
@@ -137,7 +147,8 @@ func (s *PackControllerTestSuite) TestGetPackSizes_Success() {
 // TestUpdatePackSizes_Success tests a successful UpdatePackSizes request
 func (s *PackControllerTestSuite) TestUpdatePackSizes_Success() {
 	// Set up the mock expectation using gomock API
-	s.mockService.EXPECT().UpdatePackSizes([]int{100, 200, 300}).Return(nil)
+	s.mockService.EXPECT().SchedulePackSizesIfLatest([]int{100, 200, 300}, "alice", time.Time{}, 1).
+		Return(domain.PackSizeVersion{ID: 2, PackSizes: []int{100, 200, 300}, Author: "alice"}, nil)
 
 	// Create a request body
 	reqBody := map[string][]int{"packSizes": {100, 200, 300}}
@@ -146,6 +157,8 @@ func (s *PackControllerTestSuite) TestUpdatePackSizes_Success() {
 	// Create a new HTTP request
 	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, "alice")
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`) // Based on the seeded version
 
 	// Perform the request
 	resp, err := s.app.Test(req)
@@ -153,6 +166,7 @@ func (s *PackControllerTestSuite) TestUpdatePackSizes_Success() {
 
 	// Check the response
 	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	s.Assert().Equal(`"2"`, resp.Header.Get(fiber.HeaderETag), "ETag should carry the new version")
 
 	// Decode the response body
 	var response map[string]interface{}
@@ -161,4 +175,271 @@ func (s *PackControllerTestSuite) TestUpdatePackSizes_Success() {
 
 	// Verify the response contents
 	s.Assert().Equal("Pack sizes updated successfully", response["message"], "Message should match")
+	s.Assert().Equal(float64(2), response["version"].(map[string]interface{})["id"], "Version should be returned")
+}
+
+// TestCalculatePacks_PinnedVersion tests calculating against a pinned pack-size version
+func (s *PackControllerTestSuite) TestCalculatePacks_PinnedVersion() {
+	s.mockService.EXPECT().ExecuteVersion(263, 1).Return(map[int]int{250: 2}, 500, nil)
+
+	body, _ := json.Marshal(map[string]int{"orderAmount": 263, "packSizeVersion": 1})
+	req := httptest.NewRequest("POST", "/api/calculate", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	s.Assert().NoError(err, "Expected no error decoding response")
+	s.Assert().Equal(float64(1), response["packSizeVersion"], "Pinned version should be echoed")
+	s.Assert().Equal(map[string]interface{}{"250": float64(2)}, response["packs"], "Packs should match")
+}
+
+// TestGetPackSizeHistory_Success tests listing pack-size versions
+func (s *PackControllerTestSuite) TestGetPackSizeHistory_Success() {
+	s.mockService.EXPECT().ListPackSizeVersions().Return([]domain.PackSizeVersion{
+		{ID: 1, PackSizes: []int{250, 500}, Author: "config"},
+		{ID: 2, PackSizes: []int{100}, Author: "alice"},
+	}, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/pack-sizes/history", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var response struct {
+		Versions []domain.PackSizeVersion `json:"versions"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	s.Assert().NoError(err, "Expected no error decoding response")
+	s.Require().Len(response.Versions, 2, "Expected two versions")
+	s.Assert().Equal("alice", response.Versions[1].Author, "Author should match")
+}
+
+// TestGetPackSizeVersion_NotFound tests looking up a version that does not exist
+func (s *PackControllerTestSuite) TestGetPackSizeVersion_NotFound() {
+	s.mockService.EXPECT().GetPackSizeVersion(7).Return(domain.PackSizeVersion{}, repository.ErrVersionNotFound)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/pack-sizes/versions/7", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusNotFound, resp.StatusCode, "Expected status NotFound")
+}
+
+// TestRollbackPackSizes_Success tests restoring an earlier pack-size version
+func (s *PackControllerTestSuite) TestRollbackPackSizes_Success() {
+	s.mockService.EXPECT().RollbackPackSizesIfLatest(1, "bob", 2).
+		Return(domain.PackSizeVersion{ID: 3, PackSizes: []int{250, 500}, Author: "bob", RollbackOf: 1}, nil)
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes/versions/1/rollback", nil)
+	req.Header.Set(ActorHeader, "bob")
+	req.Header.Set(fiber.HeaderIfMatch, `"2"`)
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	s.Assert().Equal(`"3"`, resp.Header.Get(fiber.HeaderETag), "ETag should name the new version")
+
+	var response struct {
+		Version domain.PackSizeVersion `json:"version"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	s.Assert().NoError(err, "Expected no error decoding response")
+	s.Assert().Equal(1, response.Version.RollbackOf, "Rollback source should be recorded")
+}
+
+// TestRollbackPackSizes_InvalidID tests rolling back with a malformed version ID
+func (s *PackControllerTestSuite) TestRollbackPackSizes_InvalidID() {
+	req := httptest.NewRequest("POST", "/api/pack-sizes/versions/abc/rollback", nil)
+	req.Header.Set(fiber.HeaderIfMatch, "*")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status BadRequest")
+}
+
+// TestRollbackPackSizes_AnyVersion tests that If-Match: * rolls back whatever the current version is
+func (s *PackControllerTestSuite) TestRollbackPackSizes_AnyVersion() {
+	s.mockService.EXPECT().RollbackPackSizes(1, "bob").Return(domain.PackSizeVersion{ID: 3, RollbackOf: 1}, nil)
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes/versions/1/rollback", nil)
+	req.Header.Set(ActorHeader, "bob")
+	req.Header.Set(fiber.HeaderIfMatch, "*")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+}
+
+// TestRollbackPackSizes_IfMatchRequired tests that rollbacks without If-Match are refused
+func (s *PackControllerTestSuite) TestRollbackPackSizes_IfMatchRequired() {
+	// No service call is expected without a precondition
+	resp, err := s.app.Test(httptest.NewRequest("POST", "/api/pack-sizes/versions/1/rollback", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusPreconditionRequired, resp.StatusCode, "Expected status PreconditionRequired")
+}
+
+// TestRollbackPackSizes_Conflict tests that a rollback based on an outdated version returns 412 with the current ETag
+func (s *PackControllerTestSuite) TestRollbackPackSizes_Conflict() {
+	s.mockService.EXPECT().RollbackPackSizesIfLatest(1, "bob", 1).
+		Return(domain.PackSizeVersion{}, repository.ErrVersionConflict)
+	s.mockService.EXPECT().LatestPackSizeVersion().Return(2, nil)
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes/versions/1/rollback", nil)
+	req.Header.Set(ActorHeader, "bob")
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusPreconditionFailed, resp.StatusCode, "Expected status PreconditionFailed")
+	s.Assert().Equal(`"2"`, resp.Header.Get(fiber.HeaderETag), "ETag should name the version to reload")
+}
+
+// TestCalculatePacks_BackDated tests calculating with the pack sizes in effect at an earlier time
+func (s *PackControllerTestSuite) TestCalculatePacks_BackDated() {
+	at := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	s.mockService.EXPECT().ExecuteAt(263, at).Return(map[int]int{300: 1}, 300, nil)
+
+	req := httptest.NewRequest("POST", "/api/calculate", bytes.NewBufferString(`{"orderAmount":263,"at":"2025-01-15T09:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+}
+
+// TestCalculatePacks_VersionAndTime tests that a pinned version and a time cannot be combined
+func (s *PackControllerTestSuite) TestCalculatePacks_VersionAndTime() {
+	req := httptest.NewRequest("POST", "/api/calculate", bytes.NewBufferString(`{"orderAmount":263,"packSizeVersion":1,"at":"2025-01-15T09:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status BadRequest")
+}
+
+// TestUpdatePackSizes_ValidationError tests that invalid sets return 422 with field errors
+func (s *PackControllerTestSuite) TestUpdatePackSizes_ValidationError() {
+	s.mockService.EXPECT().UpdatePackSizes([]int{250, 0}, "anonymous").
+		Return(domain.PackSizeVersion{}, &service.ValidationError{Fields: []service.FieldError{{Field: "packSizes[1]", Message: "must be positive"}}})
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[250,0]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderIfMatch, "*") // Change whatever is current
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusUnprocessableEntity, resp.StatusCode, "Expected status UnprocessableEntity")
+
+	var response struct {
+		Error  string               `json:"error"`
+		Fields []service.FieldError `json:"fields"`
+	}
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&response), "Expected no error decoding response")
+	s.Assert().Equal("Validation failed", response.Error, "Error should match")
+	s.Assert().Equal([]service.FieldError{{Field: "packSizes[1]", Message: "must be positive"}}, response.Fields, "Field errors should be returned")
+}
+
+// TestUpdatePackSizes_Scheduled tests scheduling a pack-size change for a later date
+func (s *PackControllerTestSuite) TestUpdatePackSizes_Scheduled() {
+	effectiveFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.mockService.EXPECT().SchedulePackSizes([]int{100}, "alice", effectiveFrom).
+		Return(domain.PackSizeVersion{ID: 2, PackSizes: []int{100}, Author: "alice", EffectiveFrom: effectiveFrom}, nil)
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100],"effectiveFrom":"2030-01-01T00:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderIfMatch, "*") // Change whatever is current
+	req.Header.Set(ActorHeader, "alice")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+}
+
+// TestUpdatePackSizes_ScheduledInPast tests that changes cannot be scheduled in the past
+func (s *PackControllerTestSuite) TestUpdatePackSizes_ScheduledInPast() {
+	effectiveFrom := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.mockService.EXPECT().SchedulePackSizes([]int{100}, "anonymous", effectiveFrom).
+		Return(domain.PackSizeVersion{}, service.ErrEffectiveFromInPast)
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100],"effectiveFrom":"2020-01-01T00:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderIfMatch, "*") // Change whatever is current
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status BadRequest")
+}
+
+// TestGetScheduledPackSizes_Success tests listing upcoming pack-size changes
+func (s *PackControllerTestSuite) TestGetScheduledPackSizes_Success() {
+	s.mockService.EXPECT().ListScheduledPackSizes().Return([]domain.PackSizeVersion{{ID: 2, PackSizes: []int{100}}}, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/pack-sizes/scheduled", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var response struct {
+		Versions []domain.PackSizeVersion `json:"versions"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	s.Assert().NoError(err, "Expected no error decoding response")
+	s.Require().Len(response.Versions, 1, "Expected one scheduled version")
+}
+
+// TestUpdatePackSizes_ApprovalRequired tests that direct changes are refused when approval is required
+func (s *PackControllerTestSuite) TestUpdatePackSizes_ApprovalRequired() {
+	s.mockService.EXPECT().UpdatePackSizes([]int{100}, "alice").Return(domain.PackSizeVersion{}, service.ErrApprovalRequired)
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderIfMatch, "*") // Change whatever is current
+	req.Header.Set(ActorHeader, "alice")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusForbidden, resp.StatusCode, "Expected status Forbidden")
+}
+
+// TestUpdatePackSizes_IfMatchRequired tests that changes without If-Match are refused
+func (s *PackControllerTestSuite) TestUpdatePackSizes_IfMatchRequired() {
+	// No service call is expected without a precondition
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusPreconditionRequired, resp.StatusCode, "Expected status PreconditionRequired")
+}
+
+// TestUpdatePackSizes_Conflict tests that a change based on an outdated version returns 412 with the current ETag
+func (s *PackControllerTestSuite) TestUpdatePackSizes_Conflict() {
+	s.mockService.EXPECT().SchedulePackSizesIfLatest([]int{100}, "bob", time.Time{}, 1).
+		Return(domain.PackSizeVersion{}, repository.ErrVersionConflict)
+	s.mockService.EXPECT().LatestPackSizeVersion().Return(2, nil)
+
+	req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, "bob")
+	req.Header.Set(fiber.HeaderIfMatch, `"1"`)
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusPreconditionFailed, resp.StatusCode, "Expected status PreconditionFailed")
+	s.Assert().Equal(`"2"`, resp.Header.Get(fiber.HeaderETag), "ETag should name the version to reload")
+}
+
+// TestUpdatePackSizes_InvalidIfMatch tests that weak and malformed tags never match
+func (s *PackControllerTestSuite) TestUpdatePackSizes_InvalidIfMatch() {
+	for _, tag := range []string{`W/"1"`, `1`, `"abc"`, `"0"`} {
+		s.mockService.EXPECT().LatestPackSizeVersion().Return(1, nil)
+
+		req := httptest.NewRequest("POST", "/api/pack-sizes", bytes.NewBufferString(`{"packSizes":[100]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(fiber.HeaderIfMatch, tag)
+
+		resp, err := s.app.Test(req)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(fiber.StatusPreconditionFailed, resp.StatusCode, "Expected status PreconditionFailed for %s", tag)
+	}
 }
@@ -0,0 +1,211 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"errors" // Import errors for matching service errors
+	"time"   // Import time for effective dates
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/domain"                    // Import the domain package for proposal states
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
+)
+
+// ProposalController handles HTTP requests for the pack-size approval workflow
+type ProposalController struct {
+	approvals service.PackSizeApprovalService // Service running the draft -> review -> published lifecycle
+	logger    *logging.Logger                 // Logger instance for logging requests and errors
+}
+
+// NewProposalController creates a new instance of ProposalController
+func NewProposalController(approvals service.PackSizeApprovalService, logger *logging.Logger) *ProposalController {
+	return &ProposalController{
+		approvals: approvals, // Initialize the service
+		logger:    logger,    // Initialize the logger
+	}
+}
+
+// CreateProposal handles the POST /api/pack-size-proposals endpoint to create a draft
+func (c *ProposalController) CreateProposal(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to create pack size proposal") // Log the incoming request
+
+	var request struct { // Define a struct to parse the JSON request body
+		PackSizes     []int      `json:"packSizes"`     // Proposed pack sizes
+		EffectiveFrom *time.Time `json:"effectiveFrom"` // Optional time from which the sizes should apply
+	}
+	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
+		c.logger.Error("Failed to parse request body", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	proposal, err := c.approvalsFor(ctx).CreateProposal(request.PackSizes, request.EffectiveFrom, actor(ctx))
+	if err != nil {
+		c.logger.Error("Failed to create pack size proposal", err) // Log the error
+		return ctx.Status(proposalStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully created pack size proposal") // Log the successful creation
+	return ctx.Status(fiber.StatusCreated).JSON(proposal)
+}
+
+// ListProposals handles the GET /api/pack-size-proposals endpoint, optionally filtered by ?status=
+func (c *ProposalController) ListProposals(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to list pack size proposals") // Log the incoming request
+
+	proposals, err := c.approvalsFor(ctx).ListProposals(domain.ProposalStatus(ctx.Query("status")))
+	if err != nil {
+		c.logger.Error("Failed to list pack size proposals", err) // Log the error
+		return ctx.Status(proposalStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully listed pack size proposals") // Log the successful retrieval
+	return ctx.JSON(fiber.Map{"proposals": proposals})
+}
+
+// GetProposal handles the GET /api/pack-size-proposals/:id endpoint
+func (c *ProposalController) GetProposal(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get pack size proposal") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the proposal ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid proposal ID"})
+	}
+
+	proposal, err := c.approvalsFor(ctx).GetProposal(id)
+	if err != nil {
+		c.logger.Error("Failed to get pack size proposal", err) // Log the error
+		return ctx.Status(proposalStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully retrieved pack size proposal") // Log the successful retrieval
+	return ctx.JSON(proposal)
+}
+
+// SubmitProposal handles the POST /api/pack-size-proposals/:id/submit endpoint to send a draft for review
+func (c *ProposalController) SubmitProposal(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to submit pack size proposal") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the proposal ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid proposal ID"})
+	}
+
+	proposal, err := c.approvalsFor(ctx).SubmitProposal(id)
+	if err != nil {
+		c.logger.Error("Failed to submit pack size proposal", err) // Log the error
+		return ctx.Status(proposalStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully submitted pack size proposal") // Log the successful submission
+	return ctx.JSON(proposal)
+}
+
+// ApproveProposal handles the POST /api/pack-size-proposals/:id/approve endpoint to publish a proposal
+func (c *ProposalController) ApproveProposal(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to approve pack size proposal") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the proposal ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid proposal ID"})
+	}
+
+	proposal, err := c.approvalsFor(ctx).ApproveProposal(id, actor(ctx))
+	if err != nil {
+		c.logger.Error("Failed to approve pack size proposal", err) // Log the error
+		return ctx.Status(proposalStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully approved pack size proposal") // Log the successful approval
+	return ctx.JSON(proposal)
+}
+
+// RejectProposal handles the POST /api/pack-size-proposals/:id/reject endpoint to turn down a proposal
+func (c *ProposalController) RejectProposal(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to reject pack size proposal") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the proposal ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid proposal ID"})
+	}
+
+	var request struct { // Define a struct to parse the JSON request body
+		Reason string `json:"reason"` // Why the proposal is rejected
+	}
+	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
+		c.logger.Error("Failed to parse request body", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	proposal, err := c.approvalsFor(ctx).RejectProposal(id, actor(ctx), request.Reason)
+	if err != nil {
+		c.logger.Error("Failed to reject pack size proposal", err) // Log the error
+		return ctx.Status(proposalStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully rejected pack size proposal") // Log the successful rejection
+	return ctx.JSON(proposal)
+}
+
+// PreviewProposal handles the POST /api/pack-size-proposals/:id/preview endpoint to compare calculations
+func (c *ProposalController) PreviewProposal(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to preview pack size proposal") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the proposal ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid proposal ID"})
+	}
+
+	var request struct { // Define a struct to parse the JSON request body
+		OrderAmounts []int `json:"orderAmounts"` // Order amounts to calculate with both sets of pack sizes
+	}
+	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
+		c.logger.Error("Failed to parse request body", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	previews, err := c.approvalsFor(ctx).PreviewProposal(id, request.OrderAmounts)
+	if err != nil {
+		c.logger.Error("Failed to preview pack size proposal", err) // Log the error
+		return ctx.Status(proposalStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully previewed pack size proposal") // Log the successful preview
+	return ctx.JSON(fiber.Map{"previews": previews})
+}
+
+// proposalStatusForError maps approval workflow errors to HTTP status codes
+func proposalStatusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrProposalNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidProposalTransition),
+		errors.Is(err, service.ErrProposalExpired),
+		errors.Is(err, service.ErrProposalSuperseded):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrSelfApproval):
+		return fiber.StatusForbidden
+	case errors.Is(err, service.ErrRejectionReasonRequired),
+		errors.Is(err, service.ErrNoOrderAmounts),
+		errors.Is(err, service.ErrEffectiveFromInPast):
+		return fiber.StatusBadRequest
+	default:
+		return statusForError(err)
+	}
+}
+
+// approvalsFor returns the approval service of the request's tenant, falling back to the controller's own
+func (c *ProposalController) approvalsFor(ctx *fiber.Ctx) service.PackSizeApprovalService {
+	if services, ok := tenantServices(ctx); ok { // Tenancy middleware selected the tenant's services
+		return services.Approvals
+	}
+	return c.approvals
+}
+
+// ProposalSnapshot returns the proposal named in the path, for auditing changes to it
+func (c *ProposalController) ProposalSnapshot(ctx *fiber.Ctx) (interface{}, error) {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return nil, err
+	}
+	return c.approvalsFor(ctx).GetProposal(id)
+}
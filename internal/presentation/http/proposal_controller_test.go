@@ -0,0 +1,160 @@
+package http
+
+import (
+	"bytes"             // Import bytes for creating request bodies
+	"encoding/json"     // Import json for encoding/decoding
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for proposals
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for its errors
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// ProposalControllerTestSuite defines the test suite for the approval workflow handlers
+type ProposalControllerTestSuite struct {
+	suite.Suite                                    // Embed the testify suite
+	app         *fiber.App                         // Fiber app for testing
+	mockService *mocks.MockPackSizeApprovalService // Use gomock-generated mock type
+	ctrl        *gomock.Controller                 // Gomock controller for managing mocks
+}
+
+// SetupTest sets up the test environment before each test
+func (s *ProposalControllerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockPackSizeApprovalService(s.ctrl)
+	controller := NewProposalController(s.mockService, logging.NewLogger())
+
+	s.app = fiber.New()
+	api := s.app.Group("/api")
+	api.Post("/pack-size-proposals", controller.CreateProposal)
+	api.Get("/pack-size-proposals", controller.ListProposals)
+	api.Get("/pack-size-proposals/:id", controller.GetProposal)
+	api.Post("/pack-size-proposals/:id/submit", controller.SubmitProposal)
+	api.Post("/pack-size-proposals/:id/approve", controller.ApproveProposal)
+	api.Post("/pack-size-proposals/:id/reject", controller.RejectProposal)
+	api.Post("/pack-size-proposals/:id/preview", controller.PreviewProposal)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *ProposalControllerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestProposalControllerTestSuite runs the test suite
+func TestProposalControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ProposalControllerTestSuite))
+}
+
+// post sends a JSON POST request as the given user
+func (s *ProposalControllerTestSuite) post(path string, body string, user string) int {
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, user)
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	return resp.StatusCode
+}
+
+// TestCreateProposal_Success tests creating a draft
+func (s *ProposalControllerTestSuite) TestCreateProposal_Success() {
+	s.mockService.EXPECT().CreateProposal([]int{100, 200}, nil, "alice").
+		Return(domain.PackSizeProposal{ID: 1, PackSizes: []int{100, 200}, Author: "alice", Status: domain.ProposalDraft}, nil)
+
+	status := s.post("/api/pack-size-proposals", `{"packSizes":[100,200]}`, "alice")
+	s.Assert().Equal(fiber.StatusCreated, status, "Expected status Created")
+}
+
+// TestListProposals_FilterByStatus tests passing the status filter through
+func (s *ProposalControllerTestSuite) TestListProposals_FilterByStatus() {
+	s.mockService.EXPECT().ListProposals(domain.ProposalInReview).Return([]domain.PackSizeProposal{{ID: 2}}, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/pack-size-proposals?status=in_review", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var response struct {
+		Proposals []domain.PackSizeProposal `json:"proposals"`
+	}
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&response), "Expected no error decoding response")
+	s.Assert().Len(response.Proposals, 1, "Expected one proposal")
+}
+
+// TestGetProposal_NotFound tests looking up a proposal that does not exist
+func (s *ProposalControllerTestSuite) TestGetProposal_NotFound() {
+	s.mockService.EXPECT().GetProposal(9).Return(domain.PackSizeProposal{}, repository.ErrProposalNotFound)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/pack-size-proposals/9", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusNotFound, resp.StatusCode, "Expected status NotFound")
+}
+
+// TestSubmitProposal_InvalidTransition tests submitting a proposal that is not a draft
+func (s *ProposalControllerTestSuite) TestSubmitProposal_InvalidTransition() {
+	s.mockService.EXPECT().SubmitProposal(1).Return(domain.PackSizeProposal{}, service.ErrInvalidProposalTransition)
+
+	status := s.post("/api/pack-size-proposals/1/submit", `{}`, "alice")
+	s.Assert().Equal(fiber.StatusConflict, status, "Expected status Conflict")
+}
+
+// TestApproveProposal_Success tests approving as the reviewer
+func (s *ProposalControllerTestSuite) TestApproveProposal_Success() {
+	s.mockService.EXPECT().ApproveProposal(1, "bob").
+		Return(domain.PackSizeProposal{ID: 1, Status: domain.ProposalPublished, ReviewedBy: "bob", PublishedVersion: 3}, nil)
+
+	status := s.post("/api/pack-size-proposals/1/approve", `{}`, "bob")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+}
+
+// TestApproveProposal_SelfApproval tests that authors cannot approve their own proposals
+func (s *ProposalControllerTestSuite) TestApproveProposal_SelfApproval() {
+	s.mockService.EXPECT().ApproveProposal(1, "alice").Return(domain.PackSizeProposal{}, service.ErrSelfApproval)
+
+	status := s.post("/api/pack-size-proposals/1/approve", `{}`, "alice")
+	s.Assert().Equal(fiber.StatusForbidden, status, "Expected status Forbidden")
+}
+
+// TestApproveProposal_Expired tests that approving a proposal that can no longer be published is a conflict
+func (s *ProposalControllerTestSuite) TestApproveProposal_Expired() {
+	s.mockService.EXPECT().ApproveProposal(1, "bob").Return(domain.PackSizeProposal{}, service.ErrProposalExpired)
+
+	status := s.post("/api/pack-size-proposals/1/approve", `{}`, "bob")
+	s.Assert().Equal(fiber.StatusConflict, status, "Expected status Conflict")
+}
+
+// TestRejectProposal_Success tests rejecting with a reason
+func (s *ProposalControllerTestSuite) TestRejectProposal_Success() {
+	s.mockService.EXPECT().RejectProposal(1, "bob", "Too many sizes").
+		Return(domain.PackSizeProposal{ID: 1, Status: domain.ProposalRejected, RejectionReason: "Too many sizes"}, nil)
+
+	status := s.post("/api/pack-size-proposals/1/reject", `{"reason":"Too many sizes"}`, "bob")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+}
+
+// TestPreviewProposal_Success tests previewing calculations for a proposal
+func (s *ProposalControllerTestSuite) TestPreviewProposal_Success() {
+	s.mockService.EXPECT().PreviewProposal(1, []int{263}).Return([]service.ProposalPreview{{
+		OrderAmount: 263,
+		Current:     service.PackCalculation{Packs: map[int]int{500: 1}, TotalItems: 500},
+		Proposed:    service.PackCalculation{Packs: map[int]int{300: 1}, TotalItems: 300},
+	}}, nil)
+
+	req := httptest.NewRequest("POST", "/api/pack-size-proposals/1/preview", bytes.NewBufferString(`{"orderAmounts":[263]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var response struct {
+		Previews []service.ProposalPreview `json:"previews"`
+	}
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&response), "Expected no error decoding response")
+	s.Require().Len(response.Previews, 1, "Expected one preview")
+	s.Assert().Equal(300, response.Previews[0].Proposed.TotalItems, "Proposed total should match")
+}
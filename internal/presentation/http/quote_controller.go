@@ -0,0 +1,80 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"errors" // Import errors for matching service errors
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
+)
+
+// QuoteController handles HTTP requests for customer quotes
+type QuoteController struct {
+	quotes service.QuoteService // Service creating and retrieving quotes
+	logger *logging.Logger      // Logger instance for logging requests and errors
+}
+
+// NewQuoteController creates a new instance of QuoteController
+func NewQuoteController(quotes service.QuoteService, logger *logging.Logger) *QuoteController {
+	return &QuoteController{
+		quotes: quotes, // Initialize the service
+		logger: logger, // Initialize the logger
+	}
+}
+
+// CreateQuote handles the POST /api/quotes endpoint to quote an order
+func (c *QuoteController) CreateQuote(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to create quote") // Log the incoming request
+
+	var request struct { // Define a struct to parse the JSON request body
+		OrderAmount int `json:"orderAmount"` // Order amount to quote
+	}
+	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
+		c.logger.Error("Failed to parse request body", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	quote, err := c.quotesFor(ctx).CreateQuote(request.OrderAmount, actor(ctx))
+	if err != nil {
+		c.logger.Error("Failed to create quote", err) // Log the error
+		return ctx.Status(quoteStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully created quote") // Log the successful creation
+	return ctx.Status(fiber.StatusCreated).JSON(quote)
+}
+
+// GetQuote handles the GET /api/quotes/:id endpoint to retrieve a quote that has not expired
+func (c *QuoteController) GetQuote(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get quote") // Log the incoming request
+
+	quote, err := c.quotesFor(ctx).GetQuote(ctx.Params("id"))
+	if err != nil {
+		c.logger.Error("Failed to get quote", err) // Log the error
+		return ctx.Status(quoteStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully retrieved quote") // Log the successful retrieval
+	return ctx.JSON(quote)
+}
+
+// quoteStatusForError maps quote errors to HTTP status codes
+func quoteStatusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrQuoteNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrQuoteExpired):
+		return fiber.StatusGone
+	default:
+		return statusForError(err)
+	}
+}
+
+// quotesFor returns the quote service of the request's tenant, falling back to the controller's own
+func (c *QuoteController) quotesFor(ctx *fiber.Ctx) service.QuoteService {
+	if services, ok := tenantServices(ctx); ok { // Tenancy middleware selected the tenant's services
+		return services.Quotes
+	}
+	return c.quotes
+}
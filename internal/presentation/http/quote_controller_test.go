@@ -0,0 +1,80 @@
+package http
+
+import (
+	"bytes"             // Import bytes for creating request bodies
+	"encoding/json"     // Import json for encoding/decoding
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for quotes
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for its errors
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// QuoteControllerTestSuite defines the test suite for the quote handlers
+type QuoteControllerTestSuite struct {
+	suite.Suite                         // Embed the testify suite
+	app         *fiber.App              // Fiber app for testing
+	mockService *mocks.MockQuoteService // Use gomock-generated mock type
+	ctrl        *gomock.Controller      // Gomock controller for managing mocks
+}
+
+// SetupTest sets up the test environment before each test
+func (s *QuoteControllerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockQuoteService(s.ctrl)
+	controller := NewQuoteController(s.mockService, logging.NewLogger())
+
+	s.app = fiber.New()
+	api := s.app.Group("/api")
+	api.Post("/quotes", controller.CreateQuote)
+	api.Get("/quotes/:id", controller.GetQuote)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *QuoteControllerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestQuoteControllerTestSuite runs the test suite
+func TestQuoteControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(QuoteControllerTestSuite))
+}
+
+// TestCreateQuote_Success tests quoting an order
+func (s *QuoteControllerTestSuite) TestCreateQuote_Success() {
+	s.mockService.EXPECT().CreateQuote(263, "alice").
+		Return(domain.Quote{ID: "abc", OrderAmount: 263, PackSizeVersion: 2, Packs: map[int]int{500: 1}, TotalItems: 500}, nil)
+
+	req := httptest.NewRequest("POST", "/api/quotes", bytes.NewBufferString(`{"orderAmount":263}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, "alice")
+
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusCreated, resp.StatusCode, "Expected status Created")
+
+	var quote domain.Quote
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&quote), "Expected no error decoding response")
+	s.Assert().Equal("abc", quote.ID, "Quote ID should match")
+	s.Assert().Equal(2, quote.PackSizeVersion, "Pack-size version should match")
+}
+
+// TestGetQuote_Errors tests the status codes for missing and expired quotes
+func (s *QuoteControllerTestSuite) TestGetQuote_Errors() {
+	s.mockService.EXPECT().GetQuote("missing").Return(domain.Quote{}, repository.ErrQuoteNotFound)
+	s.mockService.EXPECT().GetQuote("old").Return(domain.Quote{}, service.ErrQuoteExpired)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/quotes/missing", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusNotFound, resp.StatusCode, "Expected status NotFound")
+
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/api/quotes/old", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusGone, resp.StatusCode, "Expected status Gone")
+}
@@ -0,0 +1,175 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"errors" // Import errors for matching service errors
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/domain"                    // Import the domain package for reservation statuses
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
+)
+
+// ReservationController handles HTTP requests for stock levels and reservations
+type ReservationController struct {
+	reservations service.ReservationService // Service reserving packs from stock
+	logger       *logging.Logger            // Logger instance for logging requests and errors
+}
+
+// NewReservationController creates a new instance of ReservationController
+func NewReservationController(reservations service.ReservationService, logger *logging.Logger) *ReservationController {
+	return &ReservationController{
+		reservations: reservations, // Initialize the service
+		logger:       logger,       // Initialize the logger
+	}
+}
+
+// GetStock handles the GET /api/inventory endpoint to retrieve the packs in stock per size
+func (c *ReservationController) GetStock(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get stock") // Log the incoming request
+
+	stock, err := c.reservationsFor(ctx).GetStock()
+	if err != nil {
+		c.logger.Error("Failed to get stock", err) // Log the error
+		return ctx.Status(reservationStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully retrieved stock") // Log the successful retrieval
+	return ctx.JSON(fiber.Map{"stock": stock})
+}
+
+// SetStock handles the POST /api/inventory endpoint to set the stock level of one or more sizes
+func (c *ReservationController) SetStock(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to set stock") // Log the incoming request
+
+	var request struct { // Define a struct to parse the JSON request body
+		Stock map[int]int `json:"stock"` // Pack size -> packs in stock
+	}
+	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
+		c.logger.Error("Failed to parse request body", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	stock, err := c.reservationsFor(ctx).SetStock(request.Stock)
+	if err != nil {
+		c.logger.Error("Failed to set stock", err) // Log the error
+		return ctx.Status(reservationStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully set stock") // Log the successful update
+	return ctx.JSON(fiber.Map{"stock": stock})
+}
+
+// Reserve handles the POST /api/reservations endpoint to reserve the packs of an order
+func (c *ReservationController) Reserve(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to reserve packs") // Log the incoming request
+
+	var request struct { // Define a struct to parse the JSON request body
+		OrderAmount int `json:"orderAmount"` // Order amount to reserve packs for
+	}
+	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
+		c.logger.Error("Failed to parse request body", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	reservation, err := c.reservationsFor(ctx).Reserve(request.OrderAmount, actor(ctx))
+	if err != nil {
+		c.logger.Error("Failed to reserve packs", err) // Log the error
+		return ctx.Status(reservationStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully reserved packs") // Log the successful reservation
+	return ctx.Status(fiber.StatusCreated).JSON(reservation)
+}
+
+// ListReservations handles the GET /api/reservations endpoint; it supports the status query parameter
+func (c *ReservationController) ListReservations(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to list reservations") // Log the incoming request
+
+	reservations, err := c.reservationsFor(ctx).ListReservations(domain.ReservationStatus(ctx.Query("status")))
+	if err != nil {
+		c.logger.Error("Failed to list reservations", err) // Log the error
+		return ctx.Status(reservationStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully listed reservations") // Log the successful retrieval
+	return ctx.JSON(fiber.Map{"reservations": reservations})
+}
+
+// GetReservation handles the GET /api/reservations/:id endpoint
+func (c *ReservationController) GetReservation(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to get reservation") // Log the incoming request
+
+	reservation, err := c.reservationsFor(ctx).GetReservation(ctx.Params("id"))
+	if err != nil {
+		c.logger.Error("Failed to get reservation", err) // Log the error
+		return ctx.Status(reservationStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully retrieved reservation") // Log the successful retrieval
+	return ctx.JSON(reservation)
+}
+
+// ConfirmReservation handles the POST /api/reservations/:id/confirm endpoint to allocate the held packs to the order
+func (c *ReservationController) ConfirmReservation(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to confirm reservation") // Log the incoming request
+
+	reservation, err := c.reservationsFor(ctx).ConfirmReservation(ctx.Params("id"), actor(ctx))
+	if err != nil {
+		c.logger.Error("Failed to confirm reservation", err) // Log the error
+		return ctx.Status(reservationStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully confirmed reservation") // Log the successful confirmation
+	return ctx.JSON(reservation)
+}
+
+// CancelReservation handles the POST /api/reservations/:id/cancel endpoint to return the held packs to stock
+func (c *ReservationController) CancelReservation(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to cancel reservation") // Log the incoming request
+
+	reservation, err := c.reservationsFor(ctx).CancelReservation(ctx.Params("id"), actor(ctx))
+	if err != nil {
+		c.logger.Error("Failed to cancel reservation", err) // Log the error
+		return ctx.Status(reservationStatusForError(err)).JSON(errorBody(err))
+	}
+
+	c.logger.Info("Successfully cancelled reservation") // Log the successful cancellation
+	return ctx.JSON(reservation)
+}
+
+// StockSnapshot returns the packs currently in stock, for auditing changes to them
+func (c *ReservationController) StockSnapshot(ctx *fiber.Ctx) (interface{}, error) {
+	stock, err := c.reservationsFor(ctx).GetStock()
+	if err != nil {
+		return nil, err
+	}
+	return fiber.Map{"stock": stock}, nil
+}
+
+// ReservationSnapshot returns the reservation named in the path, for auditing its confirmation or cancellation
+func (c *ReservationController) ReservationSnapshot(ctx *fiber.Ctx) (interface{}, error) {
+	return c.reservationsFor(ctx).GetReservation(ctx.Params("id"))
+}
+
+// reservationStatusForError maps reservation errors to HTTP status codes
+func reservationStatusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrReservationNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, repository.ErrInsufficientStock), errors.Is(err, repository.ErrReservationNotHeld):
+		return fiber.StatusConflict
+	case errors.Is(err, service.ErrReservationExpired):
+		return fiber.StatusGone
+	default:
+		return statusForError(err)
+	}
+}
+
+// reservationsFor returns the reservation service of the request's tenant, falling back to the controller's own
+func (c *ReservationController) reservationsFor(ctx *fiber.Ctx) service.ReservationService {
+	if services, ok := tenantServices(ctx); ok { // Tenancy middleware selected the tenant's services
+		return services.Reservations
+	}
+	return c.reservations
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bytes"             // Import bytes for creating request bodies
+	"encoding/json"     // Import json for encoding/decoding
+	"fmt"               // Import fmt for wrapping errors
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for reservations
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for its errors
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// ReservationControllerTestSuite defines the test suite for the inventory and reservation handlers
+type ReservationControllerTestSuite struct {
+	suite.Suite                               // Embed the testify suite
+	app         *fiber.App                    // Fiber app for testing
+	mockService *mocks.MockReservationService // Use gomock-generated mock type
+	ctrl        *gomock.Controller            // Gomock controller for managing mocks
+}
+
+// SetupTest sets up the test environment before each test
+func (s *ReservationControllerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockReservationService(s.ctrl)
+	controller := NewReservationController(s.mockService, logging.NewLogger())
+
+	s.app = fiber.New()
+	api := s.app.Group("/api")
+	api.Get("/inventory", controller.GetStock)
+	api.Post("/inventory", controller.SetStock)
+	api.Post("/reservations", controller.Reserve)
+	api.Get("/reservations", controller.ListReservations)
+	api.Get("/reservations/:id", controller.GetReservation)
+	api.Post("/reservations/:id/confirm", controller.ConfirmReservation)
+	api.Post("/reservations/:id/cancel", controller.CancelReservation)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *ReservationControllerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestReservationControllerTestSuite runs the test suite
+func TestReservationControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReservationControllerTestSuite))
+}
+
+// post sends a JSON POST request to the test app
+func (s *ReservationControllerTestSuite) post(path, body string) int {
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, "alice")
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	return resp.StatusCode
+}
+
+// TestSetStock tests setting stock levels and reporting invalid ones per size
+func (s *ReservationControllerTestSuite) TestSetStock() {
+	s.mockService.EXPECT().SetStock(map[int]int{250: 10}).Return(map[int]int{250: 10, 500: 3}, nil)
+	s.mockService.EXPECT().SetStock(map[int]int{250: -1}).
+		Return(nil, &service.ValidationError{Fields: []service.FieldError{{Field: "stock[250]", Message: "must not be negative"}}})
+
+	s.Assert().Equal(fiber.StatusOK, s.post("/api/inventory", `{"stock":{"250":10}}`), "Expected status OK")
+	s.Assert().Equal(fiber.StatusUnprocessableEntity, s.post("/api/inventory", `{"stock":{"250":-1}}`), "Expected status UnprocessableEntity")
+	s.Assert().Equal(fiber.StatusBadRequest, s.post("/api/inventory", `{"stock":`), "Expected status BadRequest")
+}
+
+// TestReserve tests reserving packs and running out of stock
+func (s *ReservationControllerTestSuite) TestReserve() {
+	s.mockService.EXPECT().Reserve(263, "alice").
+		Return(domain.Reservation{ID: "abc", OrderAmount: 263, Packs: map[int]int{500: 1}, Status: domain.ReservationHeld}, nil)
+	s.mockService.EXPECT().Reserve(12001, "alice").
+		Return(domain.Reservation{}, fmt.Errorf("%w: pack size 5000 needs 2, 1 available", repository.ErrInsufficientStock))
+
+	req := httptest.NewRequest("POST", "/api/reservations", bytes.NewBufferString(`{"orderAmount":263}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, "alice")
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusCreated, resp.StatusCode, "Expected status Created")
+
+	var reservation domain.Reservation
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&reservation), "Expected no error decoding response")
+	s.Assert().Equal("abc", reservation.ID, "Reservation should be returned")
+
+	s.Assert().Equal(fiber.StatusConflict, s.post("/api/reservations", `{"orderAmount":12001}`), "Expected status Conflict")
+}
+
+// TestResolveReservation tests confirming and cancelling reservations in every state
+func (s *ReservationControllerTestSuite) TestResolveReservation() {
+	s.mockService.EXPECT().ConfirmReservation("a", "alice").Return(domain.Reservation{ID: "a", Status: domain.ReservationConfirmed}, nil)
+	s.mockService.EXPECT().ConfirmReservation("b", "alice").Return(domain.Reservation{}, service.ErrReservationExpired)
+	s.mockService.EXPECT().CancelReservation("a", "alice").Return(domain.Reservation{}, repository.ErrReservationNotHeld)
+	s.mockService.EXPECT().CancelReservation("c", "alice").Return(domain.Reservation{}, repository.ErrReservationNotFound)
+
+	s.Assert().Equal(fiber.StatusOK, s.post("/api/reservations/a/confirm", ""), "Expected status OK")
+	s.Assert().Equal(fiber.StatusGone, s.post("/api/reservations/b/confirm", ""), "Expected status Gone")
+	s.Assert().Equal(fiber.StatusConflict, s.post("/api/reservations/a/cancel", ""), "Expected status Conflict")
+	s.Assert().Equal(fiber.StatusNotFound, s.post("/api/reservations/c/cancel", ""), "Expected status NotFound")
+}
+
+// TestListReservations tests filtering reservations by status
+func (s *ReservationControllerTestSuite) TestListReservations() {
+	s.mockService.EXPECT().ListReservations(domain.ReservationHeld).Return([]domain.Reservation{{ID: "a"}}, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/reservations?status=held", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+}
@@ -0,0 +1,74 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"bytes"  // Import bytes for buffering archives
+	"errors" // Import errors for matching snapshot errors
+
+	"github.com/gofiber/fiber/v2"                            // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging" // Import the logging package for logging
+	"order-packs-calculator/internal/service"                // Import the service package for snapshots
+)
+
+// SnapshotController handles HTTP requests for backing up and restoring the server state
+type SnapshotController struct {
+	snapshots service.SnapshotService // Service taking and restoring snapshots of every tenant
+	logger    *logging.Logger         // Logger instance for logging requests and errors
+}
+
+// NewSnapshotController creates a new instance of SnapshotController
+func NewSnapshotController(snapshots service.SnapshotService, logger *logging.Logger) *SnapshotController {
+	return &SnapshotController{
+		snapshots: snapshots, // Initialize the service
+		logger:    logger,    // Initialize the logger
+	}
+}
+
+// GetSnapshot handles the GET /admin/snapshot endpoint; it returns an archive of the pack-size versions
+// and quotes of every tenant as gzip-compressed JSON, naming the state it leaves out in notIncluded
+func (c *SnapshotController) GetSnapshot(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to take a snapshot") // Log the incoming request
+
+	snapshot, err := c.snapshots.Snapshot()
+	if err != nil {
+		c.logger.Error("Failed to take a snapshot", err) // Log the error
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	// Buffer the archive so a failure can still be answered with an error status
+	var archive bytes.Buffer
+	if err := service.WriteSnapshot(&archive, snapshot); err != nil {
+		c.logger.Error("Failed to write the snapshot", err) // Log the error
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully took a snapshot") // Log the successful snapshot
+	name := "snapshot-" + snapshot.CreatedAt.Format("20060102T150405Z") + ".json.gz"
+	ctx.Set(fiber.HeaderContentType, "application/gzip")
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+name+`"`)
+	return ctx.Send(archive.Bytes())
+}
+
+// RestoreSnapshot handles the POST /admin/restore endpoint; the body is an archive from GET /admin/snapshot
+// of this or an earlier release. It replaces the state of every tenant in the archive and returns what
+// was restored per tenant, along with the state that was left as it is
+func (c *SnapshotController) RestoreSnapshot(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to restore a snapshot") // Log the incoming request
+
+	snapshot, err := service.ReadSnapshot(bytes.NewReader(ctx.Body()))
+	if err != nil {
+		c.logger.Error("Failed to read the snapshot", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	restored, err := c.snapshots.Restore(snapshot)
+	if err != nil {
+		c.logger.Error("Failed to restore the snapshot", err) // Log the error
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidSnapshot) { // The archive does not fit this instance; nothing was changed
+			status = fiber.StatusBadRequest
+		}
+		return ctx.Status(status).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully restored the snapshot") // Log the successful restore
+	return ctx.JSON(fiber.Map{"createdAt": snapshot.CreatedAt, "tenants": restored, "notIncluded": service.SnapshotNotIncluded()})
+}
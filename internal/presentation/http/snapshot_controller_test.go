@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bytes"             // Import bytes for archives
+	"encoding/json"     // Import json for decoding responses
+	"fmt"               // Import fmt for wrapping errors
+	"net/http/httptest" // Import httptest for HTTP testing
+	"strings"           // Import strings for request bodies
+	"testing"           // Import the testing package for writing unit tests
+	"time"              // Import time for the snapshot creation time
+
+	"github.com/gofiber/fiber/v2"                            // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                          // Import gomock for mocking
+	"github.com/stretchr/testify/assert"                     // Import assert for its sample error
+	"github.com/stretchr/testify/suite"                      // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                 // Import the domain package for versions
+	"order-packs-calculator/internal/infrastructure/logging" // Import logging package
+	"order-packs-calculator/internal/service"                // Import the service package for snapshot types
+	"order-packs-calculator/internal/service/mocks"          // Import mocks for the service
+)
+
+// SnapshotControllerTestSuite defines the test suite for the snapshot handlers
+type SnapshotControllerTestSuite struct {
+	suite.Suite                            // Embed the testify suite
+	app         *fiber.App                 // Fiber app for testing
+	mockService *mocks.MockSnapshotService // Use gomock-generated mock type
+	ctrl        *gomock.Controller         // Gomock controller for managing mocks
+	snapshot    service.Snapshot           // Snapshot the service returns
+}
+
+// SetupTest sets up the test environment before each test
+func (s *SnapshotControllerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockSnapshotService(s.ctrl)
+	controller := NewSnapshotController(s.mockService, logging.NewLogger())
+	s.snapshot = service.Snapshot{
+		Format:        service.SnapshotFormat,
+		FormatVersion: service.SnapshotFormatVersion,
+		CreatedAt:     time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Tenants: map[string]service.TenantSnapshot{
+			"default": {PackSizeVersions: []domain.PackSizeVersion{{ID: 1, PackSizes: []int{250}}}, Quotes: []domain.Quote{}},
+		},
+	}
+
+	s.app = fiber.New()
+	s.app.Get("/admin/snapshot", controller.GetSnapshot)
+	s.app.Post("/admin/restore", controller.RestoreSnapshot)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *SnapshotControllerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestSnapshotControllerTestSuite runs the test suite
+func TestSnapshotControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotControllerTestSuite))
+}
+
+// TestGetSnapshot tests that the snapshot is returned as a compressed archive
+func (s *SnapshotControllerTestSuite) TestGetSnapshot() {
+	s.mockService.EXPECT().Snapshot().Return(s.snapshot, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/admin/snapshot", nil))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	s.Assert().Equal("application/gzip", resp.Header.Get(fiber.HeaderContentType), "Expected an archive")
+	s.Assert().Contains(resp.Header.Get(fiber.HeaderContentDisposition), `filename="snapshot-20250601T120000Z.json.gz"`, "Archive should be named by its time")
+	read, err := service.ReadSnapshot(resp.Body)
+	s.Assert().NoError(err, "Expected no error reading the archive")
+	s.Assert().Equal(s.snapshot, read, "Archive should hold the snapshot")
+
+	s.mockService.EXPECT().Snapshot().Return(service.Snapshot{}, assert.AnError)
+	resp, err = s.app.Test(httptest.NewRequest("GET", "/admin/snapshot", nil))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusInternalServerError, resp.StatusCode, "Expected status Internal Server Error")
+}
+
+// TestRestoreSnapshot tests restoring an archive and reporting what was restored
+func (s *SnapshotControllerTestSuite) TestRestoreSnapshot() {
+	s.mockService.EXPECT().Restore(s.snapshot).Return(map[string]service.RestoredTenant{"default": {PackSizeVersions: 1}}, nil)
+	var archive bytes.Buffer
+	s.Require().NoError(service.WriteSnapshot(&archive, s.snapshot), "Expected no error")
+
+	resp, err := s.app.Test(httptest.NewRequest("POST", "/admin/restore", &archive))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+	var body struct {
+		CreatedAt   time.Time                         `json:"createdAt"`
+		Tenants     map[string]service.RestoredTenant `json:"tenants"`
+		NotIncluded []string                          `json:"notIncluded"`
+	}
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&body), "Expected no error decoding response")
+	s.Assert().Equal(s.snapshot.CreatedAt, body.CreatedAt, "Snapshot time should be returned")
+	s.Assert().Equal(map[string]service.RestoredTenant{"default": {PackSizeVersions: 1}}, body.Tenants, "Restored tenants should be returned")
+	s.Assert().Equal(service.SnapshotNotIncluded(), body.NotIncluded, "State left as it is should be named")
+}
+
+// TestRestoreRejected tests that unreadable, newer and unfitting archives are rejected with Bad Request
+func (s *SnapshotControllerTestSuite) TestRestoreRejected() {
+	for _, body := range []string{"not an archive", `{"format":"order-packs-calculator/snapshot","formatVersion":99}`} {
+		resp, err := s.app.Test(httptest.NewRequest("POST", "/admin/restore", strings.NewReader(body)))
+		s.Require().NoError(err, "Expected no error")
+		s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status Bad Request")
+	}
+
+	s.mockService.EXPECT().Restore(gomock.Any()).Return(nil, fmt.Errorf("%w: tenant %q is not configured", service.ErrInvalidSnapshot, "acme"))
+	resp, err := s.app.Test(httptest.NewRequest("POST", "/admin/restore", strings.NewReader(`{"format":"order-packs-calculator/snapshot","formatVersion":1}`)))
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Snapshots that do not fit should be rejected")
+}
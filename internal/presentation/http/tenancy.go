@@ -0,0 +1,81 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"errors" // Import errors for matching tenancy errors
+
+	"github.com/gofiber/fiber/v2"                            // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/infrastructure/logging" // Import the logging package for logging
+	"order-packs-calculator/internal/service"                // Import the service package for the tenant registry
+)
+
+const (
+	// TenantHeader is the request header naming the tenant whose catalogue is used
+	TenantHeader = "X-Tenant"
+	// APIKeyHeader is the request header carrying a tenant API key
+	APIKeyHeader = "X-API-Key"
+)
+
+// tenantLocal and tenantServicesLocal are the fiber.Ctx locals the resolved tenant is stored under
+const (
+	tenantLocal         = "tenant"
+	tenantServicesLocal = "tenantServices"
+)
+
+// Tenancy is a middleware that resolves the tenant of each request and selects its services
+type Tenancy struct {
+	registry *service.TenantRegistry // Registry of tenants and their services
+	logger   *logging.Logger         // Logger instance for logging rejected requests
+}
+
+// NewTenancy creates a new instance of Tenancy
+func NewTenancy(registry *service.TenantRegistry, logger *logging.Logger) *Tenancy {
+	return &Tenancy{
+		registry: registry, // Initialize the registry
+		logger:   logger,   // Initialize the logger
+	}
+}
+
+// Handle resolves the tenant from the X-Tenant and X-API-Key headers before passing the request on
+func (t *Tenancy) Handle(ctx *fiber.Ctx) error {
+	tenantID, err := t.registry.Resolve(ctx.Get(TenantHeader), ctx.Get(APIKeyHeader))
+	if err != nil {
+		t.logger.Error("Failed to resolve tenant", err) // Log the rejected request
+		return ctx.Status(tenantStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	services, err := t.registry.Services(tenantID)
+	if err != nil {
+		t.logger.Error("Failed to load tenant services", err) // Log the error
+		return ctx.Status(tenantStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	ctx.Locals(tenantLocal, tenantID)         // Let other middleware scope their state by tenant
+	ctx.Locals(tenantServicesLocal, services) // Let controllers use the tenant's services
+	return ctx.Next()
+}
+
+// tenant returns the tenant resolved for the request, or "" when tenancy is not in use
+func tenant(ctx *fiber.Ctx) string {
+	tenantID, _ := ctx.Locals(tenantLocal).(string)
+	return tenantID
+}
+
+// tenantServices returns the services of the request's tenant, if one was resolved
+func tenantServices(ctx *fiber.Ctx) (service.TenantServices, bool) {
+	services, ok := ctx.Locals(tenantServicesLocal).(service.TenantServices)
+	return services, ok
+}
+
+// tenantStatusForError maps tenancy errors to HTTP status codes
+func tenantStatusForError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrUnknownTenant):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidAPIKey), errors.Is(err, service.ErrAPIKeyRequired):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, service.ErrTenantMismatch):
+		return fiber.StatusForbidden
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"bytes"             // Import bytes for creating request bodies
+	"encoding/json"     // Import json for decoding responses
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for per-tenant storage
+	"order-packs-calculator/internal/service"                   // Import the service package for real tenant services
+)
+
+// TenancyTestSuite tests tenant isolation end to end, with real services behind the controllers
+type TenancyTestSuite struct {
+	suite.Suite
+	app *fiber.App // Fiber app for testing
+}
+
+// SetupTest sets up the test environment before each test
+func (s *TenancyTestSuite) SetupTest() {
+	registry := service.NewTenantRegistry("default")
+	for _, tenant := range []struct {
+		id      string
+		sizes   []int
+		apiKeys []string
+	}{
+		{id: "default", sizes: []int{250, 500}},
+		{id: "retail", sizes: []int{10, 20}, apiKeys: []string{"rk-1"}},
+		{id: "wholesale", sizes: []int{1000, 5000}, apiKeys: []string{"wk-1"}},
+	} {
+		packs := service.NewCalculatePacksUseCase(repository.NewInMemoryPackRepository(tenant.sizes))
+		s.Require().NoError(registry.Register(tenant.id, tenant.apiKeys, service.TenantServices{Packs: packs}))
+	}
+	defaults, err := registry.Services("default")
+	s.Require().NoError(err, "Expected no error")
+
+	controller := NewPackController(defaults.Packs, logging.NewLogger())
+	s.app = fiber.New()
+	api := s.app.Group("/api", NewTenancy(registry, logging.NewLogger()).Handle)
+	api.Get("/pack-sizes", controller.GetPackSizes)
+	api.Post("/pack-sizes", controller.UpdatePackSizes)
+}
+
+// TestTenancyTestSuite runs the test suite
+func TestTenancyTestSuite(t *testing.T) {
+	suite.Run(t, new(TenancyTestSuite))
+}
+
+// request sends a request with the given tenant headers and returns the response status and body
+func (s *TenancyTestSuite) request(method, path, body, tenantID, apiKey string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(fiber.HeaderIfMatch, "*")
+	if tenantID != "" {
+		req.Header.Set(TenantHeader, tenantID)
+	}
+	if apiKey != "" {
+		req.Header.Set(APIKeyHeader, apiKey)
+	}
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err, "Expected no error")
+	var decoded map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+// TestTenantsAreIsolated tests that one tenant's update is invisible to the others
+func (s *TenancyTestSuite) TestTenantsAreIsolated() {
+	status, _ := s.request("POST", "/api/pack-sizes", `{"packSizes":[5,15]}`, "", "rk-1")
+	s.Require().Equal(fiber.StatusOK, status, "Retail should update its own sizes")
+
+	status, body := s.request("GET", "/api/pack-sizes", "", "", "rk-1")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+	s.Assert().Equal([]interface{}{5.0, 15.0}, body["packSizes"], "Retail should see its update")
+
+	status, body = s.request("GET", "/api/pack-sizes", "", "wholesale", "wk-1")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+	s.Assert().Equal([]interface{}{1000.0, 5000.0}, body["packSizes"], "Wholesale should keep its sizes")
+
+	status, body = s.request("GET", "/api/pack-sizes", "", "", "")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+	s.Assert().Equal([]interface{}{250.0, 500.0}, body["packSizes"], "Default tenant should keep its sizes")
+}
+
+// TestCrossTenantAccessIsRejected tests that a tenant cannot reach another tenant's catalogue
+func (s *TenancyTestSuite) TestCrossTenantAccessIsRejected() {
+	status, _ := s.request("POST", "/api/pack-sizes", `{"packSizes":[1]}`, "wholesale", "rk-1")
+	s.Assert().Equal(fiber.StatusForbidden, status, "A key must not be used for another tenant")
+
+	status, _ = s.request("GET", "/api/pack-sizes", "", "wholesale", "")
+	s.Assert().Equal(fiber.StatusUnauthorized, status, "Protected tenants need their key")
+
+	status, _ = s.request("GET", "/api/pack-sizes", "", "", "stolen")
+	s.Assert().Equal(fiber.StatusUnauthorized, status, "Unknown keys should be rejected")
+
+	status, _ = s.request("GET", "/api/pack-sizes", "", "nope", "")
+	s.Assert().Equal(fiber.StatusNotFound, status, "Unknown tenants should be rejected")
+
+	status, body := s.request("GET", "/api/pack-sizes", "", "", "wk-1")
+	s.Assert().Equal(fiber.StatusOK, status, "Expected status OK")
+	s.Assert().Equal([]interface{}{1000.0, 5000.0}, body["packSizes"], "Rejected update should not have changed wholesale")
+}
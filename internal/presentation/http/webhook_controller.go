@@ -0,0 +1,121 @@
+package http // Define the package name as "presentation" for HTTP handlers
+
+import (
+	"errors" // Import errors for matching service errors
+
+	"github.com/gofiber/fiber/v2"                               // Import the Fiber framework for handling HTTP requests
+	"order-packs-calculator/internal/domain"                    // Import the domain package for its errors
+	"order-packs-calculator/internal/infrastructure/logging"    // Import the logging package for logging
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service"                   // Import the service package for business logic
+)
+
+// WebhookController handles HTTP requests for webhook subscriptions
+type WebhookController struct {
+	webhooks service.WebhookService // Service managing webhook subscriptions
+	logger   *logging.Logger        // Logger instance for logging requests and errors
+}
+
+// NewWebhookController creates a new instance of WebhookController
+func NewWebhookController(webhooks service.WebhookService, logger *logging.Logger) *WebhookController {
+	return &WebhookController{
+		webhooks: webhooks, // Initialize the service
+		logger:   logger,   // Initialize the logger
+	}
+}
+
+// RegisterWebhook handles the POST /api/webhooks endpoint to subscribe a URL to events
+func (c *WebhookController) RegisterWebhook(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to register webhook") // Log the incoming request
+
+	var request struct { // Define a struct to parse the JSON request body
+		URL    string   `json:"url"`    // URL that receives the events
+		Events []string `json:"events"` // Event types to subscribe to
+	}
+	if err := ctx.BodyParser(&request); err != nil { // Parse the request body into the struct
+		c.logger.Error("Failed to parse request body", err) // Log the error
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request"})
+	}
+
+	subscription, err := c.webhooksFor(ctx).RegisterWebhook(request.URL, request.Events, actor(ctx))
+	if err != nil {
+		c.logger.Error("Failed to register webhook", err) // Log the error
+		return ctx.Status(webhookStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully registered webhook") // Log the successful registration
+	return ctx.Status(fiber.StatusCreated).JSON(subscription)
+}
+
+// ListWebhooks handles the GET /api/webhooks endpoint to list subscriptions
+func (c *WebhookController) ListWebhooks(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to list webhooks") // Log the incoming request
+
+	subscriptions, err := c.webhooksFor(ctx).ListWebhooks()
+	if err != nil {
+		c.logger.Error("Failed to list webhooks", err) // Log the error
+		return ctx.Status(webhookStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully listed webhooks") // Log the successful retrieval
+	return ctx.JSON(fiber.Map{"webhooks": subscriptions})
+}
+
+// UnregisterWebhook handles the DELETE /api/webhooks/:id endpoint to remove a subscription
+func (c *WebhookController) UnregisterWebhook(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to unregister webhook") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the subscription ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid webhook ID"})
+	}
+
+	if err := c.webhooksFor(ctx).UnregisterWebhook(id); err != nil {
+		c.logger.Error("Failed to unregister webhook", err) // Log the error
+		return ctx.Status(webhookStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully unregistered webhook") // Log the successful removal
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles the GET /api/webhooks/:id/deliveries endpoint to show the delivery log
+func (c *WebhookController) ListWebhookDeliveries(ctx *fiber.Ctx) error {
+	c.logger.Info("Received request to list webhook deliveries") // Log the incoming request
+
+	id, err := ctx.ParamsInt("id") // Parse the subscription ID from the path
+	if err != nil || id <= 0 {     // Reject IDs that are not positive numbers
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid webhook ID"})
+	}
+
+	deliveries, err := c.webhooksFor(ctx).ListWebhookDeliveries(id)
+	if err != nil {
+		c.logger.Error("Failed to list webhook deliveries", err) // Log the error
+		return ctx.Status(webhookStatusForError(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.logger.Info("Successfully listed webhook deliveries") // Log the successful retrieval
+	return ctx.JSON(fiber.Map{"deliveries": deliveries})
+}
+
+// webhookStatusForError maps webhook errors to HTTP status codes
+func webhookStatusForError(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrWebhookNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, service.ErrInvalidWebhookURL),
+		errors.Is(err, service.ErrNoWebhookEvents),
+		errors.Is(err, domain.ErrUnknownEventType):
+		return fiber.StatusBadRequest
+	default:
+		return statusForError(err)
+	}
+}
+
+// webhooksFor returns the webhook service of the request's tenant, falling back to the controller's own
+func (c *WebhookController) webhooksFor(ctx *fiber.Ctx) service.WebhookService {
+	if services, ok := tenantServices(ctx); ok { // Tenancy middleware selected the tenant's services
+		return services.Webhooks
+	}
+	return c.webhooks
+}
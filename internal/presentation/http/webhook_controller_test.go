@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"             // Import bytes for creating request bodies
+	"encoding/json"     // Import json for encoding/decoding
+	"net/http/httptest" // Import httptest for HTTP testing
+	"testing"           // Import the testing package for writing unit tests
+
+	"github.com/gofiber/fiber/v2"                               // Import Fiber for creating a test app
+	"github.com/golang/mock/gomock"                             // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for subscriptions
+	"order-packs-calculator/internal/infrastructure/logging"    // Import logging package
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for its errors
+	"order-packs-calculator/internal/service/mocks"             // Import mocks for the service
+)
+
+// WebhookControllerTestSuite defines the test suite for the webhook handlers
+type WebhookControllerTestSuite struct {
+	suite.Suite                           // Embed the testify suite
+	app         *fiber.App                // Fiber app for testing
+	mockService *mocks.MockWebhookService // Use gomock-generated mock type
+	ctrl        *gomock.Controller        // Gomock controller for managing mocks
+}
+
+// SetupTest sets up the test environment before each test
+func (s *WebhookControllerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockService = mocks.NewMockWebhookService(s.ctrl)
+	controller := NewWebhookController(s.mockService, logging.NewLogger())
+
+	s.app = fiber.New()
+	api := s.app.Group("/api")
+	api.Post("/webhooks", controller.RegisterWebhook)
+	api.Get("/webhooks", controller.ListWebhooks)
+	api.Delete("/webhooks/:id", controller.UnregisterWebhook)
+	api.Get("/webhooks/:id/deliveries", controller.ListWebhookDeliveries)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *WebhookControllerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestWebhookControllerTestSuite runs the test suite
+func TestWebhookControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookControllerTestSuite))
+}
+
+// TestRegisterWebhook tests subscribing a URL and rejecting unknown event types
+func (s *WebhookControllerTestSuite) TestRegisterWebhook() {
+	s.mockService.EXPECT().RegisterWebhook("http://example.com/hook", []string{"pack_sizes.updated"}, "alice").
+		Return(domain.WebhookSubscription{ID: 1, URL: "http://example.com/hook", Secret: "s3cret"}, nil)
+	s.mockService.EXPECT().RegisterWebhook("http://example.com/hook", []string{"nope"}, "anonymous").
+		Return(domain.WebhookSubscription{}, domain.ErrUnknownEventType)
+
+	req := httptest.NewRequest("POST", "/api/webhooks",
+		bytes.NewBufferString(`{"url":"http://example.com/hook","events":["pack_sizes.updated"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ActorHeader, "alice")
+	resp, err := s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusCreated, resp.StatusCode, "Expected status Created")
+
+	var subscription domain.WebhookSubscription
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&subscription), "Expected no error decoding response")
+	s.Assert().Equal("s3cret", subscription.Secret, "Secret should be returned on registration")
+
+	req = httptest.NewRequest("POST", "/api/webhooks", bytes.NewBufferString(`{"url":"http://example.com/hook","events":["nope"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = s.app.Test(req)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status BadRequest")
+}
+
+// TestUnregisterWebhook tests removing subscriptions
+func (s *WebhookControllerTestSuite) TestUnregisterWebhook() {
+	s.mockService.EXPECT().UnregisterWebhook(1).Return(nil)
+	s.mockService.EXPECT().UnregisterWebhook(2).Return(repository.ErrWebhookNotFound)
+
+	resp, err := s.app.Test(httptest.NewRequest("DELETE", "/api/webhooks/1", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusNoContent, resp.StatusCode, "Expected status NoContent")
+
+	resp, err = s.app.Test(httptest.NewRequest("DELETE", "/api/webhooks/2", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusNotFound, resp.StatusCode, "Expected status NotFound")
+
+	resp, err = s.app.Test(httptest.NewRequest("DELETE", "/api/webhooks/abc", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusBadRequest, resp.StatusCode, "Expected status BadRequest")
+}
+
+// TestListWebhookDeliveries tests retrieving the delivery log
+func (s *WebhookControllerTestSuite) TestListWebhookDeliveries() {
+	s.mockService.EXPECT().ListWebhookDeliveries(1).
+		Return([]domain.WebhookDelivery{{ID: 1, SubscriptionID: 1, EventID: 3, Attempt: 1, Succeeded: true}}, nil)
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/api/webhooks/1/deliveries", nil))
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(fiber.StatusOK, resp.StatusCode, "Expected status OK")
+
+	var body struct {
+		Deliveries []domain.WebhookDelivery `json:"deliveries"`
+	}
+	s.Assert().NoError(json.NewDecoder(resp.Body).Decode(&body), "Expected no error decoding response")
+	s.Require().Len(body.Deliveries, 1, "Deliveries should be returned")
+	s.Assert().Equal(3, body.Deliveries[0].EventID, "Event ID should match")
+}
@@ -0,0 +1,57 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"time" // Import time for audit timestamps
+
+	"order-packs-calculator/internal/domain"                    // Import the domain package for audit entries
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for audit storage
+)
+
+// AuditPage is one page of audit entries
+type AuditPage struct {
+	Entries []domain.AuditEntry `json:"entries"` // Entries on this page, newest first
+	Total   int                 `json:"total"`   // Matching entries across all pages
+	Offset  int                 `json:"offset"`  // Entries skipped before this page
+	Limit   int                 `json:"limit"`   // Maximum entries per page
+}
+
+// AuditService defines the interface for the AuditUseCase
+type AuditService interface {
+	Record(entry domain.AuditEntry) error
+	ListAudit(filter repository.AuditFilter) (AuditPage, error)
+}
+
+// AuditUseCase records and queries the audit trail
+type AuditUseCase struct {
+	store repository.AuditRepository // Pluggable store for audit entries
+	now   func() time.Time           // Clock used to timestamp entries
+}
+
+// Ensure AuditUseCase implements AuditService
+var _ AuditService = (*AuditUseCase)(nil)
+
+// NewAuditUseCase creates a new instance of AuditUseCase
+func NewAuditUseCase(store repository.AuditRepository) *AuditUseCase {
+	return &AuditUseCase{
+		store: store,    // Initialize the audit store
+		now:   time.Now, // Use the wall clock
+	}
+}
+
+// Record timestamps an entry and appends it to the audit trail
+func (uc *AuditUseCase) Record(entry domain.AuditEntry) error {
+	entry.At = uc.now()
+	_, err := uc.store.AppendAudit(entry)
+	return err
+}
+
+// ListAudit returns a page of matching entries, newest first; the page size is clamped to MaxPageSize
+func (uc *AuditUseCase) ListAudit(filter repository.AuditFilter) (AuditPage, error) {
+	filter.Offset, filter.Limit = pageBounds(filter.Offset, filter.Limit)
+
+	entries, total, err := uc.store.ListAudit(filter)
+	if err != nil {
+		return AuditPage{}, err
+	}
+	return AuditPage{Entries: entries, Total: total, Offset: filter.Offset, Limit: filter.Limit}, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for the fixed clock
+
+	"github.com/golang/mock/gomock"                                   // Import gomock for mocking
+	"github.com/stretchr/testify/suite"                               // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                          // Import the domain package for audit entries
+	"order-packs-calculator/internal/infrastructure/repository"       // Import the repository package for audit filters
+	"order-packs-calculator/internal/infrastructure/repository/mocks" // Import the mocks package
+)
+
+// AuditUseCaseTestSuite defines the test suite for the audit trail
+type AuditUseCaseTestSuite struct {
+	suite.Suite
+	ctrl      *gomock.Controller         // Gomock controller for managing mocks
+	mockStore *mocks.MockAuditRepository // Use gomock-generated mock type
+	uc        *AuditUseCase              // Use case under test
+	now       time.Time                  // Fixed clock for the use case
+}
+
+// SetupTest sets up the test environment before each test
+func (s *AuditUseCaseTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockStore = mocks.NewMockAuditRepository(s.ctrl)
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.uc = NewAuditUseCase(s.mockStore)
+	s.uc.now = func() time.Time { return s.now }
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *AuditUseCaseTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestAuditUseCaseTestSuite runs the test suite
+func TestAuditUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditUseCaseTestSuite))
+}
+
+// TestRecord tests that entries are timestamped before they are stored
+func (s *AuditUseCaseTestSuite) TestRecord() {
+	s.mockStore.EXPECT().AppendAudit(domain.AuditEntry{Action: "quote.create", Actor: "alice", At: s.now}).
+		Return(domain.AuditEntry{ID: 1}, nil)
+
+	s.Assert().NoError(s.uc.Record(domain.AuditEntry{Action: "quote.create", Actor: "alice"}), "Expected no error")
+}
+
+// TestListAuditPageSize tests the default and maximum page sizes
+func (s *AuditUseCaseTestSuite) TestListAuditPageSize() {
+	s.mockStore.EXPECT().ListAudit(repository.AuditFilter{Actor: "alice", Limit: DefaultPageSize}).Return(nil, 0, nil)
+	s.mockStore.EXPECT().ListAudit(repository.AuditFilter{Offset: 0, Limit: MaxPageSize}).
+		Return([]domain.AuditEntry{{ID: 7}}, 12, nil)
+
+	page, err := s.uc.ListAudit(repository.AuditFilter{Actor: "alice"})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(DefaultPageSize, page.Limit, "Missing limits should use the default")
+
+	page, err = s.uc.ListAudit(repository.AuditFilter{Offset: -3, Limit: 10000})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(AuditPage{Entries: []domain.AuditEntry{{ID: 7}}, Total: 12, Offset: 0, Limit: MaxPageSize}, page,
+		"Oversized limits and negative offsets should be clamped")
+}
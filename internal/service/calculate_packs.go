@@ -1,48 +1,634 @@
 package service // Define the package name as "service" for the service layer (application logic)
 
 import (
+	"context" // Import context for ending the watch of the repository
+	"errors"  // Import errors for service-level errors
+	"fmt"     // Import fmt for wrapping mirror failures
+	"sync"    // Import sync for guarding the cached versions
+	"time"    // Import time for effective dates
+
 	"order-packs-calculator/internal/domain"                    // Changed from internal/entity to internal/domain
 	"order-packs-calculator/internal/infrastructure/repository" // Changed from internal/repository to internal/infrastructure/repository
 )
 
+var (
+	// ErrEffectiveFromInPast is returned when a pack-size change is scheduled before the current time
+	ErrEffectiveFromInPast = errors.New("effective date must not be in the past")
+	// ErrApprovalRequired is returned when pack sizes are changed directly while approval is required
+	ErrApprovalRequired = errors.New("pack-size changes require an approved proposal")
+)
+
 // CalculatePacksService defines the interface for the CalculatePacksUseCase
 type CalculatePacksService interface {
 	Execute(orderAmount int) (map[int]int, int, error)
-	UpdatePackSizes(newSizes []int) error
+	ExecuteVersion(orderAmount int, versionID int) (map[int]int, int, error)
+	ExecuteAt(orderAmount int, at time.Time) (map[int]int, int, error)
+	UpdatePackSizes(newSizes []int, author string) (domain.PackSizeVersion, error)
+	SchedulePackSizes(newSizes []int, author string, effectiveFrom time.Time) (domain.PackSizeVersion, error)
+	SchedulePackSizesIfLatest(newSizes []int, author string, effectiveFrom time.Time, latestID int) (domain.PackSizeVersion, error)
 	GetPackSizes() ([]int, error)
+	LatestPackSizeVersion() (int, error)
+	ListPackSizeVersions() ([]domain.PackSizeVersion, error)
+	ListScheduledPackSizes() ([]domain.PackSizeVersion, error)
+	GetPackSizeVersion(id int) (domain.PackSizeVersion, error)
+	RollbackPackSizes(versionID int, author string) (domain.PackSizeVersion, error)
+	RollbackPackSizesIfLatest(versionID int, author string, latestID int) (domain.PackSizeVersion, error)
 }
 
 // CalculatePacksUseCase defines the service for calculating packs
 type CalculatePacksUseCase struct {
-	repo repository.PackRepository // Repository interface to fetch pack sizes
+	repo     repository.PackRepository // Repository interface to fetch pack sizes
+	tieBreak domain.TieBreakPolicy     // Policy used to choose between equally good solutions
+	now      func() time.Time          // Clock used to resolve the pack sizes in effect
+
+	requireApproval bool // Whether pack sizes may only change through approved proposals
+
+	events            bool                        // Whether pack-size events are written to the outbox of the repository
+	calculationEvents repository.OutboxRepository // Outbox calculation events are written to, if any
+	tenant            string                      // Tenant whose catalogue this is, recorded on events
+	rules             PackSizeRules               // Rules new pack-size sets must satisfy
+
+	history repository.CalculationRepository // History that served calculations are recorded in, if any
+
+	mirror   func(packSizes []int) error // Copies pack sizes that take effect to where they are configured, if set
+	mirrored mirrorState                 // Which version the mirror holds, so scheduled changes are copied once in effect
+
+	cache versionCache // Versions kept in memory while the repository is watched
+}
+
+// versionCache holds the pack-size versions read from the repository while its changes are watched;
+// every change drops them, so they are never older than the repository
+type versionCache struct {
+	mu         sync.Mutex
+	watching   bool                     // Whether changes are watched; the cache is bypassed otherwise
+	versions   []domain.PackSizeVersion // Nil until loaded; shared by readers, who must not change it
+	generation int                      // Bumped by every invalidation, so a load racing one is not kept
+}
+
+// mirrorState remembers the version whose pack sizes were last copied to the mirror
+type mirrorState struct {
+	mu        sync.Mutex // Serialises mirror writes, so an older change never overwrites a newer one
+	versionID int        // 0 until a change has been mirrored or scheduled; scheduled changes are not watched before
+}
+
+// Option configures optional behaviour of CalculatePacksUseCase
+type Option func(*CalculatePacksUseCase)
+
+// WithTieBreakPolicy sets the policy used to choose between equally good solutions
+func WithTieBreakPolicy(policy domain.TieBreakPolicy) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.tieBreak = policy // Override the default tie-break policy
+	}
+}
+
+// WithClock sets the clock used to decide which pack sizes are in effect
+func WithClock(now func() time.Time) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.now = now // Override the wall clock, mainly for tests
+	}
+}
+
+// WithApprovalRequired makes direct pack-size changes fail so that they go through proposals
+func WithApprovalRequired(required bool) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.requireApproval = required // Block direct updates and rollbacks when set
+	}
+}
+
+// WithEvents records PackSizesUpdated events in the outbox of the pack repository, which saves every
+// version together with the event reporting it
+func WithEvents(enabled bool) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.events = enabled // Events are only emitted when enabled
+	}
+}
+
+// WithCalculationEvents records a CalculationPerformed event for every successful calculation in the
+// given outbox. Calculations are far more frequent than changes, so their events are kept apart from
+// the pack repository, whose writes they would otherwise compete with
+func WithCalculationEvents(outbox repository.OutboxRepository) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.calculationEvents = outbox // Calculation events are only emitted when an outbox is configured
+	}
+}
+
+// WithTenant records the tenant that owns the catalogue on every emitted event
+func WithTenant(tenant string) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.tenant = tenant // Lets subscribers tell tenants apart
+	}
+}
+
+// WithPackSizeRules sets the rules that new pack-size sets are validated against
+func WithPackSizeRules(rules PackSizeRules) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.rules = rules // Override the default limits
+	}
+}
+
+// WithHistory records every calculation, including failed ones, in the given history
+func WithHistory(history repository.CalculationRepository) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.history = history // Calculations are only recorded when a history is configured
+	}
+}
+
+// WithPackSizesMirror copies the sizes of every change that takes effect immediately to where the
+// catalogue is seeded from, such as the configuration file, so they survive a restart. Scheduled changes
+// are copied by RunPackSizesMirror once they take effect
+func WithPackSizesMirror(mirror func(packSizes []int) error) Option {
+	return func(uc *CalculatePacksUseCase) {
+		uc.mirror = mirror // Changes are only copied when a mirror is configured
+	}
 }
 
 // Ensure CalculatePacksUseCase implements CalculatePacksService
 var _ CalculatePacksService = (*CalculatePacksUseCase)(nil)
 
 // NewCalculatePacksUseCase creates a new instance of CalculatePacksUseCase
-func NewCalculatePacksUseCase(repo repository.PackRepository) *CalculatePacksUseCase {
-	return &CalculatePacksUseCase{repo: repo} // Initialize the service with the provided repository
+func NewCalculatePacksUseCase(repo repository.PackRepository, opts ...Option) *CalculatePacksUseCase {
+	uc := &CalculatePacksUseCase{
+		repo:     repo,                         // Initialize the service with the provided repository
+		tieBreak: domain.DefaultTieBreakPolicy, // Keep the historical behaviour unless configured otherwise
+		now:      time.Now,                     // Use the wall clock by default
+		rules:    DefaultPackSizeRules,         // Apply the default limits unless configured otherwise
+	}
+	for _, opt := range opts { // Apply the optional settings
+		opt(uc)
+	}
+	return uc
 }
 
 // Execute runs the service to calculate packs for an order
 func (uc *CalculatePacksUseCase) Execute(orderAmount int) (map[int]int, int, error) {
-	// Fetch pack sizes from the repository (could be a database in a real app)
-	packSizes, err := uc.repo.GetPackSizes() // Call the repository to get the current pack sizes
-	if err != nil {                          // Check if there was an error fetching pack sizes
+	return uc.ExecuteAt(orderAmount, uc.now()) // Use the pack sizes in effect right now
+}
+
+// ExecuteAt calculates packs for an order using the pack sizes in effect at the given time,
+// which allows back-dated quotes
+func (uc *CalculatePacksUseCase) ExecuteAt(orderAmount int, at time.Time) (map[int]int, int, error) {
+	// Fetch the version in effect from the repository (could be a database in a real app)
+	version, err := uc.activeVersion(at) // Resolve the active version, from memory while the repository is watched
+	if err != nil {                      // Check if there was an error fetching pack sizes
 		return nil, 0, err // Return the error if fetching failed
 	}
 
 	// Call the domain function to calculate packs using the fetched pack sizes
-	return domain.CalculatePacks(packSizes, orderAmount) // Pass the []int directly to the domain layer
+	return uc.calculateVersion(version, orderAmount) // Pass the version so the event can name it
 }
 
-// UpdatePackSizes updates the pack sizes in the repository
-func (uc *CalculatePacksUseCase) UpdatePackSizes(newSizes []int) error {
-	return uc.repo.UpdatePackSizes(newSizes) // Call the repository to update pack sizes
+// ExecuteVersion calculates packs for an order using a specific pack-size version
+func (uc *CalculatePacksUseCase) ExecuteVersion(orderAmount int, versionID int) (map[int]int, int, error) {
+	version, err := uc.repo.GetVersion(versionID) // Fetch the pinned version instead of the active one
+	if err != nil {                               // Check if the version could not be fetched
+		return nil, 0, err // Return the error if fetching failed
+	}
+
+	return uc.calculateVersion(version, orderAmount)
+}
+
+// CalculateWithActiveVersion calculates packs for an order with the pack sizes in effect now, like Execute,
+// and also returns the version used, for results that must name it. The version is returned with a failed
+// calculation too, unless it could not be resolved
+func (uc *CalculatePacksUseCase) CalculateWithActiveVersion(orderAmount int) (domain.PackSizeVersion, map[int]int, int, error) {
+	version, err := uc.activeVersion(uc.now()) // Resolve the active version, from memory while the repository is watched
+	if err != nil {
+		return domain.PackSizeVersion{}, nil, 0, err
+	}
+
+	packs, total, err := uc.calculateVersion(version, orderAmount) // Recorded and reported like every calculation
+	return version, packs, total, err
+}
+
+// Now returns the time on the catalogue's clock, so use cases built on the catalogue agree with it on
+// which pack sizes are in effect
+func (uc *CalculatePacksUseCase) Now() time.Time {
+	return uc.now()
+}
+
+// UpdatePackSizes stores the new pack sizes as a new version that takes effect immediately
+func (uc *CalculatePacksUseCase) UpdatePackSizes(newSizes []int, author string) (domain.PackSizeVersion, error) {
+	return uc.SchedulePackSizes(newSizes, author, time.Time{})
 }
 
-// GetPackSizes retrieves the current pack sizes from the repository
+// SchedulePackSizes stores the new pack sizes as a new version that takes effect at effectiveFrom;
+// a zero effectiveFrom applies the change immediately
+func (uc *CalculatePacksUseCase) SchedulePackSizes(newSizes []int, author string, effectiveFrom time.Time) (domain.PackSizeVersion, error) {
+	return uc.schedule(newSizes, author, effectiveFrom, anyLatestVersion)
+}
+
+// SchedulePackSizesIfLatest stores the new pack sizes like SchedulePackSizes, but only while latestID is
+// still the newest version; a change based on an outdated version fails with repository.ErrVersionConflict
+func (uc *CalculatePacksUseCase) SchedulePackSizesIfLatest(newSizes []int, author string, effectiveFrom time.Time, latestID int) (domain.PackSizeVersion, error) {
+	return uc.schedule(newSizes, author, effectiveFrom, latestID)
+}
+
+// schedule validates and publishes a direct pack-size change
+func (uc *CalculatePacksUseCase) schedule(newSizes []int, author string, effectiveFrom time.Time, latestID int) (domain.PackSizeVersion, error) {
+	if uc.requireApproval { // Direct changes are not allowed when approval is required
+		return domain.PackSizeVersion{}, ErrApprovalRequired
+	}
+
+	newSizes, err := uc.rules.Validate(newSizes) // Reject invalid sets and normalise the rest
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+
+	return uc.publish(domain.PackSizeVersion{
+		PackSizes:     newSizes,
+		Author:        author,
+		EffectiveFrom: effectiveFrom,
+	}, latestID)
+}
+
+// GetPackSizes retrieves the pack sizes currently in effect from the repository
 func (uc *CalculatePacksUseCase) GetPackSizes() ([]int, error) {
-	return uc.repo.GetPackSizes() // Delegate to the repository to fetch pack sizes
+	version, err := uc.activeVersion(uc.now()) // Resolve the active version, from memory while the repository is watched
+	if err != nil {                            // Check if there was an error fetching pack sizes
+		return nil, err // Return the error if fetching failed
+	}
+	return version.PackSizes, nil
+}
+
+// LatestPackSizeVersion returns the ID of the newest pack-size version, which changes with every change
+// to the pack sizes; clients send it back to make sure they change the sizes they have seen
+func (uc *CalculatePacksUseCase) LatestPackSizeVersion() (int, error) {
+	versions, cached, err := uc.cachedVersions()
+	if err != nil {
+		return 0, err
+	}
+	if !cached { // Delegate to the repository, which bumps it on every save
+		return uc.repo.LatestVersionID()
+	}
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[len(versions)-1].ID, nil
+}
+
+// ListPackSizeVersions retrieves every pack-size version, oldest first
+func (uc *CalculatePacksUseCase) ListPackSizeVersions() ([]domain.PackSizeVersion, error) {
+	return uc.listVersions() // Fetch the history, from memory while the repository is watched
+}
+
+// ListScheduledPackSizes retrieves the versions that have not taken effect yet, soonest first
+func (uc *CalculatePacksUseCase) ListScheduledPackSizes() ([]domain.PackSizeVersion, error) {
+	versions, err := uc.listVersions() // Fetch the full history, from memory while the repository is watched
+	if err != nil {                    // Check if there was an error fetching the history
+		return nil, err // Return the error if fetching failed
+	}
+	return domain.UpcomingVersions(versions, uc.now()), nil
+}
+
+// GetPackSizeVersion retrieves a single pack-size version by ID
+func (uc *CalculatePacksUseCase) GetPackSizeVersion(id int) (domain.PackSizeVersion, error) {
+	return uc.repo.GetVersion(id) // Delegate to the repository to fetch the version
+}
+
+// RollbackPackSizes restores an earlier version by saving a copy of it that takes effect immediately
+func (uc *CalculatePacksUseCase) RollbackPackSizes(versionID int, author string) (domain.PackSizeVersion, error) {
+	return uc.rollback(versionID, author, anyLatestVersion)
+}
+
+// RollbackPackSizesIfLatest restores an earlier version like RollbackPackSizes, but only while latestID is
+// still the newest version; a rollback based on an outdated version fails with repository.ErrVersionConflict
+func (uc *CalculatePacksUseCase) RollbackPackSizesIfLatest(versionID int, author string, latestID int) (domain.PackSizeVersion, error) {
+	return uc.rollback(versionID, author, latestID)
+}
+
+// rollback publishes a copy of an earlier version
+func (uc *CalculatePacksUseCase) rollback(versionID int, author string, latestID int) (domain.PackSizeVersion, error) {
+	if uc.requireApproval { // A rollback is a change too and must be proposed
+		return domain.PackSizeVersion{}, ErrApprovalRequired
+	}
+
+	target, err := uc.repo.GetVersion(versionID) // Fetch the version to restore
+	if err != nil {                              // Check if the version exists
+		return domain.PackSizeVersion{}, err // Return the error if it does not
+	}
+
+	// History stays immutable: the rollback is recorded as a new version
+	return uc.publish(domain.PackSizeVersion{
+		PackSizes:  target.PackSizes,
+		Author:     author,
+		RollbackOf: target.ID,
+	}, latestID)
+}
+
+// ExportState returns every pack-size version, scheduled ones included, oldest first, for a snapshot
+func (uc *CalculatePacksUseCase) ExportState() ([]domain.PackSizeVersion, error) {
+	return uc.repo.ListVersions() // Read the repository, so the snapshot never holds stale cached versions
+}
+
+// RestoreState replaces every pack-size version with the given ones, e.g. from a snapshot. The sizes
+// then in effect are reported and persisted like any other change
+func (uc *CalculatePacksUseCase) RestoreState(versions []domain.PackSizeVersion) error {
+	err := uc.repo.ReplaceVersions(versions)
+	uc.cache.invalidate() // The repository may have changed even if the replace failed
+	if err != nil {
+		return err
+	}
+
+	active, err := uc.activeVersion(uc.now())
+	if err == nil && uc.events { // Downstream systems must learn about restored sizes too
+		err = uc.emit(uc.repo, domain.PackSizesUpdated{Version: active})
+	}
+	if err == nil && uc.mirror != nil { // Restored sizes must survive a restart like any other change
+		err = uc.mirrorVersion(active)
+	}
+	if err != nil {
+		return fmt.Errorf("pack sizes restored but the change was not fully reported or persisted: %w", err)
+	}
+	return nil
+}
+
+// anyLatestVersion publishes a version whatever the newest version is
+const anyLatestVersion = repository.AnyLatestVersion
+
+// publish saves a new pack-size version; it is the single path through which pack sizes change.
+// Unless latestID is anyLatestVersion, the version is only saved on top of that version
+func (uc *CalculatePacksUseCase) publish(version domain.PackSizeVersion, latestID int) (domain.PackSizeVersion, error) {
+	now := uc.now()
+	if version.EffectiveFrom.IsZero() { // No effective date means the change applies immediately
+		version.EffectiveFrom = now
+	} else if version.EffectiveFrom.Before(now) { // Changing the past would make earlier quotes unexplainable
+		return domain.PackSizeVersion{}, ErrEffectiveFromInPast
+	}
+
+	var (
+		saved domain.PackSizeVersion
+		err   error
+	)
+	switch {
+	case uc.events: // Save the event in the same write, so downstream systems learn about every change that was saved
+		saved, err = uc.repo.SaveVersionWithEvent(version, latestID, func(saved domain.PackSizeVersion) (domain.EventEnvelope, error) {
+			return uc.envelope(domain.PackSizesUpdated{Version: saved})
+		})
+	case latestID == anyLatestVersion:
+		saved, err = uc.repo.SaveVersion(version) // Call the repository to append a version
+	default:
+		saved, err = uc.repo.SaveVersionIfLatest(version, latestID) // Append only if nobody changed the sizes meanwhile
+	}
+	// Drop the cached versions now rather than when the change is reported, so the caller reads its own
+	// change; a conflict drops them too, as it means they may be missing someone else's
+	uc.cache.invalidate()
+	if err != nil { // Check if the version could not be saved
+		return domain.PackSizeVersion{}, err
+	}
+
+	if uc.mirror == nil {
+		return saved, nil
+	}
+	if saved.EffectiveFrom.After(now) {
+		// Scheduled changes are not copied yet: until they take effect, the sizes in effect are the ones to
+		// keep. Remember which version is in effect, so RunPackSizesMirror notices when that changes
+		if err := uc.trackMirror(now); err != nil {
+			return domain.PackSizeVersion{}, fmt.Errorf("version %d saved but the pack sizes in effect could not be resolved: %w", saved.ID, err)
+		}
+		return saved, nil
+	}
+	if err := uc.mirrorVersion(saved); err != nil {
+		return domain.PackSizeVersion{}, fmt.Errorf("version %d saved but the pack sizes were not persisted: %w", saved.ID, err)
+	}
+	return saved, nil
+}
+
+// RunPackSizesMirror copies the pack sizes of scheduled changes to the mirror once they take effect,
+// checking every interval until the context is cancelled. Without a mirror it returns at once
+func (uc *CalculatePacksUseCase) RunPackSizesMirror(ctx context.Context, interval time.Duration, onError func(error)) {
+	if uc.mirror == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.mirrorActive(); err != nil {
+				onError(err) // Retried on the next run, as the mirror still holds the previous version
+			}
+		}
+	}
+}
+
+// mirrorVersion copies the pack sizes of a version that is in effect to the mirror
+func (uc *CalculatePacksUseCase) mirrorVersion(version domain.PackSizeVersion) error {
+	uc.mirrored.mu.Lock()
+	defer uc.mirrored.mu.Unlock()
+	if err := uc.mirror(version.PackSizes); err != nil {
+		return err
+	}
+	uc.mirrored.versionID = version.ID
+	return nil
+}
+
+// trackMirror records the version in effect as the one the mirror holds, unless one is already recorded
+func (uc *CalculatePacksUseCase) trackMirror(now time.Time) error {
+	uc.mirrored.mu.Lock()
+	defer uc.mirrored.mu.Unlock()
+	if uc.mirrored.versionID != 0 {
+		return nil
+	}
+	active, err := uc.activeVersion(now)
+	if err != nil {
+		return err
+	}
+	uc.mirrored.versionID = active.ID
+	return nil
+}
+
+// mirrorActive copies the pack sizes in effect to the mirror when a scheduled change has taken effect
+// since the last copy
+func (uc *CalculatePacksUseCase) mirrorActive() error {
+	uc.mirrored.mu.Lock()
+	defer uc.mirrored.mu.Unlock()
+	if uc.mirrored.versionID == 0 { // Nothing was scheduled, so the mirror holds the sizes in effect
+		return nil
+	}
+	active, err := uc.activeVersion(uc.now())
+	if err != nil || active.ID == uc.mirrored.versionID {
+		return err
+	}
+	if err := uc.mirror(active.PackSizes); err != nil {
+		return fmt.Errorf("version %d took effect but the pack sizes were not persisted: %w", active.ID, err)
+	}
+	uc.mirrored.versionID = active.ID
+	return nil
+}
+
+// calculateVersion calculates packs with the sizes of the given version and records the calculation
+func (uc *CalculatePacksUseCase) calculateVersion(version domain.PackSizeVersion, orderAmount int) (map[int]int, int, error) {
+	start := time.Now() // Measure with the wall clock even when the use case clock is fixed
+	packs, total, err := uc.calculate(version.PackSizes, orderAmount)
+	if historyErr := uc.recordCalculation(version, orderAmount, packs, total, err, time.Since(start)); historyErr != nil && err == nil {
+		err = historyErr // Report history failures rather than serving unrecorded results
+	}
+	if err != nil { // Failed calculations are not events
+		return nil, 0, err
+	}
+
+	if uc.calculationEvents == nil { // Calculation events are optional
+		return packs, total, nil
+	}
+	event := domain.CalculationPerformed{
+		OrderAmount:     orderAmount,
+		PackSizeVersion: version.ID,
+		Packs:           packs,
+		TotalItems:      total,
+	}
+	if err := uc.emit(uc.calculationEvents, event); err != nil { // Report outbox failures rather than losing the event
+		return nil, 0, err
+	}
+	return packs, total, nil
+}
+
+// recordCalculation stores a calculation in the history, if one is configured
+func (uc *CalculatePacksUseCase) recordCalculation(version domain.PackSizeVersion, orderAmount int, packs map[int]int, total int, calcErr error, duration time.Duration) error {
+	if uc.history == nil { // History is optional
+		return nil
+	}
+
+	record := domain.CalculationRecord{
+		OrderAmount:     orderAmount,
+		PackSizeVersion: version.ID,
+		PackSizes:       version.PackSizes,
+		Packs:           packs,
+		TotalItems:      total,
+		Duration:        duration,
+		CalculatedAt:    uc.now(),
+	}
+	if calcErr != nil {
+		record.Error = calcErr.Error()
+	}
+	_, err := uc.history.SaveCalculation(record)
+	return err
+}
+
+// emit writes an event that is not saved together with a version to the given outbox
+func (uc *CalculatePacksUseCase) emit(outbox repository.OutboxRepository, event domain.Event) error {
+	envelope, err := uc.envelope(event)
+	if err != nil { // Check if the event could not be serialised
+		return err
+	}
+	_, err = outbox.AppendEvent(envelope)
+	return err
+}
+
+// envelope serialises an event for the outbox, naming the tenant
+func (uc *CalculatePacksUseCase) envelope(event domain.Event) (domain.EventEnvelope, error) {
+	envelope, err := domain.NewEventEnvelope(event, uc.now())
+	if err != nil {
+		return domain.EventEnvelope{}, err
+	}
+	envelope.Tenant = uc.tenant
+	return envelope, nil
+}
+
+// calculate runs the domain calculation with the configured tie-break policy
+func (uc *CalculatePacksUseCase) calculate(packSizes []int, orderAmount int) (map[int]int, int, error) {
+	return domain.CalculatePacksWithPolicy(packSizes, orderAmount, uc.tieBreak)
+}
+
+// WatchPackSizes keeps the pack-size versions in memory until ctx is done, dropping them whenever the
+// repository reports a change. It returns at once when the repository cannot be watched, in which case
+// every read keeps going to the repository
+func (uc *CalculatePacksUseCase) WatchPackSizes(ctx context.Context) {
+	watcher, ok := repository.WatcherOf(uc.repo) // Look through decorators such as the metrics wrapper
+	if !ok {
+		return
+	}
+
+	changes := watcher.Watch(ctx) // Watch before caching, so no change is missed
+	uc.cache.setWatching(true)
+	defer uc.cache.setWatching(false) // Without reports the cache could go stale
+	for range changes {               // The channel is closed once ctx is done
+		uc.cache.invalidate()
+	}
+}
+
+// activeVersion resolves the version in effect at the given time
+func (uc *CalculatePacksUseCase) activeVersion(at time.Time) (domain.PackSizeVersion, error) {
+	versions, cached, err := uc.cachedVersions()
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	if !cached { // Let the repository resolve it
+		return uc.repo.GetActiveVersion(at)
+	}
+	version, err := domain.ActiveVersion(versions, at)
+	if err != nil {
+		return domain.PackSizeVersion{}, err
+	}
+	return copyPackSizeVersion(version), nil
+}
+
+// listVersions returns every version, oldest first
+func (uc *CalculatePacksUseCase) listVersions() ([]domain.PackSizeVersion, error) {
+	versions, cached, err := uc.cachedVersions()
+	if err != nil {
+		return nil, err
+	}
+	if !cached {
+		return uc.repo.ListVersions()
+	}
+	copied := make([]domain.PackSizeVersion, len(versions))
+	for i, version := range versions {
+		copied[i] = copyPackSizeVersion(version) // Callers must not change the cached versions
+	}
+	return copied, nil
+}
+
+// cachedVersions returns the versions kept in memory, loading them when needed; cached is false while
+// the repository is not watched
+func (uc *CalculatePacksUseCase) cachedVersions() (versions []domain.PackSizeVersion, cached bool, err error) {
+	c := &uc.cache
+	c.mu.Lock()
+	if !c.watching || c.versions != nil {
+		versions, cached = c.versions, c.watching
+		c.mu.Unlock()
+		return versions, cached, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	versions, err = uc.repo.ListVersions() // Load without the lock, so slow stores do not block invalidation
+	if err != nil {
+		return nil, false, err
+	}
+	if versions == nil {
+		versions = []domain.PackSizeVersion{} // Nil means not loaded
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.watching && c.generation == generation { // Keep the load unless a change was reported meanwhile
+		c.versions = versions
+	}
+	return versions, true, nil
+}
+
+// setWatching turns the cache on or off; either way it starts empty
+func (c *versionCache) setWatching(watching bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watching = watching
+	c.versions = nil
+	c.generation++
+}
+
+// invalidate drops the cached versions
+func (c *versionCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions = nil
+	c.generation++
+}
+
+// copyPackSizeVersion returns a version that shares no memory with the given one
+func copyPackSizeVersion(version domain.PackSizeVersion) domain.PackSizeVersion {
+	version.PackSizes = append([]int(nil), version.PackSizes...)
+	return version
 }
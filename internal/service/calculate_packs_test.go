@@ -1,9 +1,14 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
+	"order-packs-calculator/internal/domain"
+	"order-packs-calculator/internal/infrastructure/repository"
 	"order-packs-calculator/internal/infrastructure/repository/mocks" // Import the mocks package
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"     // Import gomock for mocking
 	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
@@ -15,6 +20,7 @@ type CalculatePacksUseCaseTestSuite struct {
 	mockRepo *mocks.MockPackRepository // Use gomock-generated mock type
 	uc       *CalculatePacksUseCase    // Use case under test
 	ctrl     *gomock.Controller        // Gomock controller for managing mocks
+	now      time.Time                 // Fixed clock for the use case
 }
 
 // SetupTest sets up the test environment before each test
@@ -25,8 +31,9 @@ func (s *CalculatePacksUseCaseTestSuite) SetupTest() {
 	// Create a mock repository using gomock
 	s.mockRepo = mocks.NewMockPackRepository(s.ctrl)
 
-	// Create a new use case instance
-	s.uc = NewCalculatePacksUseCase(s.mockRepo)
+	// Create a new use case instance with a fixed clock
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.uc = NewCalculatePacksUseCase(s.mockRepo, WithClock(func() time.Time { return s.now }))
 }
 
 // TearDownTest cleans up the test environment after each test
@@ -44,7 +51,7 @@ func TestCalculatePacksUseCaseTestSuite(t *testing.T) {
 func (s *CalculatePacksUseCaseTestSuite) TestExecute() {
 	s.Run("Success", func() {
 		// Set up the mock expectation using gomock API
-		s.mockRepo.EXPECT().GetPackSizes().Return([]int{250, 500, 1000, 2000, 5000}, nil)
+		s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{PackSizes: []int{250, 500, 1000, 2000, 5000}}, nil)
 
 		// Call the Execute method
 		result, total, err := s.uc.Execute(263)
@@ -53,9 +60,21 @@ func (s *CalculatePacksUseCaseTestSuite) TestExecute() {
 		s.Assert().Equal(500, total, "Total items should match expected")
 	})
 
+	s.Run("TieBreakPolicy", func() {
+		// Use a service configured to prefer smaller packs
+		uc := NewCalculatePacksUseCase(s.mockRepo, WithTieBreakPolicy(domain.TieBreakPreferSmaller), WithClock(func() time.Time { return s.now }))
+		s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{PackSizes: []int{5, 6, 8, 10, 13}}, nil)
+
+		// 6+6+13 and 5+10+10 are equally good; the policy picks the latter
+		result, total, err := uc.Execute(25)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(map[int]int{10: 2, 5: 1}, result, "Result should follow the tie-break policy")
+		s.Assert().Equal(25, total, "Total items should match expected")
+	})
+
 	s.Run("RepositoryError", func() {
 		// Set up the mock expectation using gomock API
-		s.mockRepo.EXPECT().GetPackSizes().Return([]int{}, assert.AnError)
+		s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{}, assert.AnError)
 
 		// Call the Execute method
 		result, total, err := s.uc.Execute(263)
@@ -64,3 +83,358 @@ func (s *CalculatePacksUseCaseTestSuite) TestExecute() {
 		s.Assert().Equal(0, total, "Total should be 0 on error")
 	})
 }
+
+// TestExecuteVersion tests calculating against a pinned pack-size version
+func (s *CalculatePacksUseCaseTestSuite) TestExecuteVersion() {
+	s.Run("Success", func() {
+		s.mockRepo.EXPECT().GetVersion(1).Return(domain.PackSizeVersion{ID: 1, PackSizes: []int{250, 500}}, nil)
+
+		// The pinned version is used instead of the latest pack sizes
+		result, total, err := s.uc.ExecuteVersion(263, 1)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(map[int]int{500: 1}, result, "Result should match expected")
+		s.Assert().Equal(500, total, "Total items should match expected")
+	})
+
+	s.Run("UnknownVersion", func() {
+		s.mockRepo.EXPECT().GetVersion(9).Return(domain.PackSizeVersion{}, assert.AnError)
+
+		_, _, err := s.uc.ExecuteVersion(263, 9)
+		s.Assert().Equal(assert.AnError, err, "Expected the repository error")
+	})
+}
+
+// TestCalculateWithActiveVersion tests calculating with the active version and naming it
+func (s *CalculatePacksUseCaseTestSuite) TestCalculateWithActiveVersion() {
+	s.Run("Success", func() {
+		s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{ID: 3, PackSizes: []int{250, 500}}, nil)
+
+		version, packs, total, err := s.uc.CalculateWithActiveVersion(263)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(3, version.ID, "Version used should be returned")
+		s.Assert().Equal(map[int]int{500: 1}, packs, "Result should match expected")
+		s.Assert().Equal(500, total, "Total items should match expected")
+	})
+
+	s.Run("InvalidOrder", func() {
+		s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{ID: 3, PackSizes: []int{250, 500}}, nil)
+
+		version, _, _, err := s.uc.CalculateWithActiveVersion(-1)
+		s.Assert().Equal(domain.ErrInvalidOrderAmount, err, "Expected the calculation error")
+		s.Assert().Equal(3, version.ID, "Version should be returned with failed calculations")
+	})
+
+	s.Run("NoActiveVersion", func() {
+		s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{}, assert.AnError)
+
+		version, _, _, err := s.uc.CalculateWithActiveVersion(263)
+		s.Assert().Equal(assert.AnError, err, "Expected the repository error")
+		s.Assert().Zero(version.ID, "No version should be returned")
+	})
+}
+
+// TestUpdatePackSizes tests that updates are saved as new versions
+func (s *CalculatePacksUseCaseTestSuite) TestUpdatePackSizes() {
+	saved := domain.PackSizeVersion{ID: 2, PackSizes: []int{100, 200}, Author: "alice"}
+	s.mockRepo.EXPECT().SaveVersion(domain.PackSizeVersion{PackSizes: []int{100, 200}, Author: "alice", EffectiveFrom: s.now}).Return(saved, nil)
+
+	version, err := s.uc.UpdatePackSizes([]int{100, 200}, "alice")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(saved, version, "Saved version should be returned")
+}
+
+// TestRollbackPackSizes tests restoring an earlier pack-size version
+func (s *CalculatePacksUseCaseTestSuite) TestRollbackPackSizes() {
+	s.Run("Success", func() {
+		s.mockRepo.EXPECT().GetVersion(1).Return(domain.PackSizeVersion{ID: 1, PackSizes: []int{250, 500}, Author: "config"}, nil)
+		// The rollback is saved as a new version copying the old sizes
+		s.mockRepo.EXPECT().SaveVersion(domain.PackSizeVersion{PackSizes: []int{250, 500}, Author: "bob", EffectiveFrom: s.now, RollbackOf: 1}).
+			Return(domain.PackSizeVersion{ID: 3, PackSizes: []int{250, 500}, Author: "bob", RollbackOf: 1}, nil)
+
+		version, err := s.uc.RollbackPackSizes(1, "bob")
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(3, version.ID, "Rollback should create a new version")
+	})
+
+	s.Run("UnknownVersion", func() {
+		s.mockRepo.EXPECT().GetVersion(9).Return(domain.PackSizeVersion{}, assert.AnError)
+
+		_, err := s.uc.RollbackPackSizes(9, "bob")
+		s.Assert().Equal(assert.AnError, err, "Expected the repository error")
+	})
+}
+
+// TestRollbackPackSizesIfLatest tests that rollbacks based on an outdated version are rejected
+func (s *CalculatePacksUseCaseTestSuite) TestRollbackPackSizesIfLatest() {
+	s.Run("Latest", func() {
+		s.mockRepo.EXPECT().GetVersion(1).Return(domain.PackSizeVersion{ID: 1, PackSizes: []int{250, 500}}, nil)
+		s.mockRepo.EXPECT().SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{250, 500}, Author: "bob", EffectiveFrom: s.now, RollbackOf: 1}, 2).
+			Return(domain.PackSizeVersion{ID: 3, PackSizes: []int{250, 500}, RollbackOf: 1}, nil)
+
+		version, err := s.uc.RollbackPackSizesIfLatest(1, "bob", 2)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(3, version.ID, "Rollback should create a new version")
+	})
+
+	s.Run("Conflict", func() {
+		s.mockRepo.EXPECT().GetVersion(1).Return(domain.PackSizeVersion{ID: 1, PackSizes: []int{250, 500}}, nil)
+		s.mockRepo.EXPECT().SaveVersionIfLatest(gomock.Any(), 1).Return(domain.PackSizeVersion{}, repository.ErrVersionConflict)
+
+		_, err := s.uc.RollbackPackSizesIfLatest(1, "bob", 1)
+		s.Assert().ErrorIs(err, repository.ErrVersionConflict, "Expected a conflict")
+	})
+}
+
+// TestExecuteAt tests back-dated calculations against the pack sizes in effect at the time
+func (s *CalculatePacksUseCaseTestSuite) TestExecuteAt() {
+	at := s.now.Add(-30 * 24 * time.Hour)
+	s.mockRepo.EXPECT().GetActiveVersion(at).Return(domain.PackSizeVersion{ID: 1, PackSizes: []int{100}}, nil)
+
+	result, total, err := s.uc.ExecuteAt(263, at)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(map[int]int{100: 3}, result, "Result should use the back-dated pack sizes")
+	s.Assert().Equal(300, total, "Total items should match expected")
+}
+
+// TestSchedulePackSizes tests scheduling pack-size changes for a future date
+func (s *CalculatePacksUseCaseTestSuite) TestSchedulePackSizes() {
+	s.Run("Future", func() {
+		effectiveFrom := s.now.Add(24 * time.Hour)
+		s.mockRepo.EXPECT().SaveVersion(domain.PackSizeVersion{PackSizes: []int{100}, Author: "alice", EffectiveFrom: effectiveFrom}).
+			Return(domain.PackSizeVersion{ID: 2, PackSizes: []int{100}, Author: "alice", EffectiveFrom: effectiveFrom}, nil)
+
+		version, err := s.uc.SchedulePackSizes([]int{100}, "alice", effectiveFrom)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(effectiveFrom, version.EffectiveFrom, "Effective date should be stored")
+	})
+
+	s.Run("Past", func() {
+		// No repository call is expected for a change in the past
+		_, err := s.uc.SchedulePackSizes([]int{100}, "alice", s.now.Add(-time.Minute))
+		s.Assert().Equal(ErrEffectiveFromInPast, err, "Expected past effective dates to be rejected")
+	})
+}
+
+// TestSchedulePackSizesIfLatest tests that changes based on an outdated version are rejected
+func (s *CalculatePacksUseCaseTestSuite) TestSchedulePackSizesIfLatest() {
+	s.Run("Latest", func() {
+		s.mockRepo.EXPECT().SaveVersionIfLatest(domain.PackSizeVersion{PackSizes: []int{100}, Author: "alice", EffectiveFrom: s.now}, 3).
+			Return(domain.PackSizeVersion{ID: 4, PackSizes: []int{100}}, nil)
+
+		version, err := s.uc.SchedulePackSizesIfLatest([]int{100}, "alice", time.Time{}, 3)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(4, version.ID, "Saved version should be returned")
+	})
+
+	s.Run("Conflict", func() {
+		s.mockRepo.EXPECT().SaveVersionIfLatest(gomock.Any(), 2).Return(domain.PackSizeVersion{}, repository.ErrVersionConflict)
+
+		_, err := s.uc.SchedulePackSizesIfLatest([]int{100}, "alice", time.Time{}, 2)
+		s.Assert().ErrorIs(err, repository.ErrVersionConflict, "Expected a conflict")
+	})
+
+	s.Run("Invalid", func() {
+		// Invalid sets are rejected before the repository is asked
+		_, err := s.uc.SchedulePackSizesIfLatest([]int{0}, "alice", time.Time{}, 3)
+		var validationErr *ValidationError
+		s.Assert().ErrorAs(err, &validationErr, "Expected a validation error")
+	})
+}
+
+// TestLatestPackSizeVersion tests reading the newest version ID
+func (s *CalculatePacksUseCaseTestSuite) TestLatestPackSizeVersion() {
+	s.mockRepo.EXPECT().LatestVersionID().Return(3, nil)
+
+	latest, err := s.uc.LatestPackSizeVersion()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(3, latest, "Newest version ID should be returned")
+}
+
+// TestListScheduledPackSizes tests listing pack-size changes that have not taken effect yet
+func (s *CalculatePacksUseCaseTestSuite) TestListScheduledPackSizes() {
+	s.mockRepo.EXPECT().ListVersions().Return([]domain.PackSizeVersion{
+		{ID: 1, PackSizes: []int{250}},
+		{ID: 2, PackSizes: []int{300}, EffectiveFrom: s.now.Add(48 * time.Hour)},
+		{ID: 3, PackSizes: []int{200}, EffectiveFrom: s.now.Add(24 * time.Hour)},
+	}, nil)
+
+	scheduled, err := s.uc.ListScheduledPackSizes()
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(scheduled, 2, "Only future versions should be listed")
+	s.Assert().Equal(3, scheduled[0].ID, "Soonest change should come first")
+}
+
+// TestUpdatePackSizesWithWallClock tests that immediate updates apply with the real clock
+func (s *CalculatePacksUseCaseTestSuite) TestUpdatePackSizesWithWallClock() {
+	uc := NewCalculatePacksUseCase(repository.NewInMemoryPackRepository([]int{250, 500}))
+
+	_, err := uc.UpdatePackSizes([]int{100, 200}, "alice")
+	s.Require().NoError(err, "Expected no error")
+
+	sizes, err := uc.GetPackSizes()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, sizes, "Update should take effect immediately")
+}
+
+// TestApprovalRequired tests that direct changes are blocked when approval is required
+func (s *CalculatePacksUseCaseTestSuite) TestApprovalRequired() {
+	uc := NewCalculatePacksUseCase(s.mockRepo, WithApprovalRequired(true))
+
+	// No repository calls are expected for blocked changes
+	_, err := uc.UpdatePackSizes([]int{100}, "alice")
+	s.Assert().Equal(ErrApprovalRequired, err, "Direct updates should be blocked")
+
+	_, err = uc.RollbackPackSizes(1, "alice")
+	s.Assert().Equal(ErrApprovalRequired, err, "Rollbacks should be blocked")
+}
+
+// TestDomainEvents tests that changes are recorded in the repository's outbox and calculations in their own
+func (s *CalculatePacksUseCaseTestSuite) TestDomainEvents() {
+	repo := repository.NewInMemoryPackRepository([]int{250, 500})
+	calculations := repository.NewInMemoryOutboxRepository(0)
+	uc := NewCalculatePacksUseCase(repo, WithClock(func() time.Time { return s.now }), WithEvents(true), WithCalculationEvents(calculations), WithTenant("retail"))
+
+	_, err := uc.UpdatePackSizes([]int{100, 200}, "alice")
+	s.Require().NoError(err, "Expected no error")
+	_, _, err = uc.Execute(150)
+	s.Require().NoError(err, "Expected no error")
+	_, _, err = uc.Execute(-1)
+	s.Require().Error(err, "Expected invalid order amount")
+	_, err = uc.SchedulePackSizesIfLatest([]int{300}, "bob", time.Time{}, 1) // Based on an outdated version
+	s.Require().ErrorIs(err, repository.ErrVersionConflict, "Expected a conflict")
+
+	events, err := repo.ListPendingEvents(0) // The repository is the outbox of pack-size changes
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(events, 1, "Only the saved change should be in the repository")
+
+	s.Assert().Equal(domain.EventPackSizesUpdated, events[0].Type, "First event should be the pack-size change")
+	s.Assert().Equal(s.now, events[0].OccurredAt, "Event should carry the use case clock")
+	s.Assert().Equal("retail", events[0].Tenant, "Event should name the tenant")
+	var updated domain.PackSizesUpdated
+	s.Require().NoError(json.Unmarshal(events[0].Payload, &updated), "Payload should decode")
+	s.Assert().Equal(2, updated.Version.ID, "Event should name the new version")
+	s.Assert().Equal([]int{100, 200}, updated.Version.PackSizes, "Event should carry the new sizes")
+
+	events, err = calculations.ListPendingEvents(0)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(events, 1, "Failed calculations should not emit events")
+	s.Assert().Equal(domain.EventCalculationPerformed, events[0].Type, "Event should be the calculation")
+	s.Assert().Equal("retail", events[0].Tenant, "Event should name the tenant")
+	s.Assert().JSONEq(`{"orderAmount":150,"packSizeVersion":2,"packs":{"200":1},"totalItems":200}`, string(events[0].Payload))
+}
+
+// TestPackSizesMirror tests that changes are copied to the mirror when they take effect, scheduled ones included
+func (s *CalculatePacksUseCaseTestSuite) TestPackSizesMirror() {
+	var mirrored [][]int
+	var mirrorErr error
+	uc := NewCalculatePacksUseCase(repository.NewInMemoryPackRepository([]int{250, 500}),
+		WithClock(func() time.Time { return s.now }),
+		WithPackSizesMirror(func(packSizes []int) error {
+			mirrored = append(mirrored, packSizes)
+			return mirrorErr
+		}))
+
+	_, err := uc.UpdatePackSizes([]int{200, 100}, "alice")
+	s.Require().NoError(err, "Expected no error")
+	_, err = uc.SchedulePackSizes([]int{300}, "alice", s.now.Add(time.Hour))
+	s.Require().NoError(err, "Expected no error")
+	_, err = uc.RollbackPackSizes(1, "bob")
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([][]int{{100, 200}, {250, 500}}, mirrored, "Only changes in effect should be mirrored")
+
+	// The scheduled change is mirrored once it takes effect, and only once
+	s.Require().NoError(uc.mirrorActive(), "Expected no error")
+	s.Assert().Len(mirrored, 2, "Scheduled change should not be mirrored before it takes effect")
+	s.now = s.now.Add(time.Hour)
+	s.Require().NoError(uc.mirrorActive(), "Expected no error")
+	s.Require().NoError(uc.mirrorActive(), "Expected no error")
+	s.Assert().Equal([]int{300}, mirrored[len(mirrored)-1], "Scheduled change should be mirrored once in effect")
+	s.Assert().Len(mirrored, 3, "Scheduled change should be mirrored once")
+
+	// A failing mirror is reported, but the change is saved
+	mirrorErr = assert.AnError
+	_, err = uc.UpdatePackSizes([]int{7}, "alice")
+	s.Assert().ErrorIs(err, assert.AnError, "Expected the mirror error")
+	sizes, _ := uc.GetPackSizes()
+	s.Assert().Equal([]int{7}, sizes, "Change should be saved anyway")
+}
+
+// TestUpdatePackSizesValidation tests that invalid sets are rejected before reaching the repository
+func (s *CalculatePacksUseCaseTestSuite) TestUpdatePackSizesValidation() {
+	// No repository calls are expected for invalid sets
+	_, err := s.uc.UpdatePackSizes([]int{250, -1}, "alice")
+	s.Assert().IsType(&ValidationError{}, err, "Expected a validation error")
+
+	uc := NewCalculatePacksUseCase(repository.NewInMemoryPackRepository([]int{250}),
+		WithPackSizeRules(PackSizeRules{MaxSizes: 2, MaxSize: 100}))
+	_, err = uc.UpdatePackSizes([]int{50, 150}, "alice")
+	s.Assert().IsType(&ValidationError{}, err, "Configured limits should apply")
+
+	version, err := uc.UpdatePackSizes([]int{50, 20, 50}, "alice")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{20, 50}, version.PackSizes, "Stored sizes should be normalised")
+}
+
+// TestWatchPackSizes tests that versions are served from memory while the repository is watched and
+// that every change, made through the use case or not, is read right after it
+func (s *CalculatePacksUseCaseTestSuite) TestWatchPackSizes() {
+	store := repository.NewInMemoryPackRepository([]int{250, 500})
+	metrics := repository.NewRepositoryMetrics()
+	uc := NewCalculatePacksUseCase(repository.NewMeteredPackRepository(store, metrics, "default.pack_sizes"))
+	calls := func(method string) int64 { return metrics.Snapshot()["default.pack_sizes."+method].Calls }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		uc.WatchPackSizes(ctx) // Finds the store behind the metrics decorator
+		close(done)
+	}()
+	s.Require().Eventually(func() bool {
+		uc.cache.mu.Lock()
+		defer uc.cache.mu.Unlock()
+		return uc.cache.watching
+	}, time.Second, time.Millisecond, "Expected the watch to start")
+
+	// Reads are served from one load
+	for i := 0; i < 3; i++ {
+		sizes, err := uc.GetPackSizes()
+		s.Require().NoError(err, "Expected no error")
+		s.Assert().Equal([]int{250, 500}, sizes, "Seeded sizes should be in effect")
+	}
+	_, _, err := uc.Execute(263)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(int64(1), calls("ListVersions"), "Versions should be loaded once")
+	s.Assert().Zero(calls("GetActiveVersion"), "Active version should be resolved in memory")
+
+	// A change through the use case is read at once
+	_, err = uc.UpdatePackSizes([]int{100, 200}, "alice")
+	s.Require().NoError(err, "Expected no error")
+	sizes, err := uc.GetPackSizes()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, sizes, "Own change should be read right away")
+
+	// A change made elsewhere is read once the repository reports it
+	store.SaveVersion(domain.PackSizeVersion{PackSizes: []int{7}, EffectiveFrom: time.Now()})
+	s.Assert().Eventually(func() bool {
+		sizes, err := uc.GetPackSizes()
+		return err == nil && len(sizes) == 1 && sizes[0] == 7
+	}, time.Second, time.Millisecond, "Reported change should be read")
+	latest, err := uc.LatestPackSizeVersion()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(3, latest, "Newest version should come from memory")
+	versions, err := uc.ListPackSizeVersions()
+	s.Assert().NoError(err, "Expected no error")
+	versions[0].PackSizes[0] = -1
+	first, _ := store.GetVersion(1)
+	s.Assert().Equal([]int{250, 500}, first.PackSizes, "Callers should not change cached versions")
+
+	// Once the watch ends, reads go to the repository again
+	cancel()
+	<-done
+	_, err = uc.GetPackSizes()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(int64(1), calls("GetActiveVersion"), "Reads should go to the repository")
+
+	// Repositories that cannot be watched are read every time
+	s.uc.WatchPackSizes(context.Background()) // Returns at once for the mock
+}
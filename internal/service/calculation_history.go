@@ -0,0 +1,103 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"time" // Import time for measuring replays
+
+	"order-packs-calculator/internal/domain"                    // Import the domain package for calculation records
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for the history store
+)
+
+// CalculationPage is one page of calculation records
+type CalculationPage struct {
+	Calculations []domain.CalculationRecord `json:"calculations"` // Records on this page, newest first
+	Total        int                        `json:"total"`        // Matching records across all pages
+	Offset       int                        `json:"offset"`       // Records skipped before this page
+	Limit        int                        `json:"limit"`        // Maximum records per page
+}
+
+// CalculationReplay compares a past calculation with the same order calculated against the current pack sizes
+type CalculationReplay struct {
+	Original         domain.CalculationRecord `json:"original"`         // Calculation as it was served
+	Replayed         domain.CalculationRecord `json:"replayed"`         // Same order with the pack sizes in effect now
+	Changed          bool                     `json:"changed"`          // Whether the packs, total or error differ
+	PackChanges      map[int]int              `json:"packChanges"`      // Pack size -> change in quantity
+	TotalItemsChange int                      `json:"totalItemsChange"` // Change in total items
+}
+
+// CalculationHistoryService defines the interface for the CalculationHistoryUseCase
+type CalculationHistoryService interface {
+	ListCalculations(filter repository.CalculationFilter) (CalculationPage, error)
+	GetCalculation(id int) (domain.CalculationRecord, error)
+	ReplayCalculation(id int) (CalculationReplay, error)
+}
+
+// CalculationHistoryUseCase queries past calculations and replays them against the current pack sizes
+type CalculationHistoryUseCase struct {
+	history   repository.CalculationRepository // Store the calculations are recorded in
+	catalogue *CalculatePacksUseCase           // Use case that resolves and calculates with pack sizes
+}
+
+// Ensure CalculationHistoryUseCase implements CalculationHistoryService
+var _ CalculationHistoryService = (*CalculationHistoryUseCase)(nil)
+
+// NewCalculationHistoryUseCase creates a new instance of CalculationHistoryUseCase
+func NewCalculationHistoryUseCase(history repository.CalculationRepository, catalogue *CalculatePacksUseCase) *CalculationHistoryUseCase {
+	return &CalculationHistoryUseCase{
+		history:   history,   // Initialize the history store
+		catalogue: catalogue, // Initialize the pack-size catalogue
+	}
+}
+
+// ListCalculations returns a page of matching calculations, newest first; the page size is clamped to MaxPageSize
+func (uc *CalculationHistoryUseCase) ListCalculations(filter repository.CalculationFilter) (CalculationPage, error) {
+	filter.Offset, filter.Limit = pageBounds(filter.Offset, filter.Limit)
+
+	records, total, err := uc.history.ListCalculations(filter)
+	if err != nil {
+		return CalculationPage{}, err
+	}
+	return CalculationPage{Calculations: records, Total: total, Offset: filter.Offset, Limit: filter.Limit}, nil
+}
+
+// GetCalculation retrieves a single calculation by ID
+func (uc *CalculationHistoryUseCase) GetCalculation(id int) (domain.CalculationRecord, error) {
+	return uc.history.GetCalculation(id)
+}
+
+// ReplayCalculation recalculates a past order against the pack sizes in effect now and reports the differences.
+// The replay is served by the catalogue, so it is recorded and reported like any other calculation.
+func (uc *CalculationHistoryUseCase) ReplayCalculation(id int) (CalculationReplay, error) {
+	original, err := uc.history.GetCalculation(id) // Fetch the calculation to replay
+	if err != nil {
+		return CalculationReplay{}, err
+	}
+
+	now := uc.catalogue.Now()
+	start := time.Now()
+	version, packs, totalItems, calcErr := uc.catalogue.CalculateWithActiveVersion(original.OrderAmount)
+	if version.ID == 0 { // The pack sizes in effect could not be resolved, so nothing was calculated
+		return CalculationReplay{}, calcErr
+	}
+	replayed := domain.CalculationRecord{
+		OrderAmount:     original.OrderAmount,
+		PackSizeVersion: version.ID,
+		PackSizes:       version.PackSizes,
+		Packs:           packs,
+		TotalItems:      totalItems,
+		Duration:        time.Since(start),
+		CalculatedAt:    now,
+	}
+	if calcErr != nil { // A failed replay is a result worth reporting, not an error
+		replayed.Error = calcErr.Error()
+	}
+
+	packChanges := domain.DiffPacks(original.Packs, replayed.Packs)
+	totalItemsChange := replayed.TotalItems - original.TotalItems
+	return CalculationReplay{
+		Original:         original,
+		Replayed:         replayed,
+		Changed:          len(packChanges) > 0 || totalItemsChange != 0 || original.Error != replayed.Error,
+		PackChanges:      packChanges,
+		TotalItemsChange: totalItemsChange,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for the fixed clock
+
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for calculation records
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for in-memory storage
+)
+
+// CalculationHistoryUseCaseTestSuite defines the test suite for the calculation history
+type CalculationHistoryUseCaseTestSuite struct {
+	suite.Suite
+	history   *repository.InMemoryCalculationRepository // History the catalogue records to
+	catalogue *CalculatePacksUseCase                    // Catalogue the calculations are served by
+	uc        *CalculationHistoryUseCase                // Use case under test
+	now       time.Time                                 // Fixed clock for the use cases
+}
+
+// SetupTest sets up the test environment before each test
+func (s *CalculationHistoryUseCaseTestSuite) SetupTest() {
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.history = repository.NewInMemoryCalculationRepository(0)
+	s.catalogue = NewCalculatePacksUseCase(repository.NewInMemoryPackRepository([]int{250, 500, 1000}),
+		WithClock(func() time.Time { return s.now }), WithHistory(s.history))
+	s.uc = NewCalculationHistoryUseCase(s.history, s.catalogue)
+}
+
+// TestCalculationHistoryUseCaseTestSuite runs the test suite
+func TestCalculationHistoryUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CalculationHistoryUseCaseTestSuite))
+}
+
+// TestExecuteIsRecorded tests that successful and failed calculations are recorded
+func (s *CalculationHistoryUseCaseTestSuite) TestExecuteIsRecorded() {
+	_, _, err := s.catalogue.Execute(263)
+	s.Require().NoError(err, "Expected no error")
+	_, _, err = s.catalogue.Execute(-1)
+	s.Require().Error(err, "Expected invalid order amount")
+
+	page, err := s.uc.ListCalculations(repository.CalculationFilter{})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(2, page.Total, "Both calculations should be recorded")
+	s.Assert().Equal(DefaultPageSize, page.Limit, "Default page size should apply")
+
+	failed, succeeded := page.Calculations[0], page.Calculations[1]
+	s.Assert().Equal(-1, failed.OrderAmount, "Newest record should come first")
+	s.Assert().NotEmpty(failed.Error, "Failure should be recorded")
+	s.Assert().Equal(domain.CalculationRecord{
+		ID:              1,
+		OrderAmount:     263,
+		PackSizeVersion: 1,
+		PackSizes:       []int{250, 500, 1000},
+		Packs:           map[int]int{500: 1},
+		TotalItems:      500,
+		Duration:        succeeded.Duration,
+		CalculatedAt:    s.now,
+	}, succeeded, "Input, pack sizes and result should be recorded")
+}
+
+// TestReplayCalculation tests recalculating a past order against the current sizes
+func (s *CalculationHistoryUseCaseTestSuite) TestReplayCalculation() {
+	_, _, err := s.catalogue.Execute(263)
+	s.Require().NoError(err, "Expected no error")
+
+	replay, err := s.uc.ReplayCalculation(1)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().False(replay.Changed, "Unchanged sizes should give the same result")
+	s.Assert().Empty(replay.PackChanges, "No pack changes expected")
+
+	_, err = s.catalogue.UpdatePackSizes([]int{100, 300}, "alice")
+	s.Require().NoError(err, "Expected no error")
+
+	replay, err = s.uc.ReplayCalculation(1)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().True(replay.Changed, "New sizes should change the result")
+	s.Assert().Equal(2, replay.Replayed.PackSizeVersion, "Replay should use the current version")
+	s.Assert().Equal(map[int]int{300: 1}, replay.Replayed.Packs, "Replay should recalculate")
+	s.Assert().Equal(map[int]int{300: 1, 500: -1}, replay.PackChanges, "Pack changes should be reported")
+	s.Assert().Equal(-200, replay.TotalItemsChange, "Total change should be reported")
+
+	page, err := s.uc.ListCalculations(repository.CalculationFilter{})
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(3, page.Total, "Replays should be recorded like other calculations")
+	s.Assert().Equal(2, page.Calculations[0].PackSizeVersion, "Newest record should be the last replay")
+
+	_, err = s.uc.ReplayCalculation(99)
+	s.Assert().Equal(repository.ErrCalculationNotFound, err, "Unknown calculations should not be replayed")
+}
@@ -0,0 +1,207 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"errors"  // Import errors for matching validation errors
+	"fmt"     // Import fmt for row messages
+	"sort"    // Import sort for listing changes in order
+	"strconv" // Import strconv for parsing imported pack sizes
+	"strings" // Import strings for trimming imported values
+	"time"    // Import time for the effective date of imports
+
+	"order-packs-calculator/internal/domain" // Import the domain package for pack-size versions
+)
+
+// Catalogue is the full pack-size catalogue of a tenant as it is exported
+type Catalogue struct {
+	Version     int                  `json:"version"`     // Newest pack-size version, the ETag an import must match
+	PackSizes   []int                `json:"packSizes"`   // Pack sizes in effect now, ascending
+	Constraints CatalogueConstraints `json:"constraints"` // Rules imported pack sizes must satisfy; read-only
+}
+
+// CatalogueConstraints are the configured pack-size rules; they are exported for reference and
+// ignored on import, since they come from the configuration
+type CatalogueConstraints struct {
+	MaxPackSizes int `json:"maxPackSizes"` // Maximum number of distinct pack sizes
+	MaxPackSize  int `json:"maxPackSize"`  // Largest allowed pack size
+}
+
+// CatalogueRow is one imported pack size, kept as text so every bad row can be reported
+type CatalogueRow struct {
+	Row      int    // Row number in the imported file, as the user sees it
+	PackSize string // Pack size as written in the file
+}
+
+// RowError describes why an imported row is invalid; row 0 is the file as a whole
+type RowError struct {
+	Row     int    `json:"row"`     // Row number in the imported file
+	Message string `json:"message"` // Human-readable reason
+}
+
+// CatalogueImport reports the outcome of an import, or what it would be for a dry run
+type CatalogueImport struct {
+	DryRun    bool                    `json:"dryRun"`            // Whether nothing was saved
+	Valid     bool                    `json:"valid"`             // Whether every row passed validation
+	Changed   bool                    `json:"changed"`           // Whether the import changes the pack sizes in effect
+	PackSizes []int                   `json:"packSizes"`         // Imported pack sizes, ascending; empty when invalid
+	Added     []int                   `json:"added"`             // Sizes that are not in effect yet
+	Removed   []int                   `json:"removed"`           // Sizes in effect that the import drops
+	Version   *domain.PackSizeVersion `json:"version,omitempty"` // Version saved by the import, if any
+	Errors    []RowError              `json:"errors,omitempty"`  // Why rows were rejected
+}
+
+// CatalogueImportError is returned when an import that is not a dry run has invalid rows; nothing is saved
+type CatalogueImportError struct {
+	Report CatalogueImport
+}
+
+// Error summarises the rejected rows
+func (e *CatalogueImportError) Error() string {
+	return fmt.Sprintf("catalogue import rejected: %d invalid rows", len(e.Report.Errors))
+}
+
+// AnyPackSizeVersion imports a catalogue whatever the newest pack-size version is
+const AnyPackSizeVersion = anyLatestVersion
+
+// CatalogueService defines the interface for the CatalogueUseCase
+type CatalogueService interface {
+	ExportCatalogue() (Catalogue, error)
+	ImportCatalogue(rows []CatalogueRow, author string, dryRun bool, latestID int) (CatalogueImport, error)
+}
+
+// CatalogueUseCase exports the pack-size catalogue and replaces it with an imported one
+type CatalogueUseCase struct {
+	catalogue *CalculatePacksUseCase // Use case that resolves, validates and publishes pack sizes
+}
+
+// Ensure CatalogueUseCase implements CatalogueService
+var _ CatalogueService = (*CatalogueUseCase)(nil)
+
+// NewCatalogueUseCase creates a new instance of CatalogueUseCase
+func NewCatalogueUseCase(catalogue *CalculatePacksUseCase) *CatalogueUseCase {
+	return &CatalogueUseCase{
+		catalogue: catalogue, // Initialize the pack-size catalogue
+	}
+}
+
+// ExportCatalogue returns the pack sizes in effect with the version they must be imported on top of
+func (uc *CatalogueUseCase) ExportCatalogue() (Catalogue, error) {
+	latestID, err := uc.catalogue.LatestPackSizeVersion() // Read the version first, so a racing change makes the import conflict
+	if err != nil {
+		return Catalogue{}, err
+	}
+	packSizes, err := uc.catalogue.GetPackSizes()
+	if err != nil {
+		return Catalogue{}, err
+	}
+	return Catalogue{
+		Version:   latestID,
+		PackSizes: normalisePackSizes(packSizes),
+		Constraints: CatalogueConstraints{
+			MaxPackSizes: uc.catalogue.rules.MaxSizes,
+			MaxPackSize:  uc.catalogue.rules.MaxSize,
+		},
+	}, nil
+}
+
+// ImportCatalogue validates the imported rows and compares them with the pack sizes in effect. Unless
+// dryRun is set, a valid import that changes them is published as a new version that takes effect
+// immediately, only on top of latestID unless it is AnyPackSizeVersion; an invalid one fails with
+// *CatalogueImportError. Importing the sizes in effect saves nothing
+func (uc *CatalogueUseCase) ImportCatalogue(rows []CatalogueRow, author string, dryRun bool, latestID int) (CatalogueImport, error) {
+	report := CatalogueImport{DryRun: dryRun, PackSizes: []int{}, Added: []int{}, Removed: []int{}}
+
+	packSizes, rowErrors := uc.parseRows(rows)
+	if len(rowErrors) == 0 { // Only check the set once every row is fine, so each problem is reported once
+		normalised, err := uc.catalogue.rules.Validate(packSizes)
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			for _, field := range validationErr.Fields {
+				rowErrors = append(rowErrors, RowError{Message: field.Message})
+			}
+		} else if err != nil {
+			return CatalogueImport{}, err
+		}
+		packSizes = normalised
+	}
+	if len(rowErrors) > 0 {
+		report.Errors = rowErrors
+		if dryRun { // A dry run reports the errors instead of failing
+			return report, nil
+		}
+		return CatalogueImport{}, &CatalogueImportError{Report: report}
+	}
+	report.Valid = true
+	report.PackSizes = packSizes
+
+	current, err := uc.catalogue.GetPackSizes() // Compare with the sizes calculations use now
+	if err != nil {
+		return CatalogueImport{}, err
+	}
+	report.Added, report.Removed = diffPackSizes(current, packSizes)
+	report.Changed = len(report.Added) > 0 || len(report.Removed) > 0
+	if dryRun || !report.Changed { // Nothing to save
+		return report, nil
+	}
+
+	version, err := uc.catalogue.schedule(packSizes, author, time.Time{}, latestID) // Zero takes effect immediately
+	if err != nil {
+		return CatalogueImport{}, err
+	}
+	report.Version = &version
+	return report, nil
+}
+
+// parseRows parses the pack size of every row, reporting each row that is not a new positive size
+// within the configured maximum
+func (uc *CatalogueUseCase) parseRows(rows []CatalogueRow) ([]int, []RowError) {
+	var (
+		packSizes []int
+		rowErrors []RowError
+	)
+	seen := make(map[int]int) // Row by pack size, to point duplicates at the first occurrence
+	for _, row := range rows {
+		value := strings.TrimSpace(row.PackSize)
+		size, err := strconv.Atoi(value)
+		firstRow, duplicate := seen[size]
+		switch {
+		case value == "":
+			rowErrors = append(rowErrors, RowError{Row: row.Row, Message: "pack size is required"})
+		case err != nil:
+			rowErrors = append(rowErrors, RowError{Row: row.Row, Message: fmt.Sprintf("pack size %q is not a whole number", value)})
+		case size <= 0:
+			rowErrors = append(rowErrors, RowError{Row: row.Row, Message: "pack size must be positive"})
+		case uc.catalogue.rules.MaxSize > 0 && size > uc.catalogue.rules.MaxSize:
+			rowErrors = append(rowErrors, RowError{Row: row.Row, Message: fmt.Sprintf("pack size must not exceed %d", uc.catalogue.rules.MaxSize)})
+		case duplicate:
+			rowErrors = append(rowErrors, RowError{Row: row.Row, Message: fmt.Sprintf("pack size %d is already on row %d", size, firstRow)})
+		default:
+			seen[size] = row.Row
+			packSizes = append(packSizes, size)
+		}
+	}
+	return packSizes, rowErrors
+}
+
+// diffPackSizes returns the sizes only in next and the sizes only in current, both ascending
+func diffPackSizes(current, next []int) (added, removed []int) {
+	inCurrent := make(map[int]bool, len(current))
+	for _, size := range current {
+		inCurrent[size] = true
+	}
+	inNext := make(map[int]bool, len(next))
+	added, removed = []int{}, []int{}
+	for _, size := range next {
+		inNext[size] = true
+		if !inCurrent[size] {
+			added = append(added, size)
+		}
+	}
+	for size := range inCurrent {
+		if !inNext[size] {
+			removed = append(removed, size)
+		}
+	}
+	sort.Ints(added)
+	sort.Ints(removed)
+	return added, removed
+}
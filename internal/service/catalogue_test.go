@@ -0,0 +1,124 @@
+package service
+
+import (
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for the fixed clock
+
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for in-memory storage
+)
+
+// CatalogueUseCaseTestSuite defines the test suite for the catalogue import and export
+type CatalogueUseCaseTestSuite struct {
+	suite.Suite
+	catalogue *CalculatePacksUseCase // Catalogue the pack sizes are published through
+	uc        *CatalogueUseCase      // Use case under test
+	now       time.Time              // Fixed clock for the use cases
+}
+
+// SetupTest sets up the test environment before each test
+func (s *CatalogueUseCaseTestSuite) SetupTest() {
+	s.now = time.Now() // Imports take effect at once, so the clock must not lag behind the versions
+	s.catalogue = NewCalculatePacksUseCase(repository.NewInMemoryPackRepository([]int{500, 250}),
+		WithClock(func() time.Time { return s.now }),
+		WithPackSizeRules(PackSizeRules{MaxSizes: 3, MaxSize: 1000}))
+	s.uc = NewCatalogueUseCase(s.catalogue)
+}
+
+// TestCatalogueUseCaseTestSuite runs the test suite
+func TestCatalogueUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogueUseCaseTestSuite))
+}
+
+// catalogueRows builds import rows numbered from 2, as below a CSV header
+func catalogueRows(values ...string) []CatalogueRow {
+	result := make([]CatalogueRow, len(values))
+	for i, value := range values {
+		result[i] = CatalogueRow{Row: i + 2, PackSize: value}
+	}
+	return result
+}
+
+// TestExportCatalogue tests that the export carries the sizes in effect, the version and the constraints
+func (s *CatalogueUseCaseTestSuite) TestExportCatalogue() {
+	catalogue, err := s.uc.ExportCatalogue()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(Catalogue{
+		Version:     1,
+		PackSizes:   []int{250, 500},
+		Constraints: CatalogueConstraints{MaxPackSizes: 3, MaxPackSize: 1000},
+	}, catalogue, "Export should match the catalogue")
+}
+
+// TestImportDryRun tests that a dry run reports the change without saving it
+func (s *CatalogueUseCaseTestSuite) TestImportDryRun() {
+	report, err := s.uc.ImportCatalogue(catalogueRows("100", " 500 "), "alice", true, AnyPackSizeVersion)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(CatalogueImport{
+		DryRun: true, Valid: true, Changed: true,
+		PackSizes: []int{100, 500}, Added: []int{100}, Removed: []int{250},
+	}, report, "Report should list the changes")
+
+	latest, _ := s.catalogue.LatestPackSizeVersion()
+	s.Assert().Equal(1, latest, "Dry run should not save a version")
+}
+
+// TestImportRowErrors tests that every invalid row is reported with its row number
+func (s *CatalogueUseCaseTestSuite) TestImportRowErrors() {
+	input := catalogueRows("250", "", "abc", "-5", "5000", "250")
+
+	report, err := s.uc.ImportCatalogue(input, "alice", true, AnyPackSizeVersion)
+	s.Require().NoError(err, "Dry runs should report errors instead of failing")
+	s.Assert().False(report.Valid, "Report should be invalid")
+	s.Assert().Equal([]RowError{
+		{Row: 3, Message: "pack size is required"},
+		{Row: 4, Message: `pack size "abc" is not a whole number`},
+		{Row: 5, Message: "pack size must be positive"},
+		{Row: 6, Message: "pack size must not exceed 1000"},
+		{Row: 7, Message: "pack size 250 is already on row 2"},
+	}, report.Errors, "Every bad row should be reported")
+
+	_, err = s.uc.ImportCatalogue(input, "alice", false, AnyPackSizeVersion)
+	s.Require().IsType(&CatalogueImportError{}, err, "Expected an import error")
+	s.Assert().Len(err.(*CatalogueImportError).Report.Errors, 5, "Error should carry the report")
+
+	// Problems with the set as a whole are reported on row 0
+	report, err = s.uc.ImportCatalogue(catalogueRows("1", "2", "3", "4"), "alice", true, AnyPackSizeVersion)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]RowError{{Row: 0, Message: "must not contain more than 3 distinct sizes"}}, report.Errors, "Set errors should be reported")
+	report, err = s.uc.ImportCatalogue(nil, "alice", true, AnyPackSizeVersion)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal([]RowError{{Row: 0, Message: "at least one pack size is required"}}, report.Errors, "Empty imports should be rejected")
+}
+
+// TestImport tests that an import is saved on top of the version it names
+func (s *CatalogueUseCaseTestSuite) TestImport() {
+	report, err := s.uc.ImportCatalogue(catalogueRows("1000", "100"), "alice", false, 1)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().NotNil(report.Version, "Version should be saved")
+	s.Assert().Equal(2, report.Version.ID, "Import should be the newest version")
+	s.Assert().Equal("alice", report.Version.Author, "Author should be recorded")
+	s.Assert().Equal([]int{100, 1000}, report.PackSizes, "Sizes should be sorted")
+	sizes, _ := s.catalogue.GetPackSizes()
+	s.Assert().Equal([]int{100, 1000}, sizes, "Import should take effect immediately")
+
+	// Importing the sizes in effect saves nothing
+	report, err = s.uc.ImportCatalogue(catalogueRows("100", "1000"), "alice", false, 2)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().False(report.Changed, "Nothing should change")
+	s.Assert().Nil(report.Version, "No version should be saved")
+
+	// An import based on an outdated export conflicts
+	_, err = s.uc.ImportCatalogue(catalogueRows("250"), "bob", false, 1)
+	s.Assert().ErrorIs(err, repository.ErrVersionConflict, "Expected a conflict")
+}
+
+// TestImportApprovalRequired tests that imports are blocked when changes need approval, but dry runs are not
+func (s *CatalogueUseCaseTestSuite) TestImportApprovalRequired() {
+	uc := NewCatalogueUseCase(NewCalculatePacksUseCase(repository.NewInMemoryPackRepository([]int{250}), WithApprovalRequired(true)))
+
+	_, err := uc.ImportCatalogue(catalogueRows("100"), "alice", true, AnyPackSizeVersion)
+	s.Assert().NoError(err, "Dry runs should still report")
+	_, err = uc.ImportCatalogue(catalogueRows("100"), "alice", false, AnyPackSizeVersion)
+	s.Assert().Equal(ErrApprovalRequired, err, "Imports should be blocked")
+}
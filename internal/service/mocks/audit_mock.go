@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/audit.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	repository "order-packs-calculator/internal/infrastructure/repository"
+	service "order-packs-calculator/internal/service"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditService is a mock of AuditService interface.
+type MockAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditServiceMockRecorder
+}
+
+// MockAuditServiceMockRecorder is the mock recorder for MockAuditService.
+type MockAuditServiceMockRecorder struct {
+	mock *MockAuditService
+}
+
+// NewMockAuditService creates a new mock instance.
+func NewMockAuditService(ctrl *gomock.Controller) *MockAuditService {
+	mock := &MockAuditService{ctrl: ctrl}
+	mock.recorder = &MockAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditService) EXPECT() *MockAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// ListAudit mocks base method.
+func (m *MockAuditService) ListAudit(filter repository.AuditFilter) (service.AuditPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudit", filter)
+	ret0, _ := ret[0].(service.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAudit indicates an expected call of ListAudit.
+func (mr *MockAuditServiceMockRecorder) ListAudit(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudit", reflect.TypeOf((*MockAuditService)(nil).ListAudit), filter)
+}
+
+// Record mocks base method.
+func (m *MockAuditService) Record(entry domain.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockAuditServiceMockRecorder) Record(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockAuditService)(nil).Record), entry)
+}
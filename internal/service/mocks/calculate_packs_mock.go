@@ -5,7 +5,9 @@
 package mocks
 
 import (
+	domain "order-packs-calculator/internal/domain"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -49,6 +51,53 @@ func (mr *MockCalculatePacksServiceMockRecorder) Execute(orderAmount interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockCalculatePacksService)(nil).Execute), orderAmount)
 }
 
+// ExecuteAt mocks base method.
+func (m *MockCalculatePacksService) ExecuteAt(orderAmount int, at time.Time) (map[int]int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteAt", orderAmount, at)
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExecuteAt indicates an expected call of ExecuteAt.
+func (mr *MockCalculatePacksServiceMockRecorder) ExecuteAt(orderAmount, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAt", reflect.TypeOf((*MockCalculatePacksService)(nil).ExecuteAt), orderAmount, at)
+}
+
+// ExecuteVersion mocks base method.
+func (m *MockCalculatePacksService) ExecuteVersion(orderAmount, versionID int) (map[int]int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteVersion", orderAmount, versionID)
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExecuteVersion indicates an expected call of ExecuteVersion.
+func (mr *MockCalculatePacksServiceMockRecorder) ExecuteVersion(orderAmount, versionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteVersion", reflect.TypeOf((*MockCalculatePacksService)(nil).ExecuteVersion), orderAmount, versionID)
+}
+
+// GetPackSizeVersion mocks base method.
+func (m *MockCalculatePacksService) GetPackSizeVersion(id int) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPackSizeVersion", id)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPackSizeVersion indicates an expected call of GetPackSizeVersion.
+func (mr *MockCalculatePacksServiceMockRecorder) GetPackSizeVersion(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackSizeVersion", reflect.TypeOf((*MockCalculatePacksService)(nil).GetPackSizeVersion), id)
+}
+
 // GetPackSizes mocks base method.
 func (m *MockCalculatePacksService) GetPackSizes() ([]int, error) {
 	m.ctrl.T.Helper()
@@ -64,16 +113,122 @@ func (mr *MockCalculatePacksServiceMockRecorder) GetPackSizes() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackSizes", reflect.TypeOf((*MockCalculatePacksService)(nil).GetPackSizes))
 }
 
+// LatestPackSizeVersion mocks base method.
+func (m *MockCalculatePacksService) LatestPackSizeVersion() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestPackSizeVersion")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestPackSizeVersion indicates an expected call of LatestPackSizeVersion.
+func (mr *MockCalculatePacksServiceMockRecorder) LatestPackSizeVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestPackSizeVersion", reflect.TypeOf((*MockCalculatePacksService)(nil).LatestPackSizeVersion))
+}
+
+// ListPackSizeVersions mocks base method.
+func (m *MockCalculatePacksService) ListPackSizeVersions() ([]domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPackSizeVersions")
+	ret0, _ := ret[0].([]domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPackSizeVersions indicates an expected call of ListPackSizeVersions.
+func (mr *MockCalculatePacksServiceMockRecorder) ListPackSizeVersions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPackSizeVersions", reflect.TypeOf((*MockCalculatePacksService)(nil).ListPackSizeVersions))
+}
+
+// ListScheduledPackSizes mocks base method.
+func (m *MockCalculatePacksService) ListScheduledPackSizes() ([]domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduledPackSizes")
+	ret0, _ := ret[0].([]domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduledPackSizes indicates an expected call of ListScheduledPackSizes.
+func (mr *MockCalculatePacksServiceMockRecorder) ListScheduledPackSizes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduledPackSizes", reflect.TypeOf((*MockCalculatePacksService)(nil).ListScheduledPackSizes))
+}
+
+// RollbackPackSizes mocks base method.
+func (m *MockCalculatePacksService) RollbackPackSizes(versionID int, author string) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackPackSizes", versionID, author)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackPackSizes indicates an expected call of RollbackPackSizes.
+func (mr *MockCalculatePacksServiceMockRecorder) RollbackPackSizes(versionID, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackPackSizes", reflect.TypeOf((*MockCalculatePacksService)(nil).RollbackPackSizes), versionID, author)
+}
+
+// RollbackPackSizesIfLatest mocks base method.
+func (m *MockCalculatePacksService) RollbackPackSizesIfLatest(versionID int, author string, latestID int) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackPackSizesIfLatest", versionID, author, latestID)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackPackSizesIfLatest indicates an expected call of RollbackPackSizesIfLatest.
+func (mr *MockCalculatePacksServiceMockRecorder) RollbackPackSizesIfLatest(versionID, author, latestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackPackSizesIfLatest", reflect.TypeOf((*MockCalculatePacksService)(nil).RollbackPackSizesIfLatest), versionID, author, latestID)
+}
+
+// SchedulePackSizes mocks base method.
+func (m *MockCalculatePacksService) SchedulePackSizes(newSizes []int, author string, effectiveFrom time.Time) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePackSizes", newSizes, author, effectiveFrom)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePackSizes indicates an expected call of SchedulePackSizes.
+func (mr *MockCalculatePacksServiceMockRecorder) SchedulePackSizes(newSizes, author, effectiveFrom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePackSizes", reflect.TypeOf((*MockCalculatePacksService)(nil).SchedulePackSizes), newSizes, author, effectiveFrom)
+}
+
+// SchedulePackSizesIfLatest mocks base method.
+func (m *MockCalculatePacksService) SchedulePackSizesIfLatest(newSizes []int, author string, effectiveFrom time.Time, latestID int) (domain.PackSizeVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePackSizesIfLatest", newSizes, author, effectiveFrom, latestID)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePackSizesIfLatest indicates an expected call of SchedulePackSizesIfLatest.
+func (mr *MockCalculatePacksServiceMockRecorder) SchedulePackSizesIfLatest(newSizes, author, effectiveFrom, latestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePackSizesIfLatest", reflect.TypeOf((*MockCalculatePacksService)(nil).SchedulePackSizesIfLatest), newSizes, author, effectiveFrom, latestID)
+}
+
 // UpdatePackSizes mocks base method.
-func (m *MockCalculatePacksService) UpdatePackSizes(newSizes []int) error {
+func (m *MockCalculatePacksService) UpdatePackSizes(newSizes []int, author string) (domain.PackSizeVersion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePackSizes", newSizes)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdatePackSizes", newSizes, author)
+	ret0, _ := ret[0].(domain.PackSizeVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdatePackSizes indicates an expected call of UpdatePackSizes.
-func (mr *MockCalculatePacksServiceMockRecorder) UpdatePackSizes(newSizes interface{}) *gomock.Call {
+func (mr *MockCalculatePacksServiceMockRecorder) UpdatePackSizes(newSizes, author interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePackSizes", reflect.TypeOf((*MockCalculatePacksService)(nil).UpdatePackSizes), newSizes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePackSizes", reflect.TypeOf((*MockCalculatePacksService)(nil).UpdatePackSizes), newSizes, author)
 }
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/calculation_history.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	repository "order-packs-calculator/internal/infrastructure/repository"
+	service "order-packs-calculator/internal/service"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCalculationHistoryService is a mock of CalculationHistoryService interface.
+type MockCalculationHistoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalculationHistoryServiceMockRecorder
+}
+
+// MockCalculationHistoryServiceMockRecorder is the mock recorder for MockCalculationHistoryService.
+type MockCalculationHistoryServiceMockRecorder struct {
+	mock *MockCalculationHistoryService
+}
+
+// NewMockCalculationHistoryService creates a new mock instance.
+func NewMockCalculationHistoryService(ctrl *gomock.Controller) *MockCalculationHistoryService {
+	mock := &MockCalculationHistoryService{ctrl: ctrl}
+	mock.recorder = &MockCalculationHistoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalculationHistoryService) EXPECT() *MockCalculationHistoryServiceMockRecorder {
+	return m.recorder
+}
+
+// GetCalculation mocks base method.
+func (m *MockCalculationHistoryService) GetCalculation(id int) (domain.CalculationRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalculation", id)
+	ret0, _ := ret[0].(domain.CalculationRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalculation indicates an expected call of GetCalculation.
+func (mr *MockCalculationHistoryServiceMockRecorder) GetCalculation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalculation", reflect.TypeOf((*MockCalculationHistoryService)(nil).GetCalculation), id)
+}
+
+// ListCalculations mocks base method.
+func (m *MockCalculationHistoryService) ListCalculations(filter repository.CalculationFilter) (service.CalculationPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCalculations", filter)
+	ret0, _ := ret[0].(service.CalculationPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCalculations indicates an expected call of ListCalculations.
+func (mr *MockCalculationHistoryServiceMockRecorder) ListCalculations(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCalculations", reflect.TypeOf((*MockCalculationHistoryService)(nil).ListCalculations), filter)
+}
+
+// ReplayCalculation mocks base method.
+func (m *MockCalculationHistoryService) ReplayCalculation(id int) (service.CalculationReplay, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayCalculation", id)
+	ret0, _ := ret[0].(service.CalculationReplay)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayCalculation indicates an expected call of ReplayCalculation.
+func (mr *MockCalculationHistoryServiceMockRecorder) ReplayCalculation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayCalculation", reflect.TypeOf((*MockCalculationHistoryService)(nil).ReplayCalculation), id)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/catalogue.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	service "order-packs-calculator/internal/service"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCatalogueService is a mock of CatalogueService interface.
+type MockCatalogueService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCatalogueServiceMockRecorder
+}
+
+// MockCatalogueServiceMockRecorder is the mock recorder for MockCatalogueService.
+type MockCatalogueServiceMockRecorder struct {
+	mock *MockCatalogueService
+}
+
+// NewMockCatalogueService creates a new mock instance.
+func NewMockCatalogueService(ctrl *gomock.Controller) *MockCatalogueService {
+	mock := &MockCatalogueService{ctrl: ctrl}
+	mock.recorder = &MockCatalogueServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCatalogueService) EXPECT() *MockCatalogueServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportCatalogue mocks base method.
+func (m *MockCatalogueService) ExportCatalogue() (service.Catalogue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCatalogue")
+	ret0, _ := ret[0].(service.Catalogue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportCatalogue indicates an expected call of ExportCatalogue.
+func (mr *MockCatalogueServiceMockRecorder) ExportCatalogue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCatalogue", reflect.TypeOf((*MockCatalogueService)(nil).ExportCatalogue))
+}
+
+// ImportCatalogue mocks base method.
+func (m *MockCatalogueService) ImportCatalogue(rows []service.CatalogueRow, author string, dryRun bool, latestID int) (service.CatalogueImport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCatalogue", rows, author, dryRun, latestID)
+	ret0, _ := ret[0].(service.CatalogueImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCatalogue indicates an expected call of ImportCatalogue.
+func (mr *MockCatalogueServiceMockRecorder) ImportCatalogue(rows, author, dryRun, latestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCatalogue", reflect.TypeOf((*MockCatalogueService)(nil).ImportCatalogue), rows, author, dryRun, latestID)
+}
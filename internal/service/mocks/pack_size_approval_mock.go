@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/pack_size_approval.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	service "order-packs-calculator/internal/service"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPackSizeApprovalService is a mock of PackSizeApprovalService interface.
+type MockPackSizeApprovalService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPackSizeApprovalServiceMockRecorder
+}
+
+// MockPackSizeApprovalServiceMockRecorder is the mock recorder for MockPackSizeApprovalService.
+type MockPackSizeApprovalServiceMockRecorder struct {
+	mock *MockPackSizeApprovalService
+}
+
+// NewMockPackSizeApprovalService creates a new mock instance.
+func NewMockPackSizeApprovalService(ctrl *gomock.Controller) *MockPackSizeApprovalService {
+	mock := &MockPackSizeApprovalService{ctrl: ctrl}
+	mock.recorder = &MockPackSizeApprovalServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPackSizeApprovalService) EXPECT() *MockPackSizeApprovalServiceMockRecorder {
+	return m.recorder
+}
+
+// ApproveProposal mocks base method.
+func (m *MockPackSizeApprovalService) ApproveProposal(id int, reviewer string) (domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveProposal", id, reviewer)
+	ret0, _ := ret[0].(domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveProposal indicates an expected call of ApproveProposal.
+func (mr *MockPackSizeApprovalServiceMockRecorder) ApproveProposal(id, reviewer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveProposal", reflect.TypeOf((*MockPackSizeApprovalService)(nil).ApproveProposal), id, reviewer)
+}
+
+// CreateProposal mocks base method.
+func (m *MockPackSizeApprovalService) CreateProposal(packSizes []int, effectiveFrom *time.Time, author string) (domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProposal", packSizes, effectiveFrom, author)
+	ret0, _ := ret[0].(domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProposal indicates an expected call of CreateProposal.
+func (mr *MockPackSizeApprovalServiceMockRecorder) CreateProposal(packSizes, effectiveFrom, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProposal", reflect.TypeOf((*MockPackSizeApprovalService)(nil).CreateProposal), packSizes, effectiveFrom, author)
+}
+
+// GetProposal mocks base method.
+func (m *MockPackSizeApprovalService) GetProposal(id int) (domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProposal", id)
+	ret0, _ := ret[0].(domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProposal indicates an expected call of GetProposal.
+func (mr *MockPackSizeApprovalServiceMockRecorder) GetProposal(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProposal", reflect.TypeOf((*MockPackSizeApprovalService)(nil).GetProposal), id)
+}
+
+// ListProposals mocks base method.
+func (m *MockPackSizeApprovalService) ListProposals(status domain.ProposalStatus) ([]domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProposals", status)
+	ret0, _ := ret[0].([]domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProposals indicates an expected call of ListProposals.
+func (mr *MockPackSizeApprovalServiceMockRecorder) ListProposals(status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProposals", reflect.TypeOf((*MockPackSizeApprovalService)(nil).ListProposals), status)
+}
+
+// PreviewProposal mocks base method.
+func (m *MockPackSizeApprovalService) PreviewProposal(id int, orderAmounts []int) ([]service.ProposalPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewProposal", id, orderAmounts)
+	ret0, _ := ret[0].([]service.ProposalPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewProposal indicates an expected call of PreviewProposal.
+func (mr *MockPackSizeApprovalServiceMockRecorder) PreviewProposal(id, orderAmounts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewProposal", reflect.TypeOf((*MockPackSizeApprovalService)(nil).PreviewProposal), id, orderAmounts)
+}
+
+// RejectProposal mocks base method.
+func (m *MockPackSizeApprovalService) RejectProposal(id int, reviewer, reason string) (domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectProposal", id, reviewer, reason)
+	ret0, _ := ret[0].(domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectProposal indicates an expected call of RejectProposal.
+func (mr *MockPackSizeApprovalServiceMockRecorder) RejectProposal(id, reviewer, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectProposal", reflect.TypeOf((*MockPackSizeApprovalService)(nil).RejectProposal), id, reviewer, reason)
+}
+
+// SubmitProposal mocks base method.
+func (m *MockPackSizeApprovalService) SubmitProposal(id int) (domain.PackSizeProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitProposal", id)
+	ret0, _ := ret[0].(domain.PackSizeProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitProposal indicates an expected call of SubmitProposal.
+func (mr *MockPackSizeApprovalServiceMockRecorder) SubmitProposal(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitProposal", reflect.TypeOf((*MockPackSizeApprovalService)(nil).SubmitProposal), id)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/quote.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuoteService is a mock of QuoteService interface.
+type MockQuoteService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteServiceMockRecorder
+}
+
+// MockQuoteServiceMockRecorder is the mock recorder for MockQuoteService.
+type MockQuoteServiceMockRecorder struct {
+	mock *MockQuoteService
+}
+
+// NewMockQuoteService creates a new mock instance.
+func NewMockQuoteService(ctrl *gomock.Controller) *MockQuoteService {
+	mock := &MockQuoteService{ctrl: ctrl}
+	mock.recorder = &MockQuoteServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteService) EXPECT() *MockQuoteServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateQuote mocks base method.
+func (m *MockQuoteService) CreateQuote(orderAmount int, author string) (domain.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQuote", orderAmount, author)
+	ret0, _ := ret[0].(domain.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateQuote indicates an expected call of CreateQuote.
+func (mr *MockQuoteServiceMockRecorder) CreateQuote(orderAmount, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuote", reflect.TypeOf((*MockQuoteService)(nil).CreateQuote), orderAmount, author)
+}
+
+// GetQuote mocks base method.
+func (m *MockQuoteService) GetQuote(id string) (domain.Quote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuote", id)
+	ret0, _ := ret[0].(domain.Quote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuote indicates an expected call of GetQuote.
+func (mr *MockQuoteServiceMockRecorder) GetQuote(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuote", reflect.TypeOf((*MockQuoteService)(nil).GetQuote), id)
+}
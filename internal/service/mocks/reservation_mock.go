@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/reservation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReservationService is a mock of ReservationService interface.
+type MockReservationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationServiceMockRecorder
+}
+
+// MockReservationServiceMockRecorder is the mock recorder for MockReservationService.
+type MockReservationServiceMockRecorder struct {
+	mock *MockReservationService
+}
+
+// NewMockReservationService creates a new mock instance.
+func NewMockReservationService(ctrl *gomock.Controller) *MockReservationService {
+	mock := &MockReservationService{ctrl: ctrl}
+	mock.recorder = &MockReservationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservationService) EXPECT() *MockReservationServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelReservation mocks base method.
+func (m *MockReservationService) CancelReservation(id, actor string) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelReservation", id, actor)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelReservation indicates an expected call of CancelReservation.
+func (mr *MockReservationServiceMockRecorder) CancelReservation(id, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReservation", reflect.TypeOf((*MockReservationService)(nil).CancelReservation), id, actor)
+}
+
+// ConfirmReservation mocks base method.
+func (m *MockReservationService) ConfirmReservation(id, actor string) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmReservation", id, actor)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmReservation indicates an expected call of ConfirmReservation.
+func (mr *MockReservationServiceMockRecorder) ConfirmReservation(id, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReservation", reflect.TypeOf((*MockReservationService)(nil).ConfirmReservation), id, actor)
+}
+
+// GetReservation mocks base method.
+func (m *MockReservationService) GetReservation(id string) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservation", id)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservation indicates an expected call of GetReservation.
+func (mr *MockReservationServiceMockRecorder) GetReservation(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservation", reflect.TypeOf((*MockReservationService)(nil).GetReservation), id)
+}
+
+// GetStock mocks base method.
+func (m *MockReservationService) GetStock() (map[int]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStock")
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStock indicates an expected call of GetStock.
+func (mr *MockReservationServiceMockRecorder) GetStock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStock", reflect.TypeOf((*MockReservationService)(nil).GetStock))
+}
+
+// ListReservations mocks base method.
+func (m *MockReservationService) ListReservations(status domain.ReservationStatus) ([]domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReservations", status)
+	ret0, _ := ret[0].([]domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReservations indicates an expected call of ListReservations.
+func (mr *MockReservationServiceMockRecorder) ListReservations(status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReservations", reflect.TypeOf((*MockReservationService)(nil).ListReservations), status)
+}
+
+// Reserve mocks base method.
+func (m *MockReservationService) Reserve(orderAmount int, author string) (domain.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", orderAmount, author)
+	ret0, _ := ret[0].(domain.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockReservationServiceMockRecorder) Reserve(orderAmount, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockReservationService)(nil).Reserve), orderAmount, author)
+}
+
+// SetStock mocks base method.
+func (m *MockReservationService) SetStock(stock map[int]int) (map[int]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStock", stock)
+	ret0, _ := ret[0].(map[int]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStock indicates an expected call of SetStock.
+func (mr *MockReservationServiceMockRecorder) SetStock(stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStock", reflect.TypeOf((*MockReservationService)(nil).SetStock), stock)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/snapshot.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	service "order-packs-calculator/internal/service"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSnapshotService is a mock of SnapshotService interface.
+type MockSnapshotService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotServiceMockRecorder
+}
+
+// MockSnapshotServiceMockRecorder is the mock recorder for MockSnapshotService.
+type MockSnapshotServiceMockRecorder struct {
+	mock *MockSnapshotService
+}
+
+// NewMockSnapshotService creates a new mock instance.
+func NewMockSnapshotService(ctrl *gomock.Controller) *MockSnapshotService {
+	mock := &MockSnapshotService{ctrl: ctrl}
+	mock.recorder = &MockSnapshotServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshotService) EXPECT() *MockSnapshotServiceMockRecorder {
+	return m.recorder
+}
+
+// Restore mocks base method.
+func (m *MockSnapshotService) Restore(snapshot service.Snapshot) (map[string]service.RestoredTenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", snapshot)
+	ret0, _ := ret[0].(map[string]service.RestoredTenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockSnapshotServiceMockRecorder) Restore(snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockSnapshotService)(nil).Restore), snapshot)
+}
+
+// Snapshot mocks base method.
+func (m *MockSnapshotService) Snapshot() (service.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(service.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockSnapshotServiceMockRecorder) Snapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockSnapshotService)(nil).Snapshot))
+}
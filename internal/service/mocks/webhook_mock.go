@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/webhook.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "order-packs-calculator/internal/domain"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookService is a mock of WebhookService interface.
+type MockWebhookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceMockRecorder
+}
+
+// MockWebhookServiceMockRecorder is the mock recorder for MockWebhookService.
+type MockWebhookServiceMockRecorder struct {
+	mock *MockWebhookService
+}
+
+// NewMockWebhookService creates a new mock instance.
+func NewMockWebhookService(ctrl *gomock.Controller) *MockWebhookService {
+	mock := &MockWebhookService{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
+	return m.recorder
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockWebhookService) ListWebhookDeliveries(id int) ([]domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", id)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockWebhookServiceMockRecorder) ListWebhookDeliveries(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockWebhookService)(nil).ListWebhookDeliveries), id)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookService) ListWebhooks() ([]domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks")
+	ret0, _ := ret[0].([]domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookServiceMockRecorder) ListWebhooks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookService)(nil).ListWebhooks))
+}
+
+// RegisterWebhook mocks base method.
+func (m *MockWebhookService) RegisterWebhook(url string, eventTypes []string, author string) (domain.WebhookSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterWebhook", url, eventTypes, author)
+	ret0, _ := ret[0].(domain.WebhookSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterWebhook indicates an expected call of RegisterWebhook.
+func (mr *MockWebhookServiceMockRecorder) RegisterWebhook(url, eventTypes, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterWebhook", reflect.TypeOf((*MockWebhookService)(nil).RegisterWebhook), url, eventTypes, author)
+}
+
+// UnregisterWebhook mocks base method.
+func (m *MockWebhookService) UnregisterWebhook(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnregisterWebhook", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnregisterWebhook indicates an expected call of UnregisterWebhook.
+func (mr *MockWebhookServiceMockRecorder) UnregisterWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnregisterWebhook", reflect.TypeOf((*MockWebhookService)(nil).UnregisterWebhook), id)
+}
@@ -0,0 +1,68 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"context" // Import context for stopping the relay loop
+	"time"    // Import time for the relay interval
+
+	"order-packs-calculator/internal/infrastructure/events"     // Import the events package for publishers
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for the outbox
+)
+
+// DefaultOutboxBatchSize is how many events the relay publishes per pass
+const DefaultOutboxBatchSize = 100
+
+// OutboxRelay delivers events from the outbox to a publisher
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository // Outbox holding pending events
+	publisher events.Publisher            // Publisher the events are delivered to
+	onError   func(error)                 // Called when a relay pass fails
+}
+
+// NewOutboxRelay creates a new instance of OutboxRelay; onError may be nil
+func NewOutboxRelay(outbox repository.OutboxRepository, publisher events.Publisher, onError func(error)) *OutboxRelay {
+	if onError == nil { // Ignore errors when nobody wants to hear about them
+		onError = func(error) {}
+	}
+	return &OutboxRelay{
+		outbox:    outbox,    // Initialize the outbox
+		publisher: publisher, // Initialize the publisher
+		onError:   onError,   // Initialize the error callback
+	}
+}
+
+// RelayPending publishes pending events in order and returns how many were delivered.
+// It stops at the first failure so events are never delivered out of order; the failed
+// event stays in the outbox and is retried on the next pass.
+func (r *OutboxRelay) RelayPending() (int, error) {
+	pending, err := r.outbox.ListPendingEvents(DefaultOutboxBatchSize) // Fetch the oldest pending events
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range pending {
+		if err := r.publisher.Publish(event); err != nil { // Keep the event for the next pass
+			return i, err
+		}
+		if err := r.outbox.MarkEventPublished(event.ID); err != nil {
+			return i, err
+		}
+	}
+	return len(pending), nil
+}
+
+// Run relays pending events every interval until the context is cancelled
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayPending(); err != nil {
+				r.onError(err)
+			}
+		}
+	}
+}
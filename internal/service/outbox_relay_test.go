@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"  // Import errors for simulated publisher failures
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for event timestamps
+
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for events
+	"order-packs-calculator/internal/infrastructure/events"     // Import the events package for publishers
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for the outbox
+)
+
+// OutboxRelayTestSuite defines the test suite for the outbox relay
+type OutboxRelayTestSuite struct {
+	suite.Suite
+	outbox    *repository.InMemoryOutboxRepository // Outbox the relay reads from
+	published []domain.EventEnvelope               // Events the publisher accepted
+	failOn    int                                  // Event ID the publisher rejects, if any
+	relay     *OutboxRelay                         // Relay under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *OutboxRelayTestSuite) SetupTest() {
+	s.outbox = repository.NewInMemoryOutboxRepository(0)
+	s.published = nil
+	s.failOn = 0
+	s.relay = NewOutboxRelay(s.outbox, events.PublisherFunc(func(event domain.EventEnvelope) error {
+		if event.ID == s.failOn {
+			return errors.New("publisher unavailable")
+		}
+		s.published = append(s.published, event)
+		return nil
+	}), nil)
+
+	for i := 0; i < 3; i++ { // Queue three events
+		envelope, err := domain.NewEventEnvelope(domain.CalculationPerformed{OrderAmount: i}, time.Now())
+		s.Require().NoError(err, "Expected no error")
+		_, err = s.outbox.AppendEvent(envelope)
+		s.Require().NoError(err, "Expected no error")
+	}
+}
+
+// TestOutboxRelayTestSuite runs the test suite
+func TestOutboxRelayTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxRelayTestSuite))
+}
+
+// TestRelayPending tests that pending events are delivered once, in order
+func (s *OutboxRelayTestSuite) TestRelayPending() {
+	delivered, err := s.relay.RelayPending()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(3, delivered, "All events should be delivered")
+	s.Require().Len(s.published, 3, "All events should reach the publisher")
+	s.Assert().Equal(1, s.published[0].ID, "Events should be delivered oldest first")
+
+	delivered, err = s.relay.RelayPending()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(0, delivered, "Published events should not be delivered again")
+}
+
+// TestRelayPendingStopsOnFailure tests that a failed event is kept and blocks later ones
+func (s *OutboxRelayTestSuite) TestRelayPendingStopsOnFailure() {
+	s.failOn = 2
+
+	delivered, err := s.relay.RelayPending()
+	s.Assert().Error(err, "Expected publisher error")
+	s.Assert().Equal(1, delivered, "Only events before the failure should be delivered")
+
+	pending, err := s.outbox.ListPendingEvents(0)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(pending, 2, "Failed and later events should stay in the outbox")
+	s.Assert().Equal(2, pending[0].ID, "Failed event should be retried first")
+
+	s.failOn = 0
+	delivered, err = s.relay.RelayPending()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(2, delivered, "Remaining events should be delivered on retry")
+}
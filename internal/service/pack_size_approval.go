@@ -0,0 +1,260 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"errors" // Import errors for workflow errors
+	"sync"   // Import sync for serialising state transitions
+	"time"   // Import time for lifecycle timestamps
+
+	"order-packs-calculator/internal/domain"                    // Import the domain package for proposals
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for proposal storage
+)
+
+var (
+	// ErrInvalidProposalTransition is returned when a proposal is not in a state that allows the action
+	ErrInvalidProposalTransition = errors.New("proposal is not in a state that allows this action")
+	// ErrSelfApproval is returned when the author of a proposal tries to approve it
+	ErrSelfApproval = errors.New("a proposal must be approved by a user other than its author")
+	// ErrRejectionReasonRequired is returned when a proposal is rejected without a reason
+	ErrRejectionReasonRequired = errors.New("a rejection reason is required")
+	// ErrNoOrderAmounts is returned when a preview is requested without any order amounts
+	ErrNoOrderAmounts = errors.New("at least one order amount is required for a preview")
+	// ErrProposalExpired is returned when a proposal is approved after its effective date; the proposal is closed
+	ErrProposalExpired = errors.New("the proposal's effective date passed while it was in review; propose the change again")
+	// ErrProposalSuperseded is returned when the pack sizes changed after a proposal was created; the proposal is closed
+	ErrProposalSuperseded = errors.New("the pack sizes changed after the proposal was created; propose the change again")
+)
+
+// PackSizeApprovalService defines the interface for the PackSizeApprovalUseCase
+type PackSizeApprovalService interface {
+	CreateProposal(packSizes []int, effectiveFrom *time.Time, author string) (domain.PackSizeProposal, error)
+	SubmitProposal(id int) (domain.PackSizeProposal, error)
+	ApproveProposal(id int, reviewer string) (domain.PackSizeProposal, error)
+	RejectProposal(id int, reviewer string, reason string) (domain.PackSizeProposal, error)
+	PreviewProposal(id int, orderAmounts []int) ([]ProposalPreview, error)
+	GetProposal(id int) (domain.PackSizeProposal, error)
+	ListProposals(status domain.ProposalStatus) ([]domain.PackSizeProposal, error)
+}
+
+// PackCalculation is the outcome of calculating one order against one set of pack sizes
+type PackCalculation struct {
+	Packs      map[int]int `json:"packs,omitempty"` // Pack size -> quantity
+	TotalItems int         `json:"totalItems"`      // Total items fulfilled
+	Error      string      `json:"error,omitempty"` // Why the calculation failed, if it did
+}
+
+// ProposalPreview compares the current and proposed pack sizes for one order amount
+type ProposalPreview struct {
+	OrderAmount int             `json:"orderAmount"` // Order amount that was calculated
+	Current     PackCalculation `json:"current"`     // Result with the pack sizes in effect now
+	Proposed    PackCalculation `json:"proposed"`    // Result with the proposed pack sizes
+}
+
+// PackSizeApprovalUseCase runs the draft -> review -> published lifecycle for pack-size changes
+type PackSizeApprovalUseCase struct {
+	proposals repository.ProposalRepository // Repository for proposals
+	catalogue *CalculatePacksUseCase        // Use case that calculates with and publishes pack sizes
+
+	mu sync.Mutex // Serialises state transitions, so a proposal is published at most once
+}
+
+// Ensure PackSizeApprovalUseCase implements PackSizeApprovalService
+var _ PackSizeApprovalService = (*PackSizeApprovalUseCase)(nil)
+
+// NewPackSizeApprovalUseCase creates a new instance of PackSizeApprovalUseCase
+func NewPackSizeApprovalUseCase(proposals repository.ProposalRepository, catalogue *CalculatePacksUseCase) *PackSizeApprovalUseCase {
+	return &PackSizeApprovalUseCase{
+		proposals: proposals, // Initialize the proposal repository
+		catalogue: catalogue, // Initialize the pack-size catalogue
+	}
+}
+
+// CreateProposal stores a new draft proposal
+func (uc *PackSizeApprovalUseCase) CreateProposal(packSizes []int, effectiveFrom *time.Time, author string) (domain.PackSizeProposal, error) {
+	now := uc.catalogue.now()
+	if effectiveFrom != nil && effectiveFrom.Before(now) { // Reject dates that could never be published
+		return domain.PackSizeProposal{}, ErrEffectiveFromInPast
+	}
+	packSizes, err := uc.catalogue.rules.Validate(packSizes) // Proposals follow the same rules as direct changes
+	if err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+	base, err := uc.catalogue.LatestPackSizeVersion() // The version the proposal changes, checked again on approval
+	if err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+
+	return uc.proposals.CreateProposal(domain.PackSizeProposal{
+		PackSizes:     packSizes,
+		EffectiveFrom: effectiveFrom,
+		Author:        author,
+		BaseVersion:   base,
+		Status:        domain.ProposalDraft,
+		CreatedAt:     now,
+	})
+}
+
+// SubmitProposal sends a draft for review
+func (uc *PackSizeApprovalUseCase) SubmitProposal(id int) (domain.PackSizeProposal, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	proposal, err := uc.proposals.GetProposal(id) // Fetch the proposal to submit
+	if err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+	if proposal.Status != domain.ProposalDraft { // Only drafts can be submitted
+		return domain.PackSizeProposal{}, ErrInvalidProposalTransition
+	}
+
+	now := uc.catalogue.now()
+	proposal.Status = domain.ProposalInReview
+	proposal.SubmittedAt = &now
+	if err := uc.proposals.UpdateProposal(proposal); err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+	return proposal, nil
+}
+
+// ApproveProposal publishes a proposal under review as a new pack-size version. A proposal that can no
+// longer be published, because its effective date passed or the pack sizes changed since it was created,
+// is closed as expired or superseded instead
+func (uc *PackSizeApprovalUseCase) ApproveProposal(id int, reviewer string) (domain.PackSizeProposal, error) {
+	uc.mu.Lock() // Held until the proposal is updated, so two approvals cannot both publish
+	defer uc.mu.Unlock()
+
+	proposal, err := uc.proposals.GetProposal(id) // Fetch the proposal to approve
+	if err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+	if proposal.Status != domain.ProposalInReview { // Only proposals under review can be approved
+		return domain.PackSizeProposal{}, ErrInvalidProposalTransition
+	}
+	if reviewer == proposal.Author { // The four-eyes principle needs a second user
+		return domain.PackSizeProposal{}, ErrSelfApproval
+	}
+
+	version := domain.PackSizeVersion{
+		PackSizes:  proposal.PackSizes,
+		Author:     proposal.Author,
+		ApprovedBy: reviewer,
+	}
+	if proposal.EffectiveFrom != nil { // Keep the requested schedule, otherwise apply immediately
+		version.EffectiveFrom = *proposal.EffectiveFrom
+	}
+	// Only now do the sizes reach calculations, and only on top of the version the author saw
+	published, err := uc.catalogue.publish(version, proposal.BaseVersion)
+	switch {
+	case errors.Is(err, ErrEffectiveFromInPast): // Retrying cannot help, so close the proposal
+		return domain.PackSizeProposal{}, uc.close(proposal, domain.ProposalExpired, reviewer, ErrProposalExpired)
+	case errors.Is(err, repository.ErrVersionConflict):
+		return domain.PackSizeProposal{}, uc.close(proposal, domain.ProposalSuperseded, reviewer, ErrProposalSuperseded)
+	case err != nil:
+		return domain.PackSizeProposal{}, err
+	}
+
+	now := uc.catalogue.now()
+	proposal.Status = domain.ProposalPublished
+	proposal.ReviewedBy = reviewer
+	proposal.ReviewedAt = &now
+	proposal.PublishedVersion = published.ID
+	if err := uc.proposals.UpdateProposal(proposal); err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+	return proposal, nil
+}
+
+// RejectProposal turns down a proposal under review, recording the reason
+func (uc *PackSizeApprovalUseCase) RejectProposal(id int, reviewer string, reason string) (domain.PackSizeProposal, error) {
+	if reason == "" { // A rejection must be explainable
+		return domain.PackSizeProposal{}, ErrRejectionReasonRequired
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	proposal, err := uc.proposals.GetProposal(id) // Fetch the proposal to reject
+	if err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+	if proposal.Status != domain.ProposalInReview { // Only proposals under review can be rejected
+		return domain.PackSizeProposal{}, ErrInvalidProposalTransition
+	}
+
+	now := uc.catalogue.now()
+	proposal.Status = domain.ProposalRejected
+	proposal.ReviewedBy = reviewer
+	proposal.ReviewedAt = &now
+	proposal.RejectionReason = reason
+	if err := uc.proposals.UpdateProposal(proposal); err != nil {
+		return domain.PackSizeProposal{}, err
+	}
+	return proposal, nil
+}
+
+// close ends the review of a proposal that cannot be published, returning why
+func (uc *PackSizeApprovalUseCase) close(proposal domain.PackSizeProposal, status domain.ProposalStatus, reviewer string, reason error) error {
+	now := uc.catalogue.now()
+	proposal.Status = status
+	proposal.ReviewedBy = reviewer
+	proposal.ReviewedAt = &now
+	if err := uc.proposals.UpdateProposal(proposal); err != nil {
+		return err
+	}
+	return reason
+}
+
+// PreviewProposal calculates each order amount with both the current and the proposed pack sizes
+func (uc *PackSizeApprovalUseCase) PreviewProposal(id int, orderAmounts []int) ([]ProposalPreview, error) {
+	if len(orderAmounts) == 0 { // Nothing to compare without order amounts
+		return nil, ErrNoOrderAmounts
+	}
+
+	proposal, err := uc.proposals.GetProposal(id) // Fetch the proposal to preview
+	if err != nil {
+		return nil, err
+	}
+	current, err := uc.catalogue.GetPackSizes() // Fetch the pack sizes in effect now
+	if err != nil {
+		return nil, err
+	}
+
+	previews := make([]ProposalPreview, 0, len(orderAmounts))
+	for _, orderAmount := range orderAmounts {
+		previews = append(previews, ProposalPreview{
+			OrderAmount: orderAmount,
+			Current:     uc.previewCalculation(current, orderAmount),
+			Proposed:    uc.previewCalculation(proposal.PackSizes, orderAmount),
+		})
+	}
+	return previews, nil
+}
+
+// GetProposal retrieves a single proposal by ID
+func (uc *PackSizeApprovalUseCase) GetProposal(id int) (domain.PackSizeProposal, error) {
+	return uc.proposals.GetProposal(id) // Delegate to the repository to fetch the proposal
+}
+
+// ListProposals retrieves all proposals, or only those with the given status when it is not empty
+func (uc *PackSizeApprovalUseCase) ListProposals(status domain.ProposalStatus) ([]domain.PackSizeProposal, error) {
+	proposals, err := uc.proposals.ListProposals() // Fetch every proposal from the repository
+	if err != nil || status == "" {
+		return proposals, err
+	}
+
+	filtered := []domain.PackSizeProposal{}
+	for _, proposal := range proposals { // Keep only proposals in the requested state
+		if proposal.Status == status {
+			filtered = append(filtered, proposal)
+		}
+	}
+	return filtered, nil
+}
+
+// previewCalculation calculates one order, reporting failures inside the result instead of aborting the preview
+func (uc *PackSizeApprovalUseCase) previewCalculation(packSizes []int, orderAmount int) PackCalculation {
+	packs, totalItems, err := uc.catalogue.calculate(packSizes, orderAmount)
+	if err != nil {
+		return PackCalculation{Error: err.Error()}
+	}
+	return PackCalculation{Packs: packs, TotalItems: totalItems}
+}
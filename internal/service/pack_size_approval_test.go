@@ -0,0 +1,251 @@
+package service
+
+import (
+	"sync" // Import sync for concurrent approvals
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"     // Import gomock for mocking
+	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"
+	"order-packs-calculator/internal/infrastructure/repository"       // Import the repository package for in-memory stores
+	"order-packs-calculator/internal/infrastructure/repository/mocks" // Import the mocks package
+)
+
+// PackSizeApprovalUseCaseTestSuite defines the test suite for the approval workflow
+type PackSizeApprovalUseCaseTestSuite struct {
+	suite.Suite
+	mockProposals *mocks.MockProposalRepository // Use gomock-generated mock type
+	mockRepo      *mocks.MockPackRepository     // Pack-size repository behind the catalogue
+	uc            *PackSizeApprovalUseCase      // Use case under test
+	ctrl          *gomock.Controller            // Gomock controller for managing mocks
+	now           time.Time                     // Fixed clock for the use case
+}
+
+// SetupTest sets up the test environment before each test
+func (s *PackSizeApprovalUseCaseTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockProposals = mocks.NewMockProposalRepository(s.ctrl)
+	s.mockRepo = mocks.NewMockPackRepository(s.ctrl)
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Direct changes are blocked so that only the workflow can publish
+	catalogue := NewCalculatePacksUseCase(s.mockRepo, WithApprovalRequired(true), WithClock(func() time.Time { return s.now }))
+	s.uc = NewPackSizeApprovalUseCase(s.mockProposals, catalogue)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *PackSizeApprovalUseCaseTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestPackSizeApprovalUseCaseTestSuite runs the test suite
+func TestPackSizeApprovalUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PackSizeApprovalUseCaseTestSuite))
+}
+
+// proposal returns a proposal in the given state authored by alice
+func (s *PackSizeApprovalUseCaseTestSuite) proposal(status domain.ProposalStatus) domain.PackSizeProposal {
+	return domain.PackSizeProposal{ID: 1, PackSizes: []int{100, 200}, Author: "alice", BaseVersion: 3, Status: status, CreatedAt: s.now}
+}
+
+// TestCreateProposal tests creating a draft
+func (s *PackSizeApprovalUseCaseTestSuite) TestCreateProposal() {
+	s.Run("Success", func() {
+		draft := domain.PackSizeProposal{PackSizes: []int{100, 200}, Author: "alice", BaseVersion: 3, Status: domain.ProposalDraft, CreatedAt: s.now}
+		s.mockRepo.EXPECT().LatestVersionID().Return(3, nil) // The newest version is recorded as the base
+		s.mockProposals.EXPECT().CreateProposal(draft).Return(s.proposal(domain.ProposalDraft), nil)
+
+		proposal, err := s.uc.CreateProposal([]int{100, 200}, nil, "alice")
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(domain.ProposalDraft, proposal.Status, "New proposals should be drafts")
+	})
+
+	s.Run("EffectiveFromInPast", func() {
+		past := s.now.Add(-time.Hour)
+		_, err := s.uc.CreateProposal([]int{100}, &past, "alice")
+		s.Assert().Equal(ErrEffectiveFromInPast, err, "Expected past effective dates to be rejected")
+	})
+}
+
+// TestSubmitProposal tests sending a draft for review
+func (s *PackSizeApprovalUseCaseTestSuite) TestSubmitProposal() {
+	s.Run("Success", func() {
+		s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalDraft), nil)
+		s.mockProposals.EXPECT().UpdateProposal(gomock.Any()).Return(nil)
+
+		proposal, err := s.uc.SubmitProposal(1)
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(domain.ProposalInReview, proposal.Status, "Proposal should be under review")
+		s.Assert().Equal(s.now, *proposal.SubmittedAt, "Submission time should be recorded")
+	})
+
+	s.Run("NotDraft", func() {
+		s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalPublished), nil)
+
+		_, err := s.uc.SubmitProposal(1)
+		s.Assert().Equal(ErrInvalidProposalTransition, err, "Only drafts can be submitted")
+	})
+}
+
+// TestApproveProposal tests publishing a proposal under review
+func (s *PackSizeApprovalUseCaseTestSuite) TestApproveProposal() {
+	s.Run("Success", func() {
+		s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalInReview), nil)
+		// Approval publishes the sizes as a version on top of the base, even though direct changes are blocked
+		s.mockRepo.EXPECT().SaveVersionIfLatest(domain.PackSizeVersion{
+			PackSizes: []int{100, 200}, Author: "alice", ApprovedBy: "bob", EffectiveFrom: s.now,
+		}, 3).Return(domain.PackSizeVersion{ID: 4}, nil)
+		s.mockProposals.EXPECT().UpdateProposal(gomock.Any()).Return(nil)
+
+		proposal, err := s.uc.ApproveProposal(1, "bob")
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(domain.ProposalPublished, proposal.Status, "Proposal should be published")
+		s.Assert().Equal("bob", proposal.ReviewedBy, "Reviewer should be recorded")
+		s.Assert().Equal(4, proposal.PublishedVersion, "Published version should be recorded")
+	})
+
+	s.Run("Scheduled", func() {
+		effectiveFrom := s.now.Add(24 * time.Hour)
+		scheduled := s.proposal(domain.ProposalInReview)
+		scheduled.EffectiveFrom = &effectiveFrom
+		s.mockProposals.EXPECT().GetProposal(1).Return(scheduled, nil)
+		s.mockRepo.EXPECT().SaveVersionIfLatest(domain.PackSizeVersion{
+			PackSizes: []int{100, 200}, Author: "alice", ApprovedBy: "bob", EffectiveFrom: effectiveFrom,
+		}, 3).Return(domain.PackSizeVersion{ID: 5}, nil)
+		s.mockProposals.EXPECT().UpdateProposal(gomock.Any()).Return(nil)
+
+		_, err := s.uc.ApproveProposal(1, "bob")
+		s.Assert().NoError(err, "Expected no error")
+	})
+
+	s.Run("Expired", func() {
+		effectiveFrom := s.now.Add(-time.Minute) // Passed while the proposal was in review
+		stale := s.proposal(domain.ProposalInReview)
+		stale.EffectiveFrom = &effectiveFrom
+		s.mockProposals.EXPECT().GetProposal(1).Return(stale, nil)
+		s.mockProposals.EXPECT().UpdateProposal(gomock.Any()).DoAndReturn(func(proposal domain.PackSizeProposal) error {
+			s.Assert().Equal(domain.ProposalExpired, proposal.Status, "Proposal should be closed")
+			return nil
+		})
+
+		_, err := s.uc.ApproveProposal(1, "bob")
+		s.Assert().Equal(ErrProposalExpired, err, "Expected the proposal to have expired")
+	})
+
+	s.Run("Superseded", func() {
+		s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalInReview), nil)
+		s.mockRepo.EXPECT().SaveVersionIfLatest(gomock.Any(), 3).Return(domain.PackSizeVersion{}, repository.ErrVersionConflict)
+		s.mockProposals.EXPECT().UpdateProposal(gomock.Any()).DoAndReturn(func(proposal domain.PackSizeProposal) error {
+			s.Assert().Equal(domain.ProposalSuperseded, proposal.Status, "Proposal should be closed")
+			return nil
+		})
+
+		_, err := s.uc.ApproveProposal(1, "bob")
+		s.Assert().Equal(ErrProposalSuperseded, err, "Expected the proposal to be superseded")
+	})
+
+	s.Run("SelfApproval", func() {
+		s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalInReview), nil)
+
+		_, err := s.uc.ApproveProposal(1, "alice")
+		s.Assert().Equal(ErrSelfApproval, err, "Authors must not approve their own proposals")
+	})
+
+	s.Run("NotInReview", func() {
+		s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalDraft), nil)
+
+		_, err := s.uc.ApproveProposal(1, "bob")
+		s.Assert().Equal(ErrInvalidProposalTransition, err, "Drafts cannot be approved")
+	})
+}
+
+// TestConcurrentApprovals tests that a proposal approved by two reviewers at once is published once
+func (s *PackSizeApprovalUseCaseTestSuite) TestConcurrentApprovals() {
+	packs := repository.NewInMemoryPackRepository([]int{250, 500})
+	uc := NewPackSizeApprovalUseCase(repository.NewInMemoryProposalRepository(), NewCalculatePacksUseCase(packs, WithApprovalRequired(true)))
+	proposal, err := uc.CreateProposal([]int{100, 200}, nil, "alice")
+	s.Require().NoError(err, "Expected no error")
+	_, err = uc.SubmitProposal(proposal.ID)
+	s.Require().NoError(err, "Expected no error")
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = uc.ApproveProposal(proposal.ID, "bob")
+		}(i)
+	}
+	wg.Wait()
+
+	s.Assert().Equal(1, countNil(errs), "Only one approval should succeed")
+	for _, err := range errs {
+		if err != nil {
+			s.Assert().Equal(ErrInvalidProposalTransition, err, "Later approvals should find the proposal published")
+		}
+	}
+	versions, err := packs.ListVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 2, "The proposal should be published once")
+}
+
+// countNil counts the nil errors
+func countNil(errs []error) int {
+	count := 0
+	for _, err := range errs {
+		if err == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// TestRejectProposal tests turning down a proposal under review
+func (s *PackSizeApprovalUseCaseTestSuite) TestRejectProposal() {
+	s.Run("Success", func() {
+		s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalInReview), nil)
+		s.mockProposals.EXPECT().UpdateProposal(gomock.Any()).Return(nil)
+
+		proposal, err := s.uc.RejectProposal(1, "bob", "500 packs are discontinued")
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(domain.ProposalRejected, proposal.Status, "Proposal should be rejected")
+		s.Assert().Equal("500 packs are discontinued", proposal.RejectionReason, "Reason should be recorded")
+	})
+
+	s.Run("MissingReason", func() {
+		_, err := s.uc.RejectProposal(1, "bob", "")
+		s.Assert().Equal(ErrRejectionReasonRequired, err, "Rejections need a reason")
+	})
+}
+
+// TestPreviewProposal tests comparing current and proposed calculations
+func (s *PackSizeApprovalUseCaseTestSuite) TestPreviewProposal() {
+	s.mockProposals.EXPECT().GetProposal(1).Return(s.proposal(domain.ProposalDraft), nil)
+	s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{PackSizes: []int{250, 500}}, nil)
+
+	previews, err := s.uc.PreviewProposal(1, []int{263, -1})
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(previews, 2, "Expected one preview per order amount")
+	s.Assert().Equal(PackCalculation{Packs: map[int]int{500: 1}, TotalItems: 500}, previews[0].Current, "Current result should match")
+	s.Assert().Equal(PackCalculation{Packs: map[int]int{100: 1, 200: 1}, TotalItems: 300}, previews[0].Proposed, "Proposed result should match")
+	s.Assert().Equal(domain.ErrInvalidOrderAmount.Error(), previews[1].Proposed.Error, "Failures should be reported per order")
+}
+
+// TestListProposals tests filtering proposals by status
+func (s *PackSizeApprovalUseCaseTestSuite) TestListProposals() {
+	s.mockProposals.EXPECT().ListProposals().Return([]domain.PackSizeProposal{
+		{ID: 1, Status: domain.ProposalDraft},
+		{ID: 2, Status: domain.ProposalInReview},
+	}, nil).Times(2)
+
+	all, err := s.uc.ListProposals("")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(all, 2, "Empty status should list every proposal")
+
+	inReview, err := s.uc.ListProposals(domain.ProposalInReview)
+	s.Assert().NoError(err, "Expected no error")
+	s.Require().Len(inReview, 1, "Only proposals under review should be listed")
+	s.Assert().Equal(2, inReview[0].ID, "Proposal ID should match")
+}
@@ -0,0 +1,21 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+const (
+	// DefaultPageSize is how many entries list endpoints return when no limit is requested
+	DefaultPageSize = 50
+	// MaxPageSize caps the entries returned in one page
+	MaxPageSize = 500
+)
+
+// pageBounds applies the default page size and clamps out-of-range offsets and limits
+func pageBounds(offset, limit int) (int, int) {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	} else if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset, limit
+}
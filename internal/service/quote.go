@@ -0,0 +1,106 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"crypto/rand"  // Import crypto/rand for unguessable quote IDs
+	"encoding/hex" // Import hex for encoding quote IDs
+	"errors"       // Import errors for quote errors
+	"time"         // Import time for quote expiry
+
+	"order-packs-calculator/internal/domain"                    // Import the domain package for quotes
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for quote storage
+)
+
+// ErrQuoteExpired is returned when a quote is requested after its expiry
+var ErrQuoteExpired = errors.New("quote has expired")
+
+// DefaultQuoteTTL is how long quotes are honoured when no TTL is configured
+const DefaultQuoteTTL = 7 * 24 * time.Hour
+
+// QuoteService defines the interface for the QuoteUseCase
+type QuoteService interface {
+	CreateQuote(orderAmount int, author string) (domain.Quote, error)
+	GetQuote(id string) (domain.Quote, error)
+}
+
+// QuoteUseCase creates and retrieves quotes that pin a pack solution to a pack-size version
+type QuoteUseCase struct {
+	quotes    repository.QuoteRepository // Repository for quotes
+	catalogue *CalculatePacksUseCase     // Use case that resolves and calculates with pack sizes
+	ttl       time.Duration              // How long a quote is honoured
+}
+
+// Ensure QuoteUseCase implements QuoteService
+var _ QuoteService = (*QuoteUseCase)(nil)
+
+// NewQuoteUseCase creates a new instance of QuoteUseCase
+func NewQuoteUseCase(quotes repository.QuoteRepository, catalogue *CalculatePacksUseCase, ttl time.Duration) *QuoteUseCase {
+	if ttl <= 0 { // Fall back to the default for missing or invalid TTLs
+		ttl = DefaultQuoteTTL
+	}
+	return &QuoteUseCase{
+		quotes:    quotes,    // Initialize the quote repository
+		catalogue: catalogue, // Initialize the pack-size catalogue
+		ttl:       ttl,       // Initialize the quote lifetime
+	}
+}
+
+// CreateQuote calculates packs with the pack sizes in effect now and stores the result as a quote
+func (uc *QuoteUseCase) CreateQuote(orderAmount int, author string) (domain.Quote, error) {
+	now := uc.catalogue.Now()
+	version, packs, totalItems, err := uc.catalogue.CalculateWithActiveVersion(orderAmount) // Also names the version the quote is based on
+	if err != nil {
+		return domain.Quote{}, err
+	}
+
+	id, err := newRandomID()
+	if err != nil {
+		return domain.Quote{}, err
+	}
+
+	quote := domain.Quote{
+		ID:              id,
+		OrderAmount:     orderAmount,
+		PackSizeVersion: version.ID,
+		Packs:           packs,
+		TotalItems:      totalItems,
+		CreatedBy:       author,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(uc.ttl),
+	}
+	if err := uc.quotes.SaveQuote(quote); err != nil {
+		return domain.Quote{}, err
+	}
+	return quote, nil
+}
+
+// GetQuote retrieves a quote, rejecting it once it has expired
+func (uc *QuoteUseCase) GetQuote(id string) (domain.Quote, error) {
+	quote, err := uc.quotes.GetQuote(id) // Fetch the quote from the repository
+	if err != nil {
+		return domain.Quote{}, err
+	}
+	if quote.IsExpired(uc.catalogue.Now()) { // Expired quotes are no longer honoured
+		return domain.Quote{}, ErrQuoteExpired
+	}
+	return quote, nil
+}
+
+// ExportState returns every quote, expired ones included, for a snapshot
+func (uc *QuoteUseCase) ExportState() ([]domain.Quote, error) {
+	return uc.quotes.ListQuotes()
+}
+
+// RestoreState replaces every quote with the given ones, e.g. from a snapshot; the pack-size versions
+// they were calculated with must be restored first
+func (uc *QuoteUseCase) RestoreState(quotes []domain.Quote) error {
+	return uc.quotes.ReplaceQuotes(quotes)
+}
+
+// newRandomID returns a random, hex-encoded identifier for quotes and reservations
+func newRandomID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
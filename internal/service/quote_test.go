@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"     // Import gomock for mocking
+	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"
+	"order-packs-calculator/internal/infrastructure/repository/mocks" // Import the mocks package
+)
+
+// QuoteUseCaseTestSuite defines the test suite for quotes
+type QuoteUseCaseTestSuite struct {
+	suite.Suite
+	mockQuotes *mocks.MockQuoteRepository // Use gomock-generated mock type
+	mockRepo   *mocks.MockPackRepository  // Pack-size repository behind the catalogue
+	uc         *QuoteUseCase              // Use case under test
+	ctrl       *gomock.Controller         // Gomock controller for managing mocks
+	now        time.Time                  // Fixed clock for the use case
+}
+
+// SetupTest sets up the test environment before each test
+func (s *QuoteUseCaseTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockQuotes = mocks.NewMockQuoteRepository(s.ctrl)
+	s.mockRepo = mocks.NewMockPackRepository(s.ctrl)
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	catalogue := NewCalculatePacksUseCase(s.mockRepo, WithClock(func() time.Time { return s.now }))
+	s.uc = NewQuoteUseCase(s.mockQuotes, catalogue, 24*time.Hour)
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *QuoteUseCaseTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+// TestQuoteUseCaseTestSuite runs the test suite
+func TestQuoteUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(QuoteUseCaseTestSuite))
+}
+
+// TestCreateQuote tests that a quote records the solution and the version it was based on
+func (s *QuoteUseCaseTestSuite) TestCreateQuote() {
+	s.mockRepo.EXPECT().GetActiveVersion(s.now).Return(domain.PackSizeVersion{ID: 3, PackSizes: []int{250, 500}}, nil)
+	var saved domain.Quote
+	s.mockQuotes.EXPECT().SaveQuote(gomock.Any()).DoAndReturn(func(quote domain.Quote) error {
+		saved = quote
+		return nil
+	})
+
+	quote, err := s.uc.CreateQuote(263, "alice")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(saved, quote, "Returned quote should be the stored one")
+	s.Assert().NotEmpty(quote.ID, "Quote should get an ID")
+	s.Assert().Equal(3, quote.PackSizeVersion, "Quote should pin the pack-size version")
+	s.Assert().Equal(map[int]int{500: 1}, quote.Packs, "Packs should match expected")
+	s.Assert().Equal(s.now.Add(24*time.Hour), quote.ExpiresAt, "Expiry should follow the TTL")
+}
+
+// TestGetQuote tests retrieving quotes before and after their expiry
+func (s *QuoteUseCaseTestSuite) TestGetQuote() {
+	s.Run("Valid", func() {
+		stored := domain.Quote{ID: "abc", Packs: map[int]int{500: 1}, ExpiresAt: s.now.Add(time.Minute)}
+		s.mockQuotes.EXPECT().GetQuote("abc").Return(stored, nil)
+
+		quote, err := s.uc.GetQuote("abc")
+		s.Assert().NoError(err, "Expected no error")
+		s.Assert().Equal(stored, quote, "Stored quote should be returned unchanged")
+	})
+
+	s.Run("Expired", func() {
+		s.mockQuotes.EXPECT().GetQuote("old").Return(domain.Quote{ID: "old", ExpiresAt: s.now}, nil)
+
+		_, err := s.uc.GetQuote("old")
+		s.Assert().Equal(ErrQuoteExpired, err, "Expired quotes should be rejected")
+	})
+}
@@ -0,0 +1,218 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"context" // Import context for stopping the expiry loop
+	"errors"  // Import errors for reservation errors
+	"fmt"     // Import fmt for field names in validation errors
+	"sort"    // Import sort for reporting invalid sizes in order
+	"time"    // Import time for reservation expiry
+
+	"order-packs-calculator/internal/domain"                    // Import the domain package for reservations
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for stock and reservations
+)
+
+// ErrReservationExpired is returned when a reservation is confirmed or cancelled after it expired
+var ErrReservationExpired = repository.ErrReservationExpired
+
+// DefaultReservationTTL is how long packs are held when no TTL is configured
+const DefaultReservationTTL = 15 * time.Minute
+
+// ReservationService defines the interface for the ReservationUseCase
+type ReservationService interface {
+	Reserve(orderAmount int, author string) (domain.Reservation, error)
+	GetReservation(id string) (domain.Reservation, error)
+	ListReservations(status domain.ReservationStatus) ([]domain.Reservation, error)
+	ConfirmReservation(id string, actor string) (domain.Reservation, error)
+	CancelReservation(id string, actor string) (domain.Reservation, error)
+	GetStock() (map[int]int, error)
+	SetStock(stock map[int]int) (map[int]int, error)
+}
+
+// ReservationUseCase reserves the packs of an order from stock until the order is confirmed or cancelled
+type ReservationUseCase struct {
+	inventory repository.InventoryRepository // Repository for stock levels and reservations
+	catalogue *CalculatePacksUseCase         // Use case that resolves and calculates with pack sizes
+	ttl       time.Duration                  // How long packs are held before they are released
+}
+
+// Ensure ReservationUseCase implements ReservationService
+var _ ReservationService = (*ReservationUseCase)(nil)
+
+// NewReservationUseCase creates a new instance of ReservationUseCase
+func NewReservationUseCase(inventory repository.InventoryRepository, catalogue *CalculatePacksUseCase, ttl time.Duration) *ReservationUseCase {
+	if ttl <= 0 { // Fall back to the default for missing or invalid TTLs
+		ttl = DefaultReservationTTL
+	}
+	return &ReservationUseCase{
+		inventory: inventory, // Initialize the inventory repository
+		catalogue: catalogue, // Initialize the pack-size catalogue
+		ttl:       ttl,       // Initialize the reservation lifetime
+	}
+}
+
+// Reserve calculates packs with the pack sizes in effect now and takes them from stock in one step,
+// so two orders can never be given the same packs
+func (uc *ReservationUseCase) Reserve(orderAmount int, author string) (domain.Reservation, error) {
+	now := uc.catalogue.Now()
+	version, packs, totalItems, err := uc.catalogue.CalculateWithActiveVersion(orderAmount) // Also names the version the packs are calculated with
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	id, err := newRandomID()
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+
+	reservation := domain.Reservation{
+		ID:              id,
+		OrderAmount:     orderAmount,
+		PackSizeVersion: version.ID,
+		Packs:           packs,
+		TotalItems:      totalItems,
+		Status:          domain.ReservationHeld,
+		CreatedBy:       author,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(uc.ttl),
+	}
+	if err := uc.inventory.Reserve(reservation); err != nil { // Fails without taking anything when stock is short
+		return domain.Reservation{}, err
+	}
+	return reservation, nil
+}
+
+// GetReservation retrieves a reservation, releasing it first if it has expired
+func (uc *ReservationUseCase) GetReservation(id string) (domain.Reservation, error) {
+	reservation, err := uc.inventory.GetReservation(id) // Fetch the reservation from the repository
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return uc.expire(reservation)
+}
+
+// ListReservations lists reservations in the given status (all when empty), releasing expired ones first
+func (uc *ReservationUseCase) ListReservations(status domain.ReservationStatus) ([]domain.Reservation, error) {
+	if _, err := uc.ExpireReservations(); err != nil { // Expired reservations must not be listed as held
+		return nil, err
+	}
+	return uc.inventory.ListReservations(status)
+}
+
+// ConfirmReservation allocates the held packs to the order for good
+func (uc *ReservationUseCase) ConfirmReservation(id string, actor string) (domain.Reservation, error) {
+	return uc.resolve(id, domain.ReservationConfirmed, actor)
+}
+
+// CancelReservation returns the held packs to stock
+func (uc *ReservationUseCase) CancelReservation(id string, actor string) (domain.Reservation, error) {
+	return uc.resolve(id, domain.ReservationCancelled, actor)
+}
+
+// GetStock returns the packs in stock per size
+func (uc *ReservationUseCase) GetStock() (map[int]int, error) {
+	return uc.inventory.GetStock()
+}
+
+// SetStock validates and sets the stock level of the given sizes, returning the stock afterwards
+func (uc *ReservationUseCase) SetStock(stock map[int]int) (map[int]int, error) {
+	sizes := make([]int, 0, len(stock))
+	for size := range stock {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes) // Report invalid sizes in a stable order
+
+	var fields []FieldError
+	if len(stock) == 0 {
+		fields = append(fields, FieldError{Field: "stock", Message: "must not be empty"})
+	}
+	for _, size := range sizes {
+		field := fmt.Sprintf("stock[%d]", size)
+		if size <= 0 {
+			fields = append(fields, FieldError{Field: field, Message: "pack size must be positive"})
+		} else if stock[size] < 0 {
+			fields = append(fields, FieldError{Field: field, Message: "must not be negative"})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+
+	if err := uc.inventory.SetStock(stock); err != nil {
+		return nil, err
+	}
+	return uc.inventory.GetStock()
+}
+
+// ExpireReservations returns the packs of every reservation that has run out to stock,
+// returning how many were released
+func (uc *ReservationUseCase) ExpireReservations() (int, error) {
+	held, err := uc.inventory.ListReservations(domain.ReservationHeld)
+	if err != nil {
+		return 0, err
+	}
+
+	now := uc.catalogue.Now()
+	released := 0
+	for _, reservation := range held {
+		if !reservation.IsExpired(now) {
+			continue
+		}
+		_, err := uc.inventory.ResolveReservation(reservation.ID, domain.ReservationExpired, "", now)
+		if errors.Is(err, repository.ErrReservationNotHeld) { // Confirmed or cancelled in the meantime
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+// Run releases expired reservations every interval until the context is cancelled
+func (uc *ReservationUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.ExpireReservations(); err != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// resolve moves a held reservation to the given status, expiring it instead if it has run out
+func (uc *ReservationUseCase) resolve(id string, status domain.ReservationStatus, actor string) (domain.Reservation, error) {
+	reservation, err := uc.GetReservation(id) // Releases the reservation if it has expired
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	if reservation.Status == domain.ReservationExpired {
+		return domain.Reservation{}, ErrReservationExpired
+	}
+
+	// The repository checks the expiry again under its lock, so a reservation running out meanwhile is expired
+	// rather than confirmed, and ErrReservationExpired is returned
+	reservation, err = uc.inventory.ResolveReservation(id, status, actor, uc.catalogue.Now())
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return reservation, nil
+}
+
+// expire releases a reservation that has run out, returning it as it is afterwards
+func (uc *ReservationUseCase) expire(reservation domain.Reservation) (domain.Reservation, error) {
+	if !reservation.IsExpired(uc.catalogue.Now()) {
+		return reservation, nil
+	}
+	expired, err := uc.inventory.ResolveReservation(reservation.ID, domain.ReservationExpired, "", uc.catalogue.Now())
+	if errors.Is(err, repository.ErrReservationNotHeld) { // Resolved concurrently; the repository returns its current state
+		return expired, nil
+	}
+	return expired, err
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"  // Import errors for matching wrapped errors
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for the fixed clock
+
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for reservations
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for in-memory storage
+)
+
+// ReservationUseCaseTestSuite defines the test suite for reservations
+type ReservationUseCaseTestSuite struct {
+	suite.Suite
+	inventory *repository.InMemoryInventoryRepository // Stock the use case reserves from
+	uc        *ReservationUseCase                     // Use case under test
+	now       time.Time                               // Fixed clock for the use cases
+}
+
+// SetupTest sets up the test environment before each test
+func (s *ReservationUseCaseTestSuite) SetupTest() {
+	s.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.inventory = repository.NewInMemoryInventoryRepository()
+	catalogue := NewCalculatePacksUseCase(repository.NewInMemoryPackRepository([]int{250, 500, 1000}),
+		WithClock(func() time.Time { return s.now }))
+	s.uc = NewReservationUseCase(s.inventory, catalogue, 15*time.Minute)
+
+	_, err := s.uc.SetStock(map[int]int{250: 1, 500: 2, 1000: 1})
+	s.Require().NoError(err, "Expected no error")
+}
+
+// TestReservationUseCaseTestSuite runs the test suite
+func TestReservationUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ReservationUseCaseTestSuite))
+}
+
+// TestReserve tests that the calculated packs are taken from stock
+func (s *ReservationUseCaseTestSuite) TestReserve() {
+	reservation, err := s.uc.Reserve(263, "alice")
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(map[int]int{500: 1}, reservation.Packs, "Calculated packs should be reserved")
+	s.Assert().Equal(domain.ReservationHeld, reservation.Status, "Reservation should be held")
+	s.Assert().Equal(s.now.Add(15*time.Minute), reservation.ExpiresAt, "Expiry should follow the TTL")
+
+	stock, err := s.uc.GetStock()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(map[int]int{250: 1, 500: 1, 1000: 1}, stock, "Reserved packs should leave stock")
+
+	_, err = s.uc.Reserve(2001, "bob") // Needs two 1000 packs and a 250
+	s.Assert().True(errors.Is(err, repository.ErrInsufficientStock), "Expected insufficient stock")
+}
+
+// TestConfirmAndCancel tests that confirmed packs stay allocated and cancelled packs return
+func (s *ReservationUseCaseTestSuite) TestConfirmAndCancel() {
+	first, _ := s.uc.Reserve(263, "alice")
+	second, _ := s.uc.Reserve(1000, "alice")
+
+	confirmed, err := s.uc.ConfirmReservation(first.ID, "bob")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(domain.ReservationConfirmed, confirmed.Status, "Reservation should be confirmed")
+
+	cancelled, err := s.uc.CancelReservation(second.ID, "bob")
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(domain.ReservationCancelled, cancelled.Status, "Reservation should be cancelled")
+
+	stock, _ := s.uc.GetStock()
+	s.Assert().Equal(map[int]int{250: 1, 500: 1, 1000: 1}, stock, "Only cancelled packs should return")
+
+	_, err = s.uc.CancelReservation(first.ID, "bob")
+	s.Assert().Equal(repository.ErrReservationNotHeld, err, "Confirmed reservations cannot be cancelled")
+}
+
+// TestExpiry tests that reservations not confirmed in time release their packs
+func (s *ReservationUseCaseTestSuite) TestExpiry() {
+	first, _ := s.uc.Reserve(263, "alice")
+	s.now = s.now.Add(10 * time.Minute)
+	second, _ := s.uc.Reserve(250, "alice")
+	s.now = s.now.Add(5 * time.Minute) // The first reservation runs out now
+
+	released, err := s.uc.ExpireReservations()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(1, released, "Only the first reservation should expire")
+
+	_, err = s.uc.ConfirmReservation(first.ID, "bob")
+	s.Assert().Equal(ErrReservationExpired, err, "Expired reservations cannot be confirmed")
+
+	s.now = s.now.Add(10 * time.Minute) // The second runs out without a sweep
+	reservation, err := s.uc.GetReservation(second.ID)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(domain.ReservationExpired, reservation.Status, "Reading should release an expired reservation")
+
+	stock, _ := s.uc.GetStock()
+	s.Assert().Equal(map[int]int{250: 1, 500: 2, 1000: 1}, stock, "All packs should be back in stock")
+}
+
+// TestSetStockValidation tests that invalid stock levels are rejected per size
+func (s *ReservationUseCaseTestSuite) TestSetStockValidation() {
+	_, err := s.uc.SetStock(map[int]int{-5: 1, 250: -1})
+	s.Assert().Equal(&ValidationError{Fields: []FieldError{
+		{Field: "stock[-5]", Message: "pack size must be positive"},
+		{Field: "stock[250]", Message: "must not be negative"},
+	}}, err, "Each invalid size should be reported")
+
+	_, err = s.uc.SetStock(nil)
+	s.Assert().Error(err, "Empty stock updates should be rejected")
+}
@@ -0,0 +1,243 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"bufio"         // Import bufio for detecting compressed archives
+	"compress/gzip" // Import gzip for compressing archives
+	"encoding/json" // Import json for the archive contents
+	"errors"        // Import errors for snapshot errors
+	"fmt"           // Import fmt for describing invalid snapshots
+	"io"            // Import io for reading and writing archives
+	"sort"          // Import sort for restoring tenants in a stable order
+	"sync"          // Import sync for guarding the registered tenants
+	"time"          // Import time for the snapshot creation time
+
+	"order-packs-calculator/internal/domain"                    // Import the domain package for versions and quotes
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for version checks
+)
+
+const (
+	// SnapshotFormat names the archive format, so other files are not mistaken for snapshots
+	SnapshotFormat = "order-packs-calculator/snapshot"
+	// SnapshotFormatVersion is the version of the archive layout written by WriteSnapshot. It is raised
+	// whenever the layout changes, and ReadSnapshot keeps reading every older version
+	SnapshotFormatVersion = 1
+)
+
+var (
+	// ErrInvalidSnapshot is returned when an archive cannot be read or its contents are inconsistent
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	// ErrSnapshotTooNew is returned for an archive written by a newer release than this one
+	ErrSnapshotTooNew = errors.New("snapshot was written by a newer release")
+)
+
+// snapshotNotIncluded names the state that snapshots leave out; a restore keeps what the server has of it
+var snapshotNotIncluded = []string{"proposals", "reservations", "stock", "calculationHistory", "webhooks", "auditTrail"}
+
+// SnapshotNotIncluded returns the names of the state that snapshots leave out, so archives and restores
+// can say what they do not cover
+func SnapshotNotIncluded() []string {
+	return append([]string(nil), snapshotNotIncluded...)
+}
+
+// Snapshot is the state of every tenant at one point in time
+type Snapshot struct {
+	Format        string                    `json:"format"`        // Always SnapshotFormat
+	FormatVersion int                       `json:"formatVersion"` // Layout version of the archive
+	CreatedAt     time.Time                 `json:"createdAt"`     // When the snapshot was taken
+	NotIncluded   []string                  `json:"notIncluded"`   // State left out of the archive, for readers only
+	Tenants       map[string]TenantSnapshot `json:"tenants"`       // State by tenant ID
+}
+
+// TenantSnapshot is the state of one tenant
+type TenantSnapshot struct {
+	PackSizeVersions []domain.PackSizeVersion `json:"packSizeVersions"` // Every pack-size version, oldest first
+	Quotes           []domain.Quote           `json:"quotes"`           // Every quote, including expired ones
+}
+
+// RestoredTenant counts what was restored for a tenant
+type RestoredTenant struct {
+	PackSizeVersions int `json:"packSizeVersions"` // Versions now stored
+	Quotes           int `json:"quotes"`           // Quotes now stored
+}
+
+// SnapshotService defines the interface for the SnapshotUseCase
+type SnapshotService interface {
+	Snapshot() (Snapshot, error)
+	Restore(snapshot Snapshot) (map[string]RestoredTenant, error)
+}
+
+// snapshotTenant holds the use cases whose state is part of a tenant's snapshot
+type snapshotTenant struct {
+	packs  *CalculatePacksUseCase
+	quotes *QuoteUseCase
+}
+
+// SnapshotUseCase takes snapshots of the registered tenants and restores them
+type SnapshotUseCase struct {
+	mu      sync.Mutex                // Serialises snapshots and restores, so neither sees the other half done
+	tenants map[string]snapshotTenant // Use cases by tenant ID
+	now     func() time.Time          // Clock for the snapshot creation time
+}
+
+// Ensure SnapshotUseCase implements SnapshotService
+var _ SnapshotService = (*SnapshotUseCase)(nil)
+
+// NewSnapshotUseCase creates a new instance of SnapshotUseCase with no tenants
+func NewSnapshotUseCase() *SnapshotUseCase {
+	return &SnapshotUseCase{
+		tenants: make(map[string]snapshotTenant), // Tenants are added with Register
+		now:     time.Now,                        // Use the system clock
+	}
+}
+
+// Register adds a tenant whose pack sizes and quotes are included in snapshots
+func (uc *SnapshotUseCase) Register(tenantID string, packs *CalculatePacksUseCase, quotes *QuoteUseCase) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if _, exists := uc.tenants[tenantID]; exists {
+		return ErrDuplicateTenant
+	}
+	uc.tenants[tenantID] = snapshotTenant{packs: packs, quotes: quotes}
+	return nil
+}
+
+// Snapshot reads the state of every tenant. Requests keep being served meanwhile; since versions are
+// only ever appended, reading a tenant's quotes before its versions guarantees that every version a
+// quote was calculated with is in the snapshot
+func (uc *SnapshotUseCase) Snapshot() (Snapshot, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	snapshot := Snapshot{
+		Format:        SnapshotFormat,
+		FormatVersion: SnapshotFormatVersion,
+		CreatedAt:     uc.now().UTC(),
+		NotIncluded:   SnapshotNotIncluded(),
+		Tenants:       make(map[string]TenantSnapshot, len(uc.tenants)),
+	}
+	for id, tenant := range uc.tenants {
+		quotes, err := tenant.quotes.ExportState()
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("tenant %q: %w", id, err)
+		}
+		versions, err := tenant.packs.ExportState()
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("tenant %q: %w", id, err)
+		}
+		snapshot.Tenants[id] = TenantSnapshot{PackSizeVersions: versions, Quotes: quotes}
+	}
+	return snapshot, nil
+}
+
+// Restore replaces the state of every tenant in the snapshot; tenants it leaves out keep theirs. The
+// whole snapshot is checked before anything is replaced, so an invalid one changes nothing. A storage
+// failure part way through leaves the tenants before it restored, and is safe to retry
+func (uc *SnapshotUseCase) Restore(snapshot Snapshot) (map[string]RestoredTenant, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if err := uc.check(snapshot); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(snapshot.Tenants))
+	for id := range snapshot.Tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	restored := make(map[string]RestoredTenant, len(ids))
+	for _, id := range ids {
+		tenant, state := uc.tenants[id], snapshot.Tenants[id]
+		// Versions first: once they are back, the quotes restored next always find theirs
+		if err := tenant.packs.RestoreState(state.PackSizeVersions); err != nil {
+			return restored, fmt.Errorf("tenant %q: %w", id, err)
+		}
+		if err := tenant.quotes.RestoreState(state.Quotes); err != nil {
+			return restored, fmt.Errorf("tenant %q: %w", id, err)
+		}
+		restored[id] = RestoredTenant{PackSizeVersions: len(state.PackSizeVersions), Quotes: len(state.Quotes)}
+	}
+	return restored, nil
+}
+
+// check reports the first reason the snapshot cannot be restored on this instance
+func (uc *SnapshotUseCase) check(snapshot Snapshot) error {
+	if snapshot.Format != SnapshotFormat {
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidSnapshot, snapshot.Format)
+	}
+	for id, state := range snapshot.Tenants {
+		if _, ok := uc.tenants[id]; !ok { // Tenants come from the configuration, so restoring cannot add one
+			return fmt.Errorf("%w: tenant %q is not configured", ErrInvalidSnapshot, id)
+		}
+		if err := repository.CheckVersions(state.PackSizeVersions); err != nil {
+			return fmt.Errorf("%w: tenant %q: %v", ErrInvalidSnapshot, id, err)
+		}
+		seen := make(map[string]bool, len(state.Quotes))
+		for _, quote := range state.Quotes {
+			switch {
+			case quote.ID == "" || seen[quote.ID]:
+				return fmt.Errorf("%w: tenant %q: quote IDs must be present and unique", ErrInvalidSnapshot, id)
+			case quote.PackSizeVersion < 1 || quote.PackSizeVersion > len(state.PackSizeVersions):
+				return fmt.Errorf("%w: tenant %q: quote %s refers to missing pack size version %d", ErrInvalidSnapshot, id, quote.ID, quote.PackSizeVersion)
+			}
+			seen[quote.ID] = true
+		}
+	}
+	return nil
+}
+
+// WriteSnapshot writes the snapshot as gzip-compressed JSON
+func WriteSnapshot(w io.Writer, snapshot Snapshot) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(snapshot); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadSnapshot reads an archive written by WriteSnapshot of this or an earlier release; uncompressed
+// JSON is accepted too, so an archive can be inspected and edited by hand
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	buffered := bufio.NewReader(r)
+	var reader io.Reader = buffered
+	if magic, _ := buffered.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(buffered)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		defer zr.Close()
+		reader = zr
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	// Read the header alone first: the rest of the layout depends on the format version
+	var header struct {
+		Format        string `json:"format"`
+		FormatVersion int    `json:"formatVersion"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+	if header.Format != SnapshotFormat {
+		return Snapshot{}, fmt.Errorf("%w: unknown format %q", ErrInvalidSnapshot, header.Format)
+	}
+
+	switch {
+	case header.FormatVersion > SnapshotFormatVersion:
+		return Snapshot{}, fmt.Errorf("%w: format version %d, this release reads up to %d", ErrSnapshotTooNew, header.FormatVersion, SnapshotFormatVersion)
+	case header.FormatVersion == 1:
+		// The current layout; once it changes, this case decodes the old layout and upgrades it
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return Snapshot{}, fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+		}
+		return snapshot, nil
+	default:
+		return Snapshot{}, fmt.Errorf("%w: format version %d", ErrInvalidSnapshot, header.FormatVersion)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"bytes"   // Import bytes for in-memory archives
+	"strings" // Import strings for hand-written archives
+	"testing" // Import the testing package for writing unit tests
+	"time"    // Import time for effective dates
+
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for versions and quotes
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for in-memory storage
+)
+
+// snapshotInstance is the state of one server: the tenants' use cases and the snapshot use case over them
+type snapshotInstance struct {
+	packs  map[string]*CalculatePacksUseCase // Pack sizes by tenant
+	quotes map[string]*QuoteUseCase          // Quotes by tenant
+	uc     *SnapshotUseCase                  // Use case under test
+}
+
+// SnapshotUseCaseTestSuite defines the test suite for snapshots
+type SnapshotUseCaseTestSuite struct {
+	suite.Suite
+}
+
+// TestSnapshotUseCaseTestSuite runs the test suite
+func TestSnapshotUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotUseCaseTestSuite))
+}
+
+// instance builds a server with the given tenants, each seeded with its own pack sizes
+func (s *SnapshotUseCaseTestSuite) instance(tenants map[string][]int) snapshotInstance {
+	instance := snapshotInstance{
+		packs:  map[string]*CalculatePacksUseCase{},
+		quotes: map[string]*QuoteUseCase{},
+		uc:     NewSnapshotUseCase(),
+	}
+	for id, sizes := range tenants {
+		packs := NewCalculatePacksUseCase(repository.NewInMemoryPackRepository(sizes))
+		quotes := NewQuoteUseCase(repository.NewInMemoryQuoteRepository(), packs, time.Hour)
+		s.Require().NoError(instance.uc.Register(id, packs, quotes), "Expected no error")
+		instance.packs[id], instance.quotes[id] = packs, quotes
+	}
+	return instance
+}
+
+// TestSnapshotAndRestore tests moving the state of every tenant to another instance through an archive
+func (s *SnapshotUseCaseTestSuite) TestSnapshotAndRestore() {
+	source := s.instance(map[string][]int{"default": {250, 500}, "acme": {10}})
+	_, err := source.packs["default"].UpdatePackSizes([]int{100, 200}, "alice")
+	s.Require().NoError(err, "Expected no error")
+	_, err = source.packs["default"].SchedulePackSizes([]int{300}, "bob", time.Now().Add(time.Hour))
+	s.Require().NoError(err, "Expected no error")
+	quote, err := source.quotes["default"].CreateQuote(150, "carol")
+	s.Require().NoError(err, "Expected no error")
+
+	snapshot, err := source.uc.Snapshot()
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(SnapshotFormatVersion, snapshot.FormatVersion, "Snapshot should carry the format version")
+	s.Assert().Len(snapshot.Tenants["default"].PackSizeVersions, 3, "Every version should be included")
+	s.Assert().Len(snapshot.Tenants["acme"].Quotes, 0, "Tenants without quotes should be included")
+	s.Assert().Equal(SnapshotNotIncluded(), snapshot.NotIncluded, "Snapshot should name the state it leaves out")
+
+	var archive bytes.Buffer
+	s.Require().NoError(WriteSnapshot(&archive, snapshot), "Expected no error")
+	read, err := ReadSnapshot(&archive)
+	s.Require().NoError(err, "Expected no error")
+
+	target := s.instance(map[string][]int{"default": {1}, "acme": {2}, "other": {3}})
+	sizes, _ := target.packs["default"].GetPackSizes()
+	s.Require().Equal([]int{1}, sizes, "Expected the target's own sizes")
+	restored, err := target.uc.Restore(read)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Equal(map[string]RestoredTenant{
+		"default": {PackSizeVersions: 3, Quotes: 1},
+		"acme":    {PackSizeVersions: 1, Quotes: 0},
+	}, restored, "Restored tenants should be counted")
+
+	sizes, err = target.packs["default"].GetPackSizes()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{100, 200}, sizes, "Restored sizes should be in effect")
+	scheduled, err := target.packs["default"].ListScheduledPackSizes()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(scheduled, 1, "Scheduled change should be restored")
+	restoredQuote, err := target.quotes["default"].GetQuote(quote.ID)
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal(quote.Packs, restoredQuote.Packs, "Quote should be restored")
+	sizes, _ = target.packs["other"].GetPackSizes()
+	s.Assert().Equal([]int{3}, sizes, "Tenants missing from the snapshot should keep their state")
+}
+
+// TestRestoreInvalid tests that snapshots that do not fit the instance are rejected before anything changes
+func (s *SnapshotUseCaseTestSuite) TestRestoreInvalid() {
+	target := s.instance(map[string][]int{"default": {250}, "acme": {10}})
+	version := domain.PackSizeVersion{ID: 1, PackSizes: []int{5}}
+	valid := TenantSnapshot{PackSizeVersions: []domain.PackSizeVersion{version}}
+
+	for name, tenants := range map[string]map[string]TenantSnapshot{
+		"unknown tenant":  {"default": valid, "missing": valid},
+		"no versions":     {"default": valid, "acme": {}},
+		"gap in versions": {"default": valid, "acme": {PackSizeVersions: []domain.PackSizeVersion{{ID: 2}}}},
+		"dangling quote":  {"default": valid, "acme": {PackSizeVersions: valid.PackSizeVersions, Quotes: []domain.Quote{{ID: "q", PackSizeVersion: 2}}}},
+		"duplicate quote": {"default": valid, "acme": {PackSizeVersions: valid.PackSizeVersions, Quotes: []domain.Quote{{ID: "q", PackSizeVersion: 1}, {ID: "q", PackSizeVersion: 1}}}},
+	} {
+		_, err := target.uc.Restore(Snapshot{Format: SnapshotFormat, FormatVersion: SnapshotFormatVersion, Tenants: tenants})
+		s.Assert().ErrorIs(err, ErrInvalidSnapshot, "Expected an invalid snapshot: "+name)
+	}
+	sizes, _ := target.packs["default"].GetPackSizes()
+	s.Assert().Equal([]int{250}, sizes, "Rejected snapshots should change nothing")
+}
+
+// TestRestoreReportsPackSizes tests that restored pack sizes are reported and persisted like any other change
+func (s *SnapshotUseCaseTestSuite) TestRestoreReportsPackSizes() {
+	repo := repository.NewInMemoryPackRepository([]int{1})
+	var mirrored []int
+	packs := NewCalculatePacksUseCase(repo, WithEvents(true), WithTenant("acme"), WithPackSizesMirror(func(packSizes []int) error {
+		mirrored = packSizes
+		return nil
+	}))
+	uc := NewSnapshotUseCase()
+	s.Require().NoError(uc.Register("acme", packs, NewQuoteUseCase(repository.NewInMemoryQuoteRepository(), packs, time.Hour)), "Expected no error")
+
+	versions := []domain.PackSizeVersion{{ID: 1, PackSizes: []int{10}}, {ID: 2, PackSizes: []int{20, 40}}}
+	_, err := uc.Restore(Snapshot{Format: SnapshotFormat, FormatVersion: SnapshotFormatVersion, Tenants: map[string]TenantSnapshot{"acme": {PackSizeVersions: versions}}})
+	s.Require().NoError(err, "Expected no error")
+
+	s.Assert().Equal([]int{20, 40}, mirrored, "Restored sizes in effect should be persisted")
+	events, err := repo.ListPendingEvents(0)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(events, 1, "Restore should emit one event")
+	s.Assert().Equal(domain.EventPackSizesUpdated, events[0].Type, "Restore should report the pack sizes")
+	s.Assert().Equal("acme", events[0].Tenant, "Event should name the tenant")
+}
+
+// TestReadSnapshotFormats tests that uncompressed archives are read and unknown or newer formats are rejected
+func (s *SnapshotUseCaseTestSuite) TestReadSnapshotFormats() {
+	snapshot, err := ReadSnapshot(strings.NewReader(`{"format":"order-packs-calculator/snapshot","formatVersion":1,"tenants":{"default":{"packSizeVersions":[{"id":1,"packSizes":[5]}]}}}`))
+	s.Require().NoError(err, "Plain JSON should be read")
+	s.Assert().Equal([]int{5}, snapshot.Tenants["default"].PackSizeVersions[0].PackSizes, "Contents should be decoded")
+
+	_, err = ReadSnapshot(strings.NewReader(`{"format":"order-packs-calculator/snapshot","formatVersion":2,"tenants":{"default":"a later layout"}}`))
+	s.Assert().ErrorIs(err, ErrSnapshotTooNew, "Newer format versions should be reported as such")
+	_, err = ReadSnapshot(strings.NewReader(`{"format":"order-packs-calculator/snapshot","formatVersion":0}`))
+	s.Assert().ErrorIs(err, ErrInvalidSnapshot, "Unknown format versions should be rejected")
+	_, err = ReadSnapshot(strings.NewReader(`{"versions":[]}`))
+	s.Assert().ErrorIs(err, ErrInvalidSnapshot, "Other files should be rejected")
+	_, err = ReadSnapshot(strings.NewReader("\x1f\x8bnot gzip"))
+	s.Assert().ErrorIs(err, ErrInvalidSnapshot, "Corrupt archives should be rejected")
+}
@@ -0,0 +1,128 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"errors" // Import errors for tenancy errors
+	"sort"   // Import sort for listing tenants in a stable order
+	"sync"   // Import sync for guarding the registry
+)
+
+var (
+	// ErrUnknownTenant is returned when a request names a tenant that does not exist
+	ErrUnknownTenant = errors.New("unknown tenant")
+	// ErrInvalidAPIKey is returned when an API key does not belong to any tenant
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrAPIKeyRequired is returned when a tenant protected by API keys is selected without one
+	ErrAPIKeyRequired = errors.New("tenant requires an API key")
+	// ErrTenantMismatch is returned when an API key is used for another tenant
+	ErrTenantMismatch = errors.New("API key does not belong to the requested tenant")
+	// ErrDuplicateTenant is returned when a tenant or API key is registered twice
+	ErrDuplicateTenant = errors.New("tenant or API key already registered")
+)
+
+// TenantServices bundles the services of one tenant; each tenant has its own pack sizes,
+// proposals, quotes, webhooks, calculation history and stock
+type TenantServices struct {
+	Packs     CalculatePacksService   // Pack-size catalogue and calculations
+	Approvals PackSizeApprovalService // Draft -> review -> published workflow
+	Quotes    QuoteService            // Customer quotes
+	Webhooks  WebhookService          // Webhook subscriptions
+
+	Calculations CalculationHistoryService // History of served calculations
+	Reservations ReservationService        // Stock levels and reservations
+	Catalogue    CatalogueService          // Import and export of the pack-size catalogue
+}
+
+// TenantRegistry resolves requests to tenants and their services
+type TenantRegistry struct {
+	mu            sync.RWMutex
+	defaultTenant string                    // Tenant used when a request names none
+	tenants       map[string]TenantServices // Services by tenant ID
+	protected     map[string]bool           // Tenants that can only be reached with an API key
+	apiKeys       map[string]string         // Tenant ID by API key
+}
+
+// NewTenantRegistry creates an empty registry; requests that name no tenant go to defaultTenant
+func NewTenantRegistry(defaultTenant string) *TenantRegistry {
+	return &TenantRegistry{
+		defaultTenant: defaultTenant,
+		tenants:       make(map[string]TenantServices),
+		protected:     make(map[string]bool),
+		apiKeys:       make(map[string]string),
+	}
+}
+
+// Register adds a tenant; when apiKeys are given, the tenant can only be reached with one of them
+func (r *TenantRegistry) Register(tenantID string, apiKeys []string, services TenantServices) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tenants[tenantID]; exists {
+		return ErrDuplicateTenant
+	}
+	for _, key := range apiKeys { // A key must identify exactly one tenant
+		if _, exists := r.apiKeys[key]; exists {
+			return ErrDuplicateTenant
+		}
+	}
+
+	r.tenants[tenantID] = services
+	for _, key := range apiKeys {
+		r.apiKeys[key] = tenantID
+	}
+	r.protected[tenantID] = len(apiKeys) > 0
+	return nil
+}
+
+// Resolve decides which tenant a request belongs to from the tenant it names and the API key it presents.
+// An API key selects its own tenant; without one, the named tenant (or the default) is used unless it is protected.
+func (r *TenantRegistry) Resolve(tenantID, apiKey string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if apiKey != "" {
+		owner, ok := r.apiKeys[apiKey]
+		if !ok {
+			return "", ErrInvalidAPIKey
+		}
+		if tenantID != "" && tenantID != owner { // A key never grants access to another tenant
+			return "", ErrTenantMismatch
+		}
+		return owner, nil
+	}
+
+	if tenantID == "" {
+		tenantID = r.defaultTenant
+	}
+	if _, ok := r.tenants[tenantID]; !ok {
+		return "", ErrUnknownTenant
+	}
+	if r.protected[tenantID] {
+		return "", ErrAPIKeyRequired
+	}
+	return tenantID, nil
+}
+
+// Services returns the services of a tenant
+func (r *TenantRegistry) Services(tenantID string) (TenantServices, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	services, ok := r.tenants[tenantID]
+	if !ok {
+		return TenantServices{}, ErrUnknownTenant
+	}
+	return services, nil
+}
+
+// Tenants lists the registered tenant IDs in alphabetical order
+func (r *TenantRegistry) Tenants() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.tenants))
+	for id := range r.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing" // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for per-tenant storage
+)
+
+// TenantRegistryTestSuite defines the test suite for the tenant registry
+type TenantRegistryTestSuite struct {
+	suite.Suite
+	registry *TenantRegistry // Registry under test
+}
+
+// newTenantServices builds the services of one tenant on top of in-memory repositories
+func newTenantServices(packSizes []int) TenantServices {
+	packs := NewCalculatePacksUseCase(repository.NewInMemoryPackRepository(packSizes))
+	return TenantServices{
+		Packs:     packs,
+		Approvals: NewPackSizeApprovalUseCase(repository.NewInMemoryProposalRepository(), packs),
+		Quotes:    NewQuoteUseCase(repository.NewInMemoryQuoteRepository(), packs, 0),
+	}
+}
+
+// SetupTest sets up the test environment before each test
+func (s *TenantRegistryTestSuite) SetupTest() {
+	s.registry = NewTenantRegistry("default")
+	s.Require().NoError(s.registry.Register("default", nil, newTenantServices([]int{250, 500})))
+	s.Require().NoError(s.registry.Register("retail", nil, newTenantServices([]int{10, 20})))
+	s.Require().NoError(s.registry.Register("wholesale", []string{"wk-1"}, newTenantServices([]int{1000, 5000})))
+}
+
+// TestTenantRegistryTestSuite runs the test suite
+func TestTenantRegistryTestSuite(t *testing.T) {
+	suite.Run(t, new(TenantRegistryTestSuite))
+}
+
+// TestResolve tests how requests are mapped to tenants
+func (s *TenantRegistryTestSuite) TestResolve() {
+	cases := []struct {
+		name     string
+		tenantID string
+		apiKey   string
+		expected string
+		err      error
+	}{
+		{name: "NoTenant", expected: "default"},
+		{name: "OpenTenant", tenantID: "retail", expected: "retail"},
+		{name: "APIKey", apiKey: "wk-1", expected: "wholesale"},
+		{name: "APIKeyAndMatchingTenant", tenantID: "wholesale", apiKey: "wk-1", expected: "wholesale"},
+		{name: "APIKeyForOtherTenant", tenantID: "retail", apiKey: "wk-1", err: ErrTenantMismatch},
+		{name: "ProtectedTenantWithoutKey", tenantID: "wholesale", err: ErrAPIKeyRequired},
+		{name: "InvalidKey", apiKey: "nope", err: ErrInvalidAPIKey},
+		{name: "UnknownTenant", tenantID: "nope", err: ErrUnknownTenant},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			tenantID, err := s.registry.Resolve(tc.tenantID, tc.apiKey)
+			s.Assert().Equal(tc.err, err, "Error should match")
+			s.Assert().Equal(tc.expected, tenantID, "Tenant should match")
+		})
+	}
+}
+
+// TestRegisterRejectsDuplicates tests that tenant IDs and API keys are unique
+func (s *TenantRegistryTestSuite) TestRegisterRejectsDuplicates() {
+	s.Assert().Equal(ErrDuplicateTenant, s.registry.Register("retail", nil, TenantServices{}), "Tenant IDs should be unique")
+	s.Assert().Equal(ErrDuplicateTenant, s.registry.Register("other", []string{"wk-1"}, TenantServices{}), "API keys should be unique")
+	s.Assert().Equal([]string{"default", "retail", "wholesale"}, s.registry.Tenants(), "Failed registrations should not be kept")
+}
+
+// TestIsolation tests that one tenant's changes are invisible to another
+func (s *TenantRegistryTestSuite) TestIsolation() {
+	retail, err := s.registry.Services("retail")
+	s.Require().NoError(err, "Expected no error")
+	wholesale, err := s.registry.Services("wholesale")
+	s.Require().NoError(err, "Expected no error")
+
+	_, err = retail.Packs.UpdatePackSizes([]int{5, 15}, "alice")
+	s.Require().NoError(err, "Expected no error")
+
+	sizes, err := wholesale.Packs.GetPackSizes()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{1000, 5000}, sizes, "Other tenants should keep their sizes")
+
+	versions, err := wholesale.Packs.ListPackSizeVersions()
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Len(versions, 1, "Other tenants should not see the new version")
+
+	quote, err := retail.Quotes.CreateQuote(20, "alice")
+	s.Require().NoError(err, "Expected no error")
+	_, err = wholesale.Quotes.GetQuote(quote.ID)
+	s.Assert().Equal(repository.ErrQuoteNotFound, err, "Quotes should not be shared between tenants")
+}
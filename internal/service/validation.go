@@ -0,0 +1,86 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"fmt"     // Import fmt for field names and messages
+	"sort"    // Import sort for normalising pack sizes
+	"strings" // Import strings for joining messages
+)
+
+const (
+	// DefaultMaxPackSizes is how many distinct pack sizes a set may contain when no limit is configured
+	DefaultMaxPackSizes = 20
+	// DefaultMaxPackSize is the largest allowed pack size when no limit is configured
+	DefaultMaxPackSize = 1000000
+)
+
+// FieldError describes why a single request field is invalid
+type FieldError struct {
+	Field   string `json:"field"`   // Field path, e.g. "packSizes" or "packSizes[2]"
+	Message string `json:"message"` // Human-readable reason
+}
+
+// ValidationError is returned when input fails validation; it carries one entry per invalid field
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error joins the field errors into a single message
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// PackSizeRules constrains the pack-size sets that may be published
+type PackSizeRules struct {
+	MaxSizes int // Maximum number of distinct pack sizes
+	MaxSize  int // Largest allowed pack size
+}
+
+// DefaultPackSizeRules is used when no rules are configured
+var DefaultPackSizeRules = PackSizeRules{MaxSizes: DefaultMaxPackSizes, MaxSize: DefaultMaxPackSize}
+
+// Validate checks a pack-size set and returns it normalised: sorted ascending with duplicates removed.
+// Empty sets, non-positive sizes, sizes above MaxSize and sets with more than MaxSizes sizes are rejected.
+func (r PackSizeRules) Validate(packSizes []int) ([]int, error) {
+	var fields []FieldError
+	if len(packSizes) == 0 {
+		fields = append(fields, FieldError{Field: "packSizes", Message: "at least one pack size is required"})
+	}
+	for i, size := range packSizes { // Report every bad entry so callers can fix them in one go
+		field := fmt.Sprintf("packSizes[%d]", i)
+		switch {
+		case size <= 0:
+			fields = append(fields, FieldError{Field: field, Message: "must be positive"})
+		case r.MaxSize > 0 && size > r.MaxSize:
+			fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf("must not exceed %d", r.MaxSize)})
+		}
+	}
+
+	normalised := normalisePackSizes(packSizes)
+	if r.MaxSizes > 0 && len(normalised) > r.MaxSizes {
+		fields = append(fields, FieldError{Field: "packSizes", Message: fmt.Sprintf("must not contain more than %d distinct sizes", r.MaxSizes)})
+	}
+
+	if len(fields) > 0 {
+		return nil, &ValidationError{Fields: fields}
+	}
+	return normalised, nil
+}
+
+// normalisePackSizes returns a sorted copy of the sizes without duplicates
+func normalisePackSizes(packSizes []int) []int {
+	sorted := append([]int(nil), packSizes...)
+	sort.Ints(sorted)
+
+	normalised := make([]int, 0, len(sorted))
+	for i, size := range sorted {
+		if i > 0 && size == sorted[i-1] { // Duplicates add nothing to the solution
+			continue
+		}
+		normalised = append(normalised, size)
+	}
+	return normalised
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing" // Import the testing package for writing unit tests
+
+	"github.com/stretchr/testify/suite" // Import testify/suite for test suites
+)
+
+// PackSizeRulesTestSuite defines the test suite for pack-size validation
+type PackSizeRulesTestSuite struct {
+	suite.Suite
+	rules PackSizeRules // Rules under test
+}
+
+// SetupTest sets up the test environment before each test
+func (s *PackSizeRulesTestSuite) SetupTest() {
+	s.rules = PackSizeRules{MaxSizes: 3, MaxSize: 1000}
+}
+
+// TestPackSizeRulesTestSuite runs the test suite
+func TestPackSizeRulesTestSuite(t *testing.T) {
+	suite.Run(t, new(PackSizeRulesTestSuite))
+}
+
+// TestValidateNormalises tests that valid sets are sorted and deduplicated
+func (s *PackSizeRulesTestSuite) TestValidateNormalises() {
+	sizes, err := s.rules.Validate([]int{500, 250, 500, 1000})
+	s.Assert().NoError(err, "Expected no error")
+	s.Assert().Equal([]int{250, 500, 1000}, sizes, "Sizes should be sorted without duplicates")
+}
+
+// TestValidateRejects tests the field errors for invalid sets
+func (s *PackSizeRulesTestSuite) TestValidateRejects() {
+	cases := []struct {
+		name   string
+		sizes  []int
+		fields []FieldError
+	}{
+		{
+			name:   "Empty",
+			sizes:  nil,
+			fields: []FieldError{{Field: "packSizes", Message: "at least one pack size is required"}},
+		},
+		{
+			name:  "NonPositive",
+			sizes: []int{250, 0, -5},
+			fields: []FieldError{
+				{Field: "packSizes[1]", Message: "must be positive"},
+				{Field: "packSizes[2]", Message: "must be positive"},
+			},
+		},
+		{
+			name:   "AboveMax",
+			sizes:  []int{250, 5000},
+			fields: []FieldError{{Field: "packSizes[1]", Message: "must not exceed 1000"}},
+		},
+		{
+			name:   "TooMany",
+			sizes:  []int{1, 2, 3, 4, 4},
+			fields: []FieldError{{Field: "packSizes", Message: "must not contain more than 3 distinct sizes"}},
+		},
+	}
+	for _, tc := range cases {
+		s.Run(tc.name, func() {
+			sizes, err := s.rules.Validate(tc.sizes)
+			s.Assert().Nil(sizes, "Invalid sets should not be returned")
+			s.Require().IsType(&ValidationError{}, err, "Expected a validation error")
+			s.Assert().Equal(tc.fields, err.(*ValidationError).Fields, "Field errors should match")
+		})
+	}
+}
+
+// TestValidationErrorMessage tests the combined error message
+func (s *PackSizeRulesTestSuite) TestValidationErrorMessage() {
+	_, err := s.rules.Validate([]int{0})
+	s.Assert().EqualError(err, "validation failed: packSizes[0]: must be positive", "Message should list the fields")
+}
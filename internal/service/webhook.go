@@ -0,0 +1,273 @@
+package service // Define the package name as "service" for the service layer (application logic)
+
+import (
+	"context"      // Import context for stopping deliveries
+	"crypto/rand"  // Import crypto/rand for webhook secrets
+	"encoding/hex" // Import hex for encoding webhook secrets
+	"errors"       // Import errors for webhook errors
+	"net/url"      // Import net/url for validating webhook URLs
+	"sync"         // Import sync for guarding the delivery queues
+	"time"         // Import time for retry backoff
+
+	"order-packs-calculator/internal/domain"                    // Import the domain package for events and subscriptions
+	"order-packs-calculator/internal/infrastructure/events"     // Import the events package for publishers and senders
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for subscription storage
+)
+
+var (
+	// ErrInvalidWebhookURL is returned when a webhook URL is not an absolute http(s) URL
+	ErrInvalidWebhookURL = errors.New("webhook URL must be an absolute http or https URL")
+	// ErrNoWebhookEvents is returned when a webhook is registered without event types
+	ErrNoWebhookEvents = errors.New("at least one event type is required")
+	// ErrWebhooksStopped is returned when events are published after deliveries were stopped
+	ErrWebhooksStopped = errors.New("webhook deliveries have been stopped")
+)
+
+// WebhookQueueSize is how many events may wait for delivery to one subscription; further events are
+// dropped, and logged as failed deliveries, until the receiver catches up
+const WebhookQueueSize = 100
+
+// MaxWebhookBackoff is the longest wait between two delivery attempts, however many have failed
+const MaxWebhookBackoff = time.Hour
+
+// WebhookRetryPolicy controls how often and how patiently failed deliveries are retried
+type WebhookRetryPolicy struct {
+	MaxAttempts int           // Total attempts per event, including the first
+	Backoff     time.Duration // Delay before the first retry; it doubles for every further retry, up to MaxWebhookBackoff
+}
+
+// Delay returns how long to wait after the given failed attempt
+func (p WebhookRetryPolicy) Delay(attempt int) time.Duration {
+	// Exponential backoff: Backoff, 2*Backoff, 4*Backoff, ... Doubling stops at the cap, so many attempts cannot overflow
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < MaxWebhookBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, MaxWebhookBackoff)
+}
+
+// DefaultWebhookRetryPolicy is used when no retry policy is configured
+var DefaultWebhookRetryPolicy = WebhookRetryPolicy{MaxAttempts: 5, Backoff: time.Second}
+
+// WebhookService defines the interface for the WebhookUseCase
+type WebhookService interface {
+	RegisterWebhook(url string, eventTypes []string, author string) (domain.WebhookSubscription, error)
+	UnregisterWebhook(id int) error
+	ListWebhooks() ([]domain.WebhookSubscription, error)
+	ListWebhookDeliveries(id int) ([]domain.WebhookDelivery, error)
+}
+
+// WebhookUseCase manages webhook subscriptions and delivers events to them
+type WebhookUseCase struct {
+	webhooks repository.WebhookRepository // Repository for subscriptions and the delivery log
+	sender   events.WebhookSender         // Sender that posts signed payloads
+	retry    WebhookRetryPolicy           // Retry policy for failed deliveries
+
+	now   func() time.Time                     // Clock used for subscriptions and the delivery log
+	after func(time.Duration) <-chan time.Time // Waits between retries
+
+	mu       sync.Mutex              // Guards queues and stopped
+	queues   map[int]chan webhookJob // Events waiting for each subscription that has a worker
+	stopped  bool                    // Whether Run's context is done
+	stop     chan struct{}           // Closed when Run's context is done, ending workers and their retries
+	inFlight sync.WaitGroup          // Workers that have not exited yet
+}
+
+// webhookJob is an event waiting for delivery to a subscription
+type webhookJob struct {
+	subscription domain.WebhookSubscription
+	event        domain.EventEnvelope
+}
+
+// Ensure WebhookUseCase implements WebhookService and can receive events
+var (
+	_ WebhookService   = (*WebhookUseCase)(nil)
+	_ events.Publisher = (*WebhookUseCase)(nil)
+)
+
+// NewWebhookUseCase creates a new instance of WebhookUseCase
+func NewWebhookUseCase(webhooks repository.WebhookRepository, sender events.WebhookSender, retry WebhookRetryPolicy) *WebhookUseCase {
+	if retry.MaxAttempts <= 0 || retry.Backoff <= 0 { // Fall back to the default for missing or invalid policies
+		retry = DefaultWebhookRetryPolicy
+	}
+	return &WebhookUseCase{
+		webhooks: webhooks,   // Initialize the webhook repository
+		sender:   sender,     // Initialize the sender
+		retry:    retry,      // Initialize the retry policy
+		now:      time.Now,   // Use the wall clock
+		after:    time.After, // Really wait between retries
+		queues:   map[int]chan webhookJob{},
+		stop:     make(chan struct{}),
+	}
+}
+
+// RegisterWebhook subscribes a URL to the given event types; the returned secret signs every delivery
+func (uc *WebhookUseCase) RegisterWebhook(rawURL string, eventTypes []string, author string) (domain.WebhookSubscription, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.WebhookSubscription{}, ErrInvalidWebhookURL
+	}
+	if len(eventTypes) == 0 {
+		return domain.WebhookSubscription{}, ErrNoWebhookEvents
+	}
+
+	types := make([]domain.EventType, 0, len(eventTypes))
+	for _, name := range eventTypes { // Reject typos instead of silently never delivering
+		eventType, err := domain.ParseEventType(name)
+		if err != nil {
+			return domain.WebhookSubscription{}, err
+		}
+		types = append(types, eventType)
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	return uc.webhooks.CreateSubscription(domain.WebhookSubscription{
+		URL:        rawURL,
+		EventTypes: types,
+		Secret:     secret,
+		CreatedBy:  author,
+		CreatedAt:  uc.now(),
+	})
+}
+
+// UnregisterWebhook removes a subscription; events already queued for it are still delivered
+func (uc *WebhookUseCase) UnregisterWebhook(id int) error {
+	return uc.webhooks.DeleteSubscription(id)
+}
+
+// ListWebhooks retrieves every subscription without its secret
+func (uc *WebhookUseCase) ListWebhooks() ([]domain.WebhookSubscription, error) {
+	subscriptions, err := uc.webhooks.ListSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions { // Secrets are only shown once, at registration
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// ListWebhookDeliveries retrieves the delivery log of a subscription
+func (uc *WebhookUseCase) ListWebhookDeliveries(id int) ([]domain.WebhookDelivery, error) {
+	return uc.webhooks.ListDeliveries(id)
+}
+
+// Publish queues the event for every matching subscription, so slow receivers do not hold up the
+// outbox. Each subscription has one worker delivering its events in order, which exits once its queue
+// is empty; failures, including events dropped from a full queue, are recorded in the delivery log
+func (uc *WebhookUseCase) Publish(event domain.EventEnvelope) error {
+	subscriptions, err := uc.webhooks.ListSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.stopped { // Fail, so the event stays in the outbox
+		return ErrWebhooksStopped
+	}
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(event.Type) {
+			continue
+		}
+		queue, ok := uc.queues[subscription.ID]
+		if !ok { // Start a worker for the subscription
+			queue = make(chan webhookJob, WebhookQueueSize)
+			uc.queues[subscription.ID] = queue
+			uc.inFlight.Add(1)
+			go uc.work(subscription.ID, queue)
+		}
+		select {
+		case queue <- webhookJob{subscription: subscription, event: event}:
+		default: // The receiver is too far behind; drop the event rather than hold up the others
+			uc.logDelivery(subscription, event, 0, 0, errors.New("delivery queue is full"))
+		}
+	}
+	return nil
+}
+
+// Run stops deliveries once ctx is done: queued events are dropped, waiting retries are abandoned and
+// further events are refused
+func (uc *WebhookUseCase) Run(ctx context.Context) {
+	<-ctx.Done()
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if !uc.stopped {
+		uc.stopped = true
+		close(uc.stop)
+	}
+}
+
+// Wait blocks until every event queued so far has been delivered, or given up on
+func (uc *WebhookUseCase) Wait() {
+	uc.inFlight.Wait()
+}
+
+// work delivers the events queued for a subscription until the queue is empty or deliveries stop
+func (uc *WebhookUseCase) work(id int, queue chan webhookJob) {
+	defer uc.inFlight.Done()
+	for {
+		select {
+		case <-uc.stop: // Checked first, as a select picks at random among the ready cases
+			return
+		default:
+		}
+		job := <-queue // Ready: workers are started for an event and exit once their queue is empty
+		uc.deliver(job.subscription, job.event)
+
+		uc.mu.Lock()
+		if len(queue) == 0 { // Publish queues under the lock, so nothing can arrive between the check and the exit
+			delete(uc.queues, id)
+			uc.mu.Unlock()
+			return
+		}
+		uc.mu.Unlock()
+	}
+}
+
+// deliver sends an event to one subscription, retrying with exponential backoff until deliveries stop
+func (uc *WebhookUseCase) deliver(subscription domain.WebhookSubscription, event domain.EventEnvelope) {
+	for attempt := 1; attempt <= uc.retry.MaxAttempts; attempt++ {
+		status, err := uc.sender.Send(subscription.URL, subscription.Secret, event)
+		uc.logDelivery(subscription, event, attempt, status, err)
+
+		if err == nil || attempt == uc.retry.MaxAttempts {
+			return
+		}
+		select {
+		case <-uc.stop:
+			return
+		case <-uc.after(uc.retry.Delay(attempt)):
+		}
+	}
+}
+
+// logDelivery records an attempt to deliver an event; attempt 0 means the event was never sent
+func (uc *WebhookUseCase) logDelivery(subscription domain.WebhookSubscription, event domain.EventEnvelope, attempt, status int, err error) {
+	delivery := domain.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Attempt:        attempt,
+		StatusCode:     status,
+		Succeeded:      err == nil,
+		AttemptedAt:    uc.now(),
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	uc.webhooks.SaveDelivery(delivery) // The in-memory log cannot fail; a lost entry must not stop retries
+}
+
+// newWebhookSecret returns a random, hex-encoded HMAC key
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
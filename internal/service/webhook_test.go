@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"           // Import context for stopping deliveries
+	"net/http"          // Import net/http for the test receiver
+	"net/http/httptest" // Import httptest for a local webhook receiver
+	"sync"              // Import sync for counting receiver calls
+	"testing"           // Import the testing package for writing unit tests
+	"time"              // Import time for backoff durations
+
+	"github.com/stretchr/testify/suite"                         // Import testify/suite for test suites
+	"order-packs-calculator/internal/domain"                    // Import the domain package for events
+	"order-packs-calculator/internal/infrastructure/events"     // Import the events package for the HTTP sender
+	"order-packs-calculator/internal/infrastructure/repository" // Import the repository package for subscription storage
+)
+
+// WebhookUseCaseTestSuite defines the test suite for webhook delivery
+type WebhookUseCaseTestSuite struct {
+	suite.Suite
+	uc      *WebhookUseCase  // Use case under test
+	server  *httptest.Server // Local webhook receiver
+	mu      sync.Mutex       // Guards the receiver state
+	calls   int              // Requests the receiver got
+	failFor int              // Number of requests the receiver rejects before accepting
+	slept   []time.Duration  // Backoff delays the use case waited for; only read once deliveries are done
+}
+
+// SetupTest sets up the test environment before each test
+func (s *WebhookUseCaseTestSuite) SetupTest() {
+	s.calls, s.failFor, s.slept = 0, 0, nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.calls++
+		if s.calls <= s.failFor {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	s.uc = NewWebhookUseCase(repository.NewInMemoryWebhookRepository(), events.NewHTTPWebhookSender(time.Second),
+		WebhookRetryPolicy{MaxAttempts: 3, Backoff: 100 * time.Millisecond})
+	s.uc.after = func(d time.Duration) <-chan time.Time { // Record instead of waiting
+		s.slept = append(s.slept, d)
+		ready := make(chan time.Time, 1)
+		ready <- time.Time{}
+		return ready
+	}
+}
+
+// TearDownTest cleans up the test environment after each test
+func (s *WebhookUseCaseTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+// TestWebhookUseCaseTestSuite runs the test suite
+func TestWebhookUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookUseCaseTestSuite))
+}
+
+// TestRegisterWebhookValidation tests that invalid subscriptions are rejected
+func (s *WebhookUseCaseTestSuite) TestRegisterWebhookValidation() {
+	_, err := s.uc.RegisterWebhook("ftp://example.com", []string{"pack_sizes.updated"}, "alice")
+	s.Assert().Equal(ErrInvalidWebhookURL, err, "Non-HTTP URLs should be rejected")
+
+	_, err = s.uc.RegisterWebhook("/relative", []string{"pack_sizes.updated"}, "alice")
+	s.Assert().Equal(ErrInvalidWebhookURL, err, "Relative URLs should be rejected")
+
+	_, err = s.uc.RegisterWebhook(s.server.URL, nil, "alice")
+	s.Assert().Equal(ErrNoWebhookEvents, err, "Event types should be required")
+
+	_, err = s.uc.RegisterWebhook(s.server.URL, []string{"pack_sizes.deleted"}, "alice")
+	s.Assert().Equal(domain.ErrUnknownEventType, err, "Unknown event types should be rejected")
+}
+
+// TestRegisterAndUnregister tests the subscription lifecycle and that secrets are only shown once
+func (s *WebhookUseCaseTestSuite) TestRegisterAndUnregister() {
+	subscription, err := s.uc.RegisterWebhook(s.server.URL, []string{"pack_sizes.updated"}, "alice")
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Len(subscription.Secret, 64, "A secret should be generated")
+	s.Assert().Equal("alice", subscription.CreatedBy, "Author should be recorded")
+
+	listed, err := s.uc.ListWebhooks()
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(listed, 1, "Subscription should be listed")
+	s.Assert().Empty(listed[0].Secret, "Secret should not be listed")
+
+	s.Require().NoError(s.uc.UnregisterWebhook(subscription.ID), "Expected no error")
+	s.Assert().Equal(repository.ErrWebhookNotFound, s.uc.UnregisterWebhook(subscription.ID), "Subscription should be gone")
+}
+
+// TestPublishDeliversMatchingEvents tests that only subscribed event types are delivered
+func (s *WebhookUseCaseTestSuite) TestPublishDeliversMatchingEvents() {
+	subscription, err := s.uc.RegisterWebhook(s.server.URL, []string{"pack_sizes.updated"}, "alice")
+	s.Require().NoError(err, "Expected no error")
+
+	s.Require().NoError(s.uc.Publish(domain.EventEnvelope{ID: 1, Type: domain.EventCalculationPerformed}))
+	s.Require().NoError(s.uc.Publish(domain.EventEnvelope{ID: 2, Type: domain.EventPackSizesUpdated}))
+	s.uc.Wait()
+
+	deliveries, err := s.uc.ListWebhookDeliveries(subscription.ID)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(deliveries, 1, "Only the subscribed event should be delivered")
+	s.Assert().Equal(2, deliveries[0].EventID, "Delivered event should match")
+	s.Assert().True(deliveries[0].Succeeded, "Delivery should succeed")
+	s.Assert().Equal(http.StatusNoContent, deliveries[0].StatusCode, "Receiver status should be logged")
+}
+
+// TestPublishRetriesWithBackoff tests that failed deliveries are retried with exponential backoff
+func (s *WebhookUseCaseTestSuite) TestPublishRetriesWithBackoff() {
+	s.failFor = 2
+	subscription, err := s.uc.RegisterWebhook(s.server.URL, []string{"pack_sizes.updated"}, "alice")
+	s.Require().NoError(err, "Expected no error")
+
+	s.Require().NoError(s.uc.Publish(domain.EventEnvelope{ID: 1, Type: domain.EventPackSizesUpdated}))
+	s.uc.Wait()
+
+	deliveries, err := s.uc.ListWebhookDeliveries(subscription.ID)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(deliveries, 3, "Every attempt should be logged")
+	s.Assert().False(deliveries[0].Succeeded, "First attempt should fail")
+	s.Assert().Equal(http.StatusInternalServerError, deliveries[0].StatusCode, "Failure status should be logged")
+	s.Assert().NotEmpty(deliveries[0].Error, "Failure reason should be logged")
+	s.Assert().True(deliveries[2].Succeeded, "Third attempt should succeed")
+	s.Assert().Equal([]time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, s.slept, "Backoff should double")
+}
+
+// TestDelayIsCapped tests that the backoff stops growing at the cap instead of overflowing
+func (s *WebhookUseCaseTestSuite) TestDelayIsCapped() {
+	policy := WebhookRetryPolicy{MaxAttempts: 100, Backoff: time.Second}
+	s.Assert().Equal(time.Second, policy.Delay(1), "First retry should wait the backoff")
+	s.Assert().Equal(8*time.Second, policy.Delay(4), "Backoff should double")
+	s.Assert().Equal(MaxWebhookBackoff, policy.Delay(13), "Backoff should stop at the cap")
+	s.Assert().Equal(MaxWebhookBackoff, policy.Delay(64), "Many attempts should not overflow")
+	s.Assert().Equal(MaxWebhookBackoff, policy.Delay(100), "Many attempts should not overflow")
+	s.Assert().Equal(MaxWebhookBackoff, WebhookRetryPolicy{Backoff: 2 * time.Hour}.Delay(1), "A longer backoff should be capped")
+}
+
+// TestPublishGivesUp tests that delivery stops after the configured attempts
+func (s *WebhookUseCaseTestSuite) TestPublishGivesUp() {
+	s.failFor = 10
+	subscription, err := s.uc.RegisterWebhook(s.server.URL, []string{"pack_sizes.updated"}, "alice")
+	s.Require().NoError(err, "Expected no error")
+
+	s.Require().NoError(s.uc.Publish(domain.EventEnvelope{ID: 1, Type: domain.EventPackSizesUpdated}))
+	s.uc.Wait()
+
+	deliveries, err := s.uc.ListWebhookDeliveries(subscription.ID)
+	s.Require().NoError(err, "Expected no error")
+	s.Assert().Len(deliveries, 3, "Delivery should stop after the maximum attempts")
+	s.Assert().Equal(3, s.calls, "Receiver should be called once per attempt")
+}
+
+// TestQueueIsBoundedAndStops tests that events for a stuck receiver are dropped once its queue is full
+// and that stopping ends the worker and refuses further events
+func (s *WebhookUseCaseTestSuite) TestQueueIsBoundedAndStops() {
+	s.failFor = 1000
+	s.uc.after = func(time.Duration) <-chan time.Time { return nil } // Retries wait until deliveries stop
+	subscription, err := s.uc.RegisterWebhook(s.server.URL, []string{"pack_sizes.updated"}, "alice")
+	s.Require().NoError(err, "Expected no error")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		s.uc.Run(ctx)
+		close(stopped)
+	}()
+
+	// The first event is taken off the queue and waits for its retry; the queue then fills up behind it
+	s.Require().NoError(s.uc.Publish(domain.EventEnvelope{ID: 1, Type: domain.EventPackSizesUpdated}))
+	s.Require().Eventually(func() bool {
+		deliveries, _ := s.uc.ListWebhookDeliveries(subscription.ID)
+		return len(deliveries) == 1
+	}, time.Second, 10*time.Millisecond, "First attempt should be made")
+	for id := 2; id <= WebhookQueueSize+2; id++ {
+		s.Require().NoError(s.uc.Publish(domain.EventEnvelope{ID: id, Type: domain.EventPackSizesUpdated}))
+	}
+	deliveries, err := s.uc.ListWebhookDeliveries(subscription.ID)
+	s.Require().NoError(err, "Expected no error")
+	s.Require().Len(deliveries, 2, "Only the dropped event should be logged besides the first attempt")
+	s.Assert().Equal(WebhookQueueSize+2, deliveries[1].EventID, "Event beyond the queue size should be dropped")
+	s.Assert().Equal(0, deliveries[1].Attempt, "Dropped event should not be sent")
+	s.Assert().Equal("delivery queue is full", deliveries[1].Error, "Drop should be explained")
+
+	cancel()
+	<-stopped
+	s.uc.Wait() // Returns once the worker has given up on its retry
+	s.Assert().Equal(ErrWebhooksStopped, s.uc.Publish(domain.EventEnvelope{ID: 200, Type: domain.EventPackSizesUpdated}), "Events should be refused once stopped")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Assert().Equal(1, s.calls, "Queued events should not be sent once stopped")
+}
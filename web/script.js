@@ -1,9 +1,13 @@
 
 const apiurl = 'http://127.0.0.1:3000';
 
+// ETag of the pack sizes shown in the form; updates are only accepted on top of this version
+let packSizesETag = null;
+
 async function loadPackSizes() {
     const response = await fetch(apiurl+'/api/pack-sizes');
     const data = await response.json();
+    packSizesETag = response.headers.get('ETag');
     document.getElementById('packSizes').value = data.packSizes.join('\n');
 }
 
@@ -12,11 +16,20 @@ async function updatePackSizes() {
     const packSizes = packSizesText.split('\n').map(Number).filter(n => n > 0);
     const response = await fetch(apiurl+'/api/pack-sizes', {
         method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', 'If-Match': packSizesETag },
         body: JSON.stringify({ packSizes })
     });
     const result = await response.json();
-    alert(result.message || result.error);
+    if (response.status === 412) {
+        alert('Someone else changed the pack sizes since you loaded them. The current pack sizes will be loaded; apply your change again.');
+        await loadPackSizes();
+        return;
+    }
+    if (response.ok) {
+        packSizesETag = response.headers.get('ETag');
+    }
+    const fieldErrors = (result.fields || []).map(f => `${f.field}: ${f.message}`);
+    alert(result.message || [result.error, ...fieldErrors].join('\n'));
 }
 
 async function calculatePacks() {